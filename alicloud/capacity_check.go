@@ -0,0 +1,581 @@
+package alicloud
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// capacityQuota is a quota of the Quotas service that planned capacity is checked against.
+type capacityQuota struct {
+	Kind            string
+	Name            string
+	ProductCode     string
+	QuotaActionCode string
+}
+
+// capacityQuotas are the quotas checked by default. A quota the account does not report is skipped, and
+// features.capacity_check can name another quota action code for each kind.
+var capacityQuotas = []capacityQuota{
+	{Kind: "vcpu", Name: "vCPUs of pay-as-you-go instances", ProductCode: "ecs", QuotaActionCode: "q_elastic-vcpu-upper-limit"},
+	{Kind: "eip", Name: "EIPs", ProductCode: "vpc", QuotaActionCode: "vpc_quota_eip_count"},
+	{Kind: "disk", Name: "GiB of pay-as-you-go disks", ProductCode: "ecs", QuotaActionCode: "q_postpaid-yundisk-capacity"},
+}
+
+// capacityUsage is the capacity one side of a resource's diff occupies.
+type capacityUsage struct {
+	InstanceType string
+	ZoneId       string
+	// VSwitchIds are the vSwitches instances are spread over when there is no single zone, as in node pools and
+	// scaling groups. Their zones are looked up for the stock check.
+	VSwitchIds []string
+	// ScalingGroup reports whether the instance type is the one of the active scaling configuration of the group.
+	ScalingGroup bool
+	Instances    int
+	// PostPaid reports whether the instances are pay-as-you-go, which is what the vCPU quota limits.
+	PostPaid bool
+	// DiskGiB is the size of all pay-as-you-go disks.
+	DiskGiB int
+	Eips    int
+}
+
+// capacityUsageFunc reads the capacity of a resource through get, which returns the old or the new value of a key.
+type capacityUsageFunc func(get func(key string) interface{}) capacityUsage
+
+// capacityCheckResources are the resources whose planned capacity is added up.
+var capacityCheckResources = map[string]capacityUsageFunc{
+	"alicloud_instance":                capacityUsageOfInstance("availability_zone", "", "size"),
+	"alicloud_ecs_instance_set":        capacityUsageOfInstance("zone_id", "amount", "disk_size"),
+	"alicloud_cs_kubernetes_node_pool": capacityUsageOfNodePool,
+	"alicloud_ess_scaling_group":       capacityUsageOfScalingGroup,
+	"alicloud_ecs_disk":                capacityUsageOfDisk,
+	"alicloud_disk":                    capacityUsageOfDisk,
+	"alicloud_eip_address":             capacityUsageOfEip,
+	"alicloud_eip":                     capacityUsageOfEip,
+}
+
+func capacityUsageOfInstance(zoneKey, amountKey, dataDiskSizeKey string) capacityUsageFunc {
+	return func(get func(key string) interface{}) capacityUsage {
+		usage := capacityUsage{
+			InstanceType: fmt.Sprint(get("instance_type")),
+			ZoneId:       fmt.Sprint(get(zoneKey)),
+			Instances:    1,
+			PostPaid:     get("instance_charge_type") != string(PrePaid),
+		}
+		if amountKey != "" {
+			usage.Instances, _ = get(amountKey).(int)
+		}
+		if usage.PostPaid {
+			usage.DiskGiB = capacityDiskGiB(get("system_disk_size"), get("data_disks"), dataDiskSizeKey) * usage.Instances
+		}
+		return usage
+	}
+}
+
+func capacityUsageOfNodePool(get func(key string) interface{}) capacityUsage {
+	usage := capacityUsage{
+		PostPaid: get("instance_charge_type") != string(PrePaid),
+	}
+	if instanceTypes, ok := get("instance_types").([]interface{}); ok && len(instanceTypes) > 0 {
+		usage.InstanceType = fmt.Sprint(instanceTypes[0])
+	}
+	if desiredSize, ok := get("desired_size").(string); ok && desiredSize != "" {
+		usage.Instances, _ = strconv.Atoi(desiredSize)
+	} else {
+		usage.Instances, _ = get("node_count").(int)
+	}
+	usage.VSwitchIds = capacityStrings(get("vswitch_ids"))
+	if usage.PostPaid {
+		usage.DiskGiB = capacityDiskGiB(get("system_disk_size"), get("data_disks"), "size") * usage.Instances
+	}
+	return usage
+}
+
+// capacityUsageOfScalingGroup counts the instances a scaling group keeps: its desired capacity, or its minimum size
+// without one, and the instances of its warm pool. Their type is the one of the group's active scaling configuration,
+// which is only known once the group exists.
+func capacityUsageOfScalingGroup(get func(key string) interface{}) capacityUsage {
+	usage := capacityUsage{
+		ScalingGroup: true,
+		PostPaid:     true,
+	}
+	if desiredCapacity, ok := get("desired_capacity").(int); ok && desiredCapacity > 0 {
+		usage.Instances = desiredCapacity
+	} else {
+		usage.Instances, _ = get("min_size").(int)
+	}
+	if warmPools, ok := get("warm_pool").([]interface{}); ok && len(warmPools) > 0 && warmPools[0] != nil {
+		size, _ := warmPools[0].(map[string]interface{})["size"].(int)
+		usage.Instances += size
+	}
+	usage.VSwitchIds = capacityStrings(get("vswitch_ids"))
+	if vswitchId, ok := get("vswitch_id").(string); ok && vswitchId != "" && len(usage.VSwitchIds) == 0 {
+		usage.VSwitchIds = []string{vswitchId}
+	}
+	return usage
+}
+
+func capacityStrings(v interface{}) []string {
+	if set, ok := v.(*schema.Set); ok {
+		v = set.List()
+	}
+	var values []string
+	if list, ok := v.([]interface{}); ok {
+		for _, value := range list {
+			if value != nil && fmt.Sprint(value) != "" {
+				values = append(values, fmt.Sprint(value))
+			}
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+func capacityUsageOfDisk(get func(key string) interface{}) capacityUsage {
+	usage := capacityUsage{}
+	if get("payment_type") != "Subscription" {
+		usage.DiskGiB, _ = get("size").(int)
+	}
+	return usage
+}
+
+func capacityUsageOfEip(get func(key string) interface{}) capacityUsage {
+	return capacityUsage{Eips: 1}
+}
+
+func capacityDiskGiB(systemDiskSize, dataDisks interface{}, sizeKey string) int {
+	total, _ := systemDiskSize.(int)
+	if disks, ok := dataDisks.([]interface{}); ok {
+		for _, disk := range disks {
+			if diskMap, ok := disk.(map[string]interface{}); ok {
+				size, _ := diskMap[sizeKey].(int)
+				total += size
+			}
+		}
+	}
+	return total
+}
+
+// capacityDemand is what a planned resource adds to the capacity of its region: the change of every quota kind,
+// and the instances it asks for in one of its zones.
+type capacityDemand struct {
+	Quotas       map[string]float64
+	InstanceType string
+	ZoneIds      []string
+	ChargeType   string
+	Instances    int
+}
+
+// capacityPlan adds up the demands of the resources planned in a region during a run, keyed by capacityPlanKey.
+type capacityPlan struct {
+	mutex   sync.Mutex
+	demands map[string]capacityDemand
+	// diffs counts the diffs of the new resources planned with a key, and instances the resources they stand for.
+	diffs     map[string]int
+	instances map[string]int
+}
+
+func newCapacityPlan() *capacityPlan {
+	return &capacityPlan{
+		demands:   make(map[string]capacityDemand),
+		diffs:     make(map[string]int),
+		instances: make(map[string]int),
+	}
+}
+
+// add records the demand of a diff keyed by key, and returns the demands of the whole plan, one for every resource.
+// The diff of a new resource runs passes times, so resources planned with the very same arguments share the key and
+// count once for every passes diffs of it.
+func (plan *capacityPlan) add(key string, demand capacityDemand, newResource bool, passes int) []capacityDemand {
+	plan.mutex.Lock()
+	defer plan.mutex.Unlock()
+	plan.demands[key] = demand
+	plan.instances[key] = 1
+	if newResource {
+		plan.diffs[key]++
+		plan.instances[key] = (plan.diffs[key] + passes - 1) / passes
+	}
+	demands := make([]capacityDemand, 0, len(plan.demands))
+	for k, d := range plan.demands {
+		for i := 0; i < plan.instances[k]; i++ {
+			demands = append(demands, d)
+		}
+	}
+	return demands
+}
+
+var capacityPlans sync.Map
+
+// capacityLookups caches what the capacity check reads from the APIs during a run: instance type cores, quotas and
+// stock. The usage of a quota is read once, so resources created by the run are not counted twice when it is
+// planned again during apply.
+var capacityLookups sync.Map
+
+type capacityLookupEntry struct {
+	once  sync.Once
+	value interface{}
+	err   error
+}
+
+func capacityLookup(key string, lookup func() (interface{}, error)) (interface{}, error) {
+	v, _ := capacityLookups.LoadOrStore(key, &capacityLookupEntry{})
+	entry := v.(*capacityLookupEntry)
+	entry.once.Do(func() {
+		entry.value, entry.err = lookup()
+	})
+	return entry.value, entry.err
+}
+
+// capacityPlanKey identifies the resource a diff belongs to: an existing resource by its ID, and a new one by the
+// hash of its planned arguments. The passes of the diff of a new resource share the key, and so do the resources
+// planned with the very same arguments, which capacityPlan tells apart by counting the diffs.
+func capacityPlanKey(resourceName string, resourceSchema map[string]*schema.Schema, diff *schema.ResourceDiff) string {
+	if id := diff.Id(); id != "" {
+		return resourceName + ":" + id
+	}
+	keys := make([]string, 0, len(resourceSchema))
+	for key := range resourceSchema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hash := md5.New()
+	for _, key := range keys {
+		if !diff.NewValueKnown(key) {
+			fmt.Fprintf(hash, "%s=?;", key)
+			continue
+		}
+		value := diff.Get(key)
+		if set, ok := value.(*schema.Set); ok {
+			value = set.List()
+		}
+		fmt.Fprintf(hash, "%s=%#v;", key, value)
+	}
+	return resourceName + "#" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// capacityDiffPasses returns how many times the SDK runs the diff of a new resource: twice when an argument that
+// forces a new resource has a value or is computed, as the diff then requires a new resource and is run again.
+func capacityDiffPasses(resourceSchema map[string]*schema.Schema, diff *schema.ResourceDiff) int {
+	for key, s := range resourceSchema {
+		if !capacityForcesNew(s) {
+			continue
+		}
+		if s.Computed || !diff.NewValueKnown(key) || diff.HasChange(key) {
+			return 2
+		}
+	}
+	return 1
+}
+
+func capacityForcesNew(s *schema.Schema) bool {
+	if s.ForceNew {
+		return true
+	}
+	if elem, ok := s.Elem.(*schema.Resource); ok {
+		for _, e := range elem.Schema {
+			if capacityForcesNew(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// capacityReplaced reports whether the diff of an existing resource replaces it. Its new side is then planned again
+// as a new resource, so the diff itself only releases what the resource holds.
+func capacityReplaced(resourceSchema map[string]*schema.Schema, diff *schema.ResourceDiff) bool {
+	if diff.Id() == "" {
+		return false
+	}
+	for key, s := range resourceSchema {
+		if s.ForceNew && diff.HasChange(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func capacityCheckCustomizeDiff(resourceName string, resourceSchema map[string]*schema.Schema, usageFunc capacityUsageFunc, customizeDiff schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return func(diff *schema.ResourceDiff, meta interface{}) error {
+		if customizeDiff != nil {
+			if err := customizeDiff(diff, meta); err != nil {
+				return err
+			}
+		}
+		client, ok := meta.(*connectivity.AliyunClient)
+		if !ok || client == nil || !client.Features.CapacityCheck.Enabled {
+			return nil
+		}
+		key := capacityPlanKey(resourceName, resourceSchema, diff)
+		oldUsage := capacityUsage{}
+		if diff.Id() != "" {
+			oldUsage = usageFunc(func(key string) interface{} {
+				o, _ := diff.GetChange(key)
+				return o
+			})
+		}
+		newUsage := capacityUsage{}
+		if !capacityReplaced(resourceSchema, diff) {
+			newUsage = usageFunc(func(key string) interface{} {
+				if !diff.NewValueKnown(key) {
+					return nil
+				}
+				_, n := diff.GetChange(key)
+				return n
+			})
+		}
+		demand, err := capacityDemandOf(client, diff.Id(), oldUsage, newUsage)
+		if err != nil {
+			log.Printf("[WARN] skip checking the capacity of %s: %v", resourceName, err)
+			return nil
+		}
+
+		v, _ := capacityPlans.LoadOrStore(client.RegionId, newCapacityPlan())
+		demands := v.(*capacityPlan).add(key, demand, diff.Id() == "", capacityDiffPasses(resourceSchema, diff))
+
+		return checkCapacityPlan(client, demand, demands)
+	}
+}
+
+// capacityDemandOf returns what changing the resource id, empty for a new one, from oldUsage to newUsage adds to its
+// region.
+func capacityDemandOf(client *connectivity.AliyunClient, id string, oldUsage, newUsage capacityUsage) (capacityDemand, error) {
+	for _, usage := range []*capacityUsage{&oldUsage, &newUsage} {
+		if usage.ScalingGroup && usage.InstanceType == "" && id != "" {
+			instanceType, err := capacityScalingGroupInstanceType(client, id)
+			if err != nil {
+				return capacityDemand{}, err
+			}
+			usage.InstanceType = instanceType
+		}
+	}
+	demand := capacityDemand{
+		Quotas: map[string]float64{
+			"eip":  float64(newUsage.Eips - oldUsage.Eips),
+			"disk": float64(newUsage.DiskGiB - oldUsage.DiskGiB),
+		},
+	}
+	vcpus := func(usage capacityUsage) (float64, error) {
+		if !usage.PostPaid || usage.Instances <= 0 || usage.InstanceType == "" || usage.InstanceType == "<nil>" {
+			return 0, nil
+		}
+		cores, err := capacityInstanceTypeCores(client, usage.InstanceType)
+		return cores * float64(usage.Instances), err
+	}
+	newVcpus, err := vcpus(newUsage)
+	if err != nil {
+		return demand, err
+	}
+	oldVcpus, err := vcpus(oldUsage)
+	if err != nil {
+		return demand, err
+	}
+	demand.Quotas["vcpu"] = newVcpus - oldVcpus
+
+	// only instances that are not running yet need stock
+	zoneIds, err := capacityZoneIds(client, newUsage)
+	if err != nil {
+		return demand, err
+	}
+	if len(zoneIds) > 0 && newUsage.InstanceType != "" && newUsage.InstanceType != "<nil>" {
+		demand.ZoneIds, demand.InstanceType = zoneIds, newUsage.InstanceType
+		demand.Instances = newUsage.Instances
+		if oldUsage.ZoneId == newUsage.ZoneId && strings.Join(oldUsage.VSwitchIds, ",") == strings.Join(newUsage.VSwitchIds, ",") && oldUsage.InstanceType == newUsage.InstanceType {
+			demand.Instances -= oldUsage.Instances
+		}
+		demand.ChargeType = string(PostPaid)
+		if !newUsage.PostPaid {
+			demand.ChargeType = string(PrePaid)
+		}
+	}
+	return demand, nil
+}
+
+// capacityZoneIds returns the zones the instances of usage may be created in: its zone, or the zones of its vSwitches.
+func capacityZoneIds(client *connectivity.AliyunClient, usage capacityUsage) ([]string, error) {
+	if usage.ZoneId != "" && usage.ZoneId != "<nil>" {
+		return []string{usage.ZoneId}, nil
+	}
+	zoneIds := make([]string, 0, len(usage.VSwitchIds))
+	for _, vswitchId := range usage.VSwitchIds {
+		v, err := capacityLookup("zone:"+vswitchId, func() (interface{}, error) {
+			vpcServiceV2 := VpcServiceV2{client}
+			object, err := vpcServiceV2.DescribeVpcVswitch(vswitchId)
+			if err != nil {
+				return nil, err
+			}
+			return fmt.Sprint(object["ZoneId"]), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if zoneId := v.(string); zoneId != "" && !InArray(zoneId, zoneIds) {
+			zoneIds = append(zoneIds, zoneId)
+		}
+	}
+	return zoneIds, nil
+}
+
+// capacityScalingGroupInstanceType returns the instance type of the active scaling configuration of a scaling group.
+func capacityScalingGroupInstanceType(client *connectivity.AliyunClient, scalingGroupId string) (string, error) {
+	v, err := capacityLookup("scaling_group:"+scalingGroupId, func() (interface{}, error) {
+		essService := EssService{client}
+		group, err := essService.DescribeEssScalingGroup(scalingGroupId)
+		if err != nil {
+			return nil, err
+		}
+		if group.ActiveScalingConfigurationId == "" {
+			return "", nil
+		}
+		config, err := essService.DescribeEssScalingConfiguration(group.ActiveScalingConfigurationId)
+		if err != nil {
+			return nil, err
+		}
+		if config.InstanceType == "" && len(config.InstanceTypes.InstanceType) > 0 {
+			return config.InstanceTypes.InstanceType[0], nil
+		}
+		return config.InstanceType, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+func capacityInstanceTypeCores(client *connectivity.AliyunClient, instanceType string) (float64, error) {
+	v, err := capacityLookup("cores:"+instanceType, func() (interface{}, error) {
+		ecsServiceV2 := EcsServiceV2{client}
+		object, err := ecsServiceV2.DescribeEcsInstanceType(instanceType)
+		if err != nil {
+			return nil, err
+		}
+		return formatFloat64(object["CpuCoreCount"]), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return v.(float64), nil
+}
+
+// checkCapacityPlan checks the demands planned so far in the region of client, and fails when one of them exceeds
+// the headroom of a quota or asks for an instance type that is out of stock. The breakdown lists every quota checked.
+// Only what the demands add is totalled, as what others release may only be freed after the capacity is taken. The
+// totals then only grow as resources are planned, so the last demand that adds to a quota checks the whole plan
+// whatever order the resources are planned in.
+func checkCapacityPlan(client *connectivity.AliyunClient, demand capacityDemand, demands []capacityDemand) error {
+	totals := make(map[string]float64)
+	for _, d := range demands {
+		for kind, amount := range d.Quotas {
+			if amount > 0 {
+				totals[kind] += amount
+			}
+		}
+	}
+
+	lines := make([]string, 0)
+	exceeded := false
+	for _, quota := range capacityQuotas {
+		if demand.Quotas[quota.Kind] <= 0 || totals[quota.Kind] <= 0 {
+			continue
+		}
+		quota.QuotaActionCode = capacityQuotaActionCode(client, quota)
+		object, err := capacityQuotaObject(client, quota)
+		if err != nil {
+			log.Printf("[WARN] skip checking the %s quota %s/%s in %s: %v", quota.Kind, quota.ProductCode, quota.QuotaActionCode, client.RegionId, err)
+			continue
+		}
+		total, used := formatFloat64(object["TotalQuota"]), formatFloat64(object["TotalUsage"])
+		line := fmt.Sprintf("%s (%s/%s): %s planned, %s available of %s (%s used)", quota.Name, quota.ProductCode, quota.QuotaActionCode,
+			formatCapacityAmount(totals[quota.Kind]), formatCapacityAmount(total-used), formatCapacityAmount(total), formatCapacityAmount(used))
+		if totals[quota.Kind] > total-used {
+			line += " - exceeded"
+			exceeded = true
+		}
+		lines = append(lines, line)
+	}
+
+	// instances spread over several zones only need stock in one of them
+	if demand.Instances > 0 {
+		statuses := make([]string, 0, len(demand.ZoneIds))
+		for _, zoneId := range demand.ZoneIds {
+			stock, err := capacityLookup(fmt.Sprintf("stock:%s:%s:%s:%s", client.RegionId, zoneId, demand.InstanceType, demand.ChargeType), func() (interface{}, error) {
+				ecsServiceV2 := EcsServiceV2{client}
+				return ecsServiceV2.DescribeEcsInstanceTypeStock(zoneId, demand.InstanceType, demand.ChargeType)
+			})
+			if err != nil {
+				log.Printf("[WARN] skip checking the stock of %s in %s: %v", demand.InstanceType, zoneId, err)
+				statuses = nil
+				break
+			}
+			status := stock.(string)
+			if status == "WithStock" || status == "ClosedWithStock" {
+				statuses = nil
+				break
+			}
+			if status == "" {
+				status = "not offered"
+			}
+			statuses = append(statuses, status)
+		}
+		if len(statuses) > 0 {
+			zones := strings.Join(demand.ZoneIds, ",")
+			planned := 0
+			for _, d := range demands {
+				if strings.Join(d.ZoneIds, ",") == zones && d.InstanceType == demand.InstanceType && d.Instances > 0 {
+					planned += d.Instances
+				}
+			}
+			if len(demand.ZoneIds) == 1 {
+				lines = append(lines, fmt.Sprintf("instance type %s in zone %s: %d planned, %s - exceeded", demand.InstanceType, demand.ZoneIds[0], planned, statuses[0]))
+			} else {
+				lines = append(lines, fmt.Sprintf("instance type %s in zones %s: %d planned, %s - exceeded", demand.InstanceType, strings.Join(demand.ZoneIds, ", "), planned, strings.Join(statuses, ", ")))
+			}
+			exceeded = true
+		}
+	}
+
+	if !exceeded {
+		return nil
+	}
+	sort.Strings(lines)
+	return fmt.Errorf("the capacity planned in region %s exceeds what is available:\n  - %s", client.RegionId, strings.Join(lines, "\n  - "))
+}
+
+func capacityQuotaActionCode(client *connectivity.AliyunClient, quota capacityQuota) string {
+	var code string
+	switch quota.Kind {
+	case "vcpu":
+		code = client.Features.CapacityCheck.VcpuQuotaActionCode
+	case "eip":
+		code = client.Features.CapacityCheck.EipQuotaActionCode
+	case "disk":
+		code = client.Features.CapacityCheck.DiskQuotaActionCode
+	}
+	if code == "" {
+		return quota.QuotaActionCode
+	}
+	return code
+}
+
+func capacityQuotaObject(client *connectivity.AliyunClient, quota capacityQuota) (map[string]interface{}, error) {
+	v, err := capacityLookup(fmt.Sprintf("quota:%s:%s:%s", client.RegionId, quota.ProductCode, quota.QuotaActionCode), func() (interface{}, error) {
+		quotasServiceV2 := QuotasServiceV2{client}
+		return quotasServiceV2.DescribeQuotasProductQuota(quota.ProductCode, quota.QuotaActionCode, client.RegionId)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(map[string]interface{}), nil
+}
+
+func formatCapacityAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,205 @@
+package alicloud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitCapacityUsage(t *testing.T) {
+	getter := func(values map[string]interface{}) func(string) interface{} {
+		return func(key string) interface{} {
+			return values[key]
+		}
+	}
+
+	assert.Equal(t, capacityUsage{InstanceType: "ecs.g7.large", ZoneId: "cn-hangzhou-k", Instances: 1, PostPaid: true, DiskGiB: 140}, capacityCheckResources["alicloud_instance"](getter(map[string]interface{}{
+		"instance_type":        "ecs.g7.large",
+		"availability_zone":    "cn-hangzhou-k",
+		"instance_charge_type": "PostPaid",
+		"system_disk_size":     40,
+		"data_disks":           []interface{}{map[string]interface{}{"size": 100}},
+	})))
+	assert.Equal(t, capacityUsage{InstanceType: "ecs.g7.large", ZoneId: "cn-hangzhou-k", Instances: 3}, capacityCheckResources["alicloud_ecs_instance_set"](getter(map[string]interface{}{
+		"instance_type":        "ecs.g7.large",
+		"zone_id":              "cn-hangzhou-k",
+		"instance_charge_type": "PrePaid",
+		"amount":               3,
+		"system_disk_size":     40,
+	})))
+	assert.Equal(t, capacityUsage{InstanceType: "ecs.g7.xlarge", VSwitchIds: []string{"vsw-a", "vsw-b"}, Instances: 2, PostPaid: true, DiskGiB: 240}, capacityCheckResources["alicloud_cs_kubernetes_node_pool"](getter(map[string]interface{}{
+		"instance_types":   []interface{}{"ecs.g7.xlarge", "ecs.g6.xlarge"},
+		"vswitch_ids":      []interface{}{"vsw-b", "vsw-a"},
+		"desired_size":     "2",
+		"system_disk_size": 120,
+	})))
+	assert.Equal(t, capacityUsage{VSwitchIds: []string{"vsw-a"}, ScalingGroup: true, Instances: 5, PostPaid: true}, capacityCheckResources["alicloud_ess_scaling_group"](getter(map[string]interface{}{
+		"min_size":         2,
+		"desired_capacity": 3,
+		"vswitch_ids":      schema.NewSet(schema.HashString, []interface{}{"vsw-a"}),
+		"warm_pool":        []interface{}{map[string]interface{}{"size": 2}},
+	})))
+	assert.Equal(t, capacityUsage{VSwitchIds: []string{"vsw-a"}, ScalingGroup: true, Instances: 2, PostPaid: true}, capacityCheckResources["alicloud_ess_scaling_group"](getter(map[string]interface{}{
+		"min_size":   2,
+		"vswitch_id": "vsw-a",
+	})))
+	assert.Equal(t, capacityUsage{}, capacityCheckResources["alicloud_ecs_disk"](getter(map[string]interface{}{
+		"payment_type": "Subscription",
+		"size":         500,
+	})))
+	assert.Equal(t, capacityUsage{DiskGiB: 500}, capacityCheckResources["alicloud_disk"](getter(map[string]interface{}{
+		"size": 500,
+	})))
+	assert.Equal(t, capacityUsage{Eips: 1}, capacityCheckResources["alicloud_eip_address"](getter(nil)))
+}
+
+func TestUnitCapacityCheckPlan(t *testing.T) {
+	client := &connectivity.AliyunClient{RegionId: "cn-capacity-test"}
+	client.Features.CapacityCheck.EipQuotaActionCode = "q_eip-test"
+	seed := func(key string, value interface{}) {
+		capacityLookup(key, func() (interface{}, error) {
+			return value, nil
+		})
+	}
+	seed("cores:ecs.g7.large", float64(2))
+	seed("quota:cn-capacity-test:ecs:q_elastic-vcpu-upper-limit", map[string]interface{}{"TotalQuota": float64(10), "TotalUsage": float64(6)})
+	seed("quota:cn-capacity-test:vpc:q_eip-test", map[string]interface{}{"TotalQuota": float64(20), "TotalUsage": float64(1)})
+	seed("stock:cn-capacity-test:cn-capacity-test-a:ecs.g7.large:PostPaid", "WithStock")
+	seed("stock:cn-capacity-test:cn-capacity-test-b:ecs.g7.large:PostPaid", "WithoutStock")
+	defer capacityLookups.Range(func(key, _ interface{}) bool {
+		capacityLookups.Delete(key)
+		return true
+	})
+
+	instance := capacityUsage{InstanceType: "ecs.g7.large", ZoneId: "cn-capacity-test-a", Instances: 1, PostPaid: true}
+	first, err := capacityDemandOf(client, "", capacityUsage{}, instance)
+	assert.Nil(t, err)
+	assert.Equal(t, capacityDemand{
+		Quotas:       map[string]float64{"vcpu": 2, "eip": 0, "disk": 0},
+		InstanceType: "ecs.g7.large",
+		ZoneIds:      []string{"cn-capacity-test-a"},
+		ChargeType:   "PostPaid",
+		Instances:    1,
+	}, first)
+	assert.Nil(t, checkCapacityPlan(client, first, []capacityDemand{first}))
+
+	// resizing an existing instance only asks for the vCPUs it adds, and no stock for instances already running
+	resized, err := capacityDemandOf(client, "i-capacity-test", instance, capacityUsage{InstanceType: "ecs.g7.large", ZoneId: "cn-capacity-test-a", Instances: 1, PostPaid: true, Eips: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, float64(0), resized.Quotas["vcpu"])
+	assert.Equal(t, 0, resized.Instances)
+
+	second, err := capacityDemandOf(client, "", capacityUsage{}, capacityUsage{InstanceType: "ecs.g7.large", ZoneId: "cn-capacity-test-b", Instances: 2, PostPaid: true, Eips: 1})
+	assert.Nil(t, err)
+	err = checkCapacityPlan(client, second, []capacityDemand{first, second})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "the capacity planned in region cn-capacity-test exceeds what is available:\n"+
+			"  - EIPs (vpc/q_eip-test): 1 planned, 19 available of 20 (1 used)\n"+
+			"  - instance type ecs.g7.large in zone cn-capacity-test-b: 2 planned, WithoutStock - exceeded\n"+
+			"  - vCPUs of pay-as-you-go instances (ecs/q_elastic-vcpu-upper-limit): 6 planned, 4 available of 10 (6 used) - exceeded", err.Error())
+	}
+
+	// what other resources release is not subtracted, so the verdict does not depend on the order of the plan
+	released, err := capacityDemandOf(client, "i-capacity-test", capacityUsage{InstanceType: "ecs.g7.large", ZoneId: "cn-capacity-test-a", Instances: 2, PostPaid: true}, capacityUsage{})
+	assert.Nil(t, err)
+	assert.Equal(t, float64(-4), released.Quotas["vcpu"])
+	assert.Nil(t, checkCapacityPlan(client, released, []capacityDemand{first, released}))
+	assert.NotNil(t, checkCapacityPlan(client, second, []capacityDemand{released, first, second}))
+
+	// instances spread over vSwitches need stock in one of their zones
+	seed("zone:vsw-a", "cn-capacity-test-a")
+	seed("zone:vsw-b", "cn-capacity-test-b")
+	seed("scaling_group:asg-capacity-test", "ecs.g7.large")
+	group, err := capacityDemandOf(client, "asg-capacity-test", capacityUsage{}, capacityUsage{VSwitchIds: []string{"vsw-a", "vsw-b"}, ScalingGroup: true, Instances: 1, PostPaid: true})
+	assert.Nil(t, err)
+	assert.Equal(t, capacityDemand{
+		Quotas:       map[string]float64{"vcpu": 2, "eip": 0, "disk": 0},
+		InstanceType: "ecs.g7.large",
+		ZoneIds:      []string{"cn-capacity-test-a", "cn-capacity-test-b"},
+		ChargeType:   "PostPaid",
+		Instances:    1,
+	}, group)
+	assert.Nil(t, checkCapacityPlan(client, group, []capacityDemand{group}))
+	group.ZoneIds = []string{"cn-capacity-test-b", "cn-capacity-test-c"}
+	seed("stock:cn-capacity-test:cn-capacity-test-c:ecs.g7.large:PostPaid", "")
+	group.Quotas["vcpu"] = 0
+	err = checkCapacityPlan(client, group, []capacityDemand{group})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "the capacity planned in region cn-capacity-test exceeds what is available:\n"+
+			"  - instance type ecs.g7.large in zones cn-capacity-test-b, cn-capacity-test-c: 1 planned, WithoutStock, not offered - exceeded", err.Error())
+	}
+}
+
+func TestUnitCapacityPlanKey(t *testing.T) {
+	keys := make([]string, 0)
+	replaced := make([]bool, 0)
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"instance_type": {Type: schema.TypeString, Required: true, ForceNew: true},
+			"instance_name": {Type: schema.TypeString, Optional: true},
+			"status":        {Type: schema.TypeString, Computed: true},
+		},
+	}
+	r.CustomizeDiff = func(diff *schema.ResourceDiff, meta interface{}) error {
+		keys = append(keys, capacityPlanKey("alicloud_instance", r.Schema, diff))
+		replaced = append(replaced, capacityReplaced(r.Schema, diff))
+		return nil
+	}
+	config := func(name string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{"instance_type": "ecs.g7.large", "instance_name": name})
+	}
+
+	// a new resource is planned twice with the same key, and another one gets its own
+	_, err := r.Diff(nil, config("web-1"), nil)
+	assert.Nil(t, err)
+	_, err = r.Diff(nil, config("web-2"), nil)
+	assert.Nil(t, err)
+	_, err = r.Diff(nil, config("web-1"), nil)
+	assert.Nil(t, err)
+	if assert.Len(t, keys, 6) {
+		assert.Equal(t, keys[0], keys[1])
+		assert.NotEqual(t, keys[0], keys[2])
+		assert.Equal(t, keys[0], keys[4])
+		assert.Equal(t, []bool{false, false, false, false, false, false}, replaced)
+	}
+
+	// an existing resource is keyed by its ID, and its replacement is planned as a new resource
+	keys, replaced = keys[:0], replaced[:0]
+	state := &terraform.InstanceState{ID: "i-capacity", Attributes: map[string]string{"id": "i-capacity", "instance_type": "ecs.g6.large", "instance_name": "web-1"}}
+	_, err = r.Diff(state, config("web-1"), nil)
+	assert.Nil(t, err)
+	if assert.Len(t, keys, 2) {
+		assert.Equal(t, "alicloud_instance:i-capacity", keys[0])
+		assert.Equal(t, []bool{true, false}, replaced)
+		assert.True(t, strings.HasPrefix(keys[1], "alicloud_instance#"))
+	}
+
+	// resources planned with the very same arguments count once each, however many passes their diff takes
+	plan := newCapacityPlan()
+	var demands []capacityDemand
+	r.CustomizeDiff = func(diff *schema.ResourceDiff, meta interface{}) error {
+		demands = plan.add(capacityPlanKey("alicloud_instance", r.Schema, diff), capacityDemand{Instances: 1}, diff.Id() == "", capacityDiffPasses(r.Schema, diff))
+		return nil
+	}
+	_, err = r.Diff(nil, config("web-1"), nil)
+	assert.Nil(t, err)
+	assert.Len(t, demands, 1)
+	_, err = r.Diff(nil, config("web-1"), nil)
+	assert.Nil(t, err)
+	assert.Len(t, demands, 2)
+	_, err = r.Diff(nil, config("web-2"), nil)
+	assert.Nil(t, err)
+	assert.Len(t, demands, 3)
+
+	r.Schema["instance_type"].ForceNew = false
+	plan = newCapacityPlan()
+	for i := 0; i < 2; i++ {
+		_, err = r.Diff(nil, config("web-1"), nil)
+		assert.Nil(t, err)
+	}
+	assert.Len(t, demands, 2)
+}
@@ -0,0 +1,390 @@
+package alicloud
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// cdnConfigArgKind is the type of the value of a CDN or DCDN config function argument.
+type cdnConfigArgKind int
+
+const (
+	cdnConfigArgString cdnConfigArgKind = iota
+	cdnConfigArgSwitch
+	cdnConfigArgInteger
+	cdnConfigArgEnum
+	cdnConfigArgList
+)
+
+// cdnConfigArg describes the values an argument of a config function takes. An argument has the same type in every
+// function using it, so a value can be normalized knowing only the argument name.
+type cdnConfigArg struct {
+	Kind   cdnConfigArgKind
+	Min    int
+	Max    int
+	Values []string
+}
+
+// cdnConfigFunction is a config function of a CDN or DCDN domain. Products lists the products serving the function,
+// both when empty.
+type cdnConfigFunction struct {
+	Args     []string
+	Products []string
+}
+
+// cdnConfigUnknownValue is the value the SDK gives to arguments not known until apply.
+const cdnConfigUnknownValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+func cdnConfigInteger(min, max int) cdnConfigArg {
+	return cdnConfigArg{Kind: cdnConfigArgInteger, Min: min, Max: max}
+}
+
+func cdnConfigEnum(values ...string) cdnConfigArg {
+	return cdnConfigArg{Kind: cdnConfigArgEnum, Values: values}
+}
+
+var (
+	cdnConfigSwitch = cdnConfigArg{Kind: cdnConfigArgSwitch}
+	cdnConfigString = cdnConfigArg{Kind: cdnConfigArgString}
+	cdnConfigList   = cdnConfigArg{Kind: cdnConfigArgList}
+)
+
+// cdnConfigArgs is the catalog of the arguments of the config functions, see
+// https://www.alibabacloud.com/help/en/cdn/developer-reference/parameters-for-configuring-features-for-domain-names
+var cdnConfigArgs = map[string]cdnConfigArg{
+	"action":                        cdnConfigInteger(0, 1),
+	"ali_auth_delta":                cdnConfigInteger(0, 315360000),
+	"allow_empty":                   cdnConfigSwitch,
+	"auth_key1":                     cdnConfigString,
+	"auth_key2":                     cdnConfigString,
+	"auth_type":                     cdnConfigEnum("no_auth", "type_a", "type_b", "type_c", "type_f"),
+	"brotli_level":                  cdnConfigInteger(1, 11),
+	"code_string":                   cdnConfigString,
+	"count":                         cdnConfigInteger(1, 100000000),
+	"disable":                       cdnConfigSwitch,
+	"domain_name":                   cdnConfigString,
+	"enable":                        cdnConfigSwitch,
+	"enabled":                       cdnConfigSwitch,
+	"error_code":                    cdnConfigInteger(400, 599),
+	"file_type":                     cdnConfigList,
+	"flag":                          cdnConfigEnum("break", "enhance_break", "redirect"),
+	"hashkey_args":                  cdnConfigList,
+	"header_name":                   cdnConfigString,
+	"header_operation_type":         cdnConfigEnum("add", "delete", "modify", "rewrite"),
+	"header_value":                  cdnConfigString,
+	"http2":                         cdnConfigSwitch,
+	"http_rewrite":                  cdnConfigEnum("301", "302", "307", "308"),
+	"https_hsts_include_subdomains": cdnConfigSwitch,
+	"https_hsts_max_age":            cdnConfigInteger(0, 315360000),
+	"https_origin_sni":              cdnConfigString,
+	"https_rewrite":                 cdnConfigEnum("301", "302", "307", "308"),
+	"interval":                      cdnConfigInteger(1, 86400),
+	"ip_list":                       cdnConfigList,
+	"keep_oss_args":                 cdnConfigSwitch,
+	"key":                           cdnConfigString,
+	"name":                          cdnConfigString,
+	"ocsp_stapling":                 cdnConfigSwitch,
+	"oss_bucket_id":                 cdnConfigString,
+	"path":                          cdnConfigString,
+	"pathType":                      cdnConfigInteger(0, 2),
+	"private_oss_auth":              cdnConfigSwitch,
+	"refer_domain_allow_list":       cdnConfigList,
+	"refer_domain_deny_list":        cdnConfigList,
+	"regex":                         cdnConfigString,
+	"region":                        cdnConfigString,
+	"replacement":                   cdnConfigString,
+	"rewrite_page":                  cdnConfigString,
+	"rule":                          cdnConfigString,
+	"scheme_origin":                 cdnConfigEnum("follow", "http", "https"),
+	"scheme_origin_port":            cdnConfigString,
+	"source_url":                    cdnConfigString,
+	"switch":                        cdnConfigSwitch,
+	"target_url":                    cdnConfigString,
+	"tls10":                         cdnConfigSwitch,
+	"tls11":                         cdnConfigSwitch,
+	"tls12":                         cdnConfigSwitch,
+	"tls13":                         cdnConfigSwitch,
+	"ttl":                           cdnConfigInteger(0, 315360000),
+	"type":                          cdnConfigString,
+	"ua":                            cdnConfigString,
+	"value":                         cdnConfigString,
+	"weight":                        cdnConfigInteger(1, 99),
+}
+
+// cdnConfigFunctions is the catalog of the config functions. It is not exhaustive: functions missing from it are
+// passed through to the API as they are.
+var cdnConfigFunctions = map[string]cdnConfigFunction{
+	"HSTS":                       {Args: []string{"enabled", "https_hsts_max_age", "https_hsts_include_subdomains"}},
+	"ali_ua":                     {Args: []string{"ua", "type"}},
+	"aliauth":                    {Args: []string{"auth_type", "auth_key1", "auth_key2", "ali_auth_delta"}},
+	"back_to_origin_url_rewrite": {Args: []string{"source_url", "target_url", "flag"}},
+	"brotli":                     {Args: []string{"enable", "brotli_level"}},
+	"condition":                  {Args: []string{"rule"}},
+	"dynamic":                    {Args: []string{"enable"}},
+	"error_page":                 {Args: []string{"error_code", "rewrite_page"}},
+	"filetype_based_ttl_set":     {Args: []string{"ttl", "file_type", "weight"}},
+	"filetype_force_ttl_code":    {Args: []string{"file_type", "code_string"}},
+	"forward_scheme":             {Args: []string{"enable", "scheme_origin", "scheme_origin_port"}},
+	"green_manager":              {Args: []string{"enable"}, Products: []string{"cdn"}},
+	"gzip":                       {Args: []string{"enable"}},
+	"host_redirect":              {Args: []string{"regex", "replacement", "flag"}},
+	"http_force":                 {Args: []string{"enable", "http_rewrite"}},
+	"https_force":                {Args: []string{"enable", "https_rewrite"}},
+	"https_option":               {Args: []string{"http2", "ocsp_stapling"}},
+	"https_origin_sni":           {Args: []string{"enabled", "https_origin_sni"}},
+	"https_tls_version":          {Args: []string{"tls10", "tls11", "tls12", "tls13"}},
+	"ip_allow_list_set":          {Args: []string{"ip_list"}},
+	"ip_black_list_set":          {Args: []string{"ip_list"}},
+	"ip_white_list_set":          {Args: []string{"ip_list"}},
+	"ipv6":                       {Args: []string{"switch", "region"}},
+	"l2_oss_key":                 {Args: []string{"private_oss_auth"}},
+	"oss_auth":                   {Args: []string{"oss_bucket_id"}},
+	"path_based_ttl_set":         {Args: []string{"ttl", "path", "weight"}},
+	"path_force_ttl_code":        {Args: []string{"path", "code_string"}},
+	"range":                      {Args: []string{"enable"}},
+	"referer_black_list_set":     {Args: []string{"refer_domain_deny_list", "allow_empty"}},
+	"referer_white_list_set":     {Args: []string{"refer_domain_allow_list", "allow_empty"}},
+	"set_hashkey_args":           {Args: []string{"hashkey_args", "disable", "keep_oss_args"}},
+	"set_req_header":             {Args: []string{"key", "value"}},
+	"set_req_host_header":        {Args: []string{"domain_name"}},
+	"set_resp_header":            {Args: []string{"key", "value", "header_operation_type", "header_name", "header_value"}},
+	"tesla":                      {Args: []string{"enable"}, Products: []string{"cdn"}},
+	"tmd_signature":              {Args: []string{"name", "path", "pathType", "interval", "count", "action", "ttl"}},
+	"video_seek":                 {Args: []string{"enable"}},
+	"websocket":                  {Args: []string{"enabled"}, Products: []string{"dcdn"}},
+}
+
+// cdnConfigIgnoredArgs are the arguments the API adds to the config functions it returns, which are not managed.
+var cdnConfigIgnoredArgs = map[string][]string{
+	"cdn": {"aliyun_id", "scheme_origin_port", "dsl", "session_timeout",
+		"oss_pri_buckets", "private_oss_tbl", "private_oss_ram_unauthorized", "cert", "cert_name",
+		"consistent_hash", "cert_type", "https", "dkey", "pkey"},
+	"dcdn": {"dsl", "disable_l2_log", "dynamic_batch_route", "dynamic_enable_cpool_chash",
+		"dynamic_mux_ecn_enable", "dynamic_mux_keepalive_enable", "dynamic_mux_share_enable", "dynamic_pk",
+		"dynamic_retry_status", "dynamic_route_cdn_v2", "dynamic_route_cpool", "dynamic_route_magic",
+		"dynamic_route_round_robin", "dynamic_route_session", "dynamic_route_tunnel", "dynamie_route_alllink_log",
+		"keepalive_sni", "l7tol4", "partition_back_to_origin", "dynamic_route_adapt_cache",
+		"dynamic_route_http_methods", "dynamic_mux_tls_enable", "dynamic_route_cdn_v2"},
+}
+
+// normalizeCdnConfigArgValue returns the value of an argument the way the API returns it, so values written
+// differently do not show as a diff.
+func normalizeCdnConfigArgValue(name, value string) string {
+	arg, ok := cdnConfigArgs[name]
+	if !ok || value == cdnConfigUnknownValue {
+		return value
+	}
+	switch arg.Kind {
+	case cdnConfigArgSwitch, cdnConfigArgEnum:
+		return strings.ToLower(strings.TrimSpace(value))
+	case cdnConfigArgInteger:
+		if i, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			return strconv.Itoa(i)
+		}
+	case cdnConfigArgList:
+		items := strings.Split(value, ",")
+		for i, item := range items {
+			items[i] = strings.TrimSpace(item)
+		}
+		return strings.Join(items, ",")
+	}
+	return value
+}
+
+// validateCdnConfigFunction checks a config function of a product against the catalog. Unknown values are skipped,
+// and names missing from the catalog are only rejected when they look like a typo of a name in it.
+func validateCdnConfigFunction(product, functionName string, args []interface{}) error {
+	if functionName == "" || functionName == cdnConfigUnknownValue {
+		return nil
+	}
+	function, ok := cdnConfigFunctions[functionName]
+	if !ok {
+		names := make([]string, 0, len(cdnConfigFunctions))
+		for name := range cdnConfigFunctions {
+			names = append(names, name)
+		}
+		if suggestion := cdnConfigSuggestion(functionName, names); suggestion != "" {
+			return fmt.Errorf("unknown %s config function %q, did you mean %q?", product, functionName, suggestion)
+		}
+		log.Printf("[WARN] The %s config function %s is not in the catalog of the provider, it is not validated.", product, functionName)
+		return nil
+	}
+	if len(function.Products) > 0 && !InArray(product, function.Products) {
+		return fmt.Errorf("the config function %q is not available for %s domains", functionName, product)
+	}
+	for _, raw := range args {
+		arg, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := fmt.Sprint(arg["arg_name"])
+		value := fmt.Sprint(arg["arg_value"])
+		if name == "" || name == cdnConfigUnknownValue {
+			continue
+		}
+		if !InArray(name, function.Args) {
+			if suggestion := cdnConfigSuggestion(name, function.Args); suggestion != "" {
+				return fmt.Errorf("unknown argument %q of the %s config function %q, did you mean %q?", name, product, functionName, suggestion)
+			}
+			log.Printf("[WARN] The argument %s of the %s config function %s is not in the catalog of the provider, it is not validated.", name, product, functionName)
+			continue
+		}
+		if value == cdnConfigUnknownValue {
+			continue
+		}
+		if err := validateCdnConfigArgValue(name, normalizeCdnConfigArgValue(name, value)); err != nil {
+			return fmt.Errorf("invalid argument %q of the %s config function %q: %s", name, product, functionName, err)
+		}
+	}
+	return nil
+}
+
+func validateCdnConfigArgValue(name, value string) error {
+	arg := cdnConfigArgs[name]
+	switch arg.Kind {
+	case cdnConfigArgSwitch:
+		if value != "on" && value != "off" {
+			return fmt.Errorf("expected \"on\" or \"off\", got %q", value)
+		}
+	case cdnConfigArgEnum:
+		if !InArray(value, arg.Values) {
+			return fmt.Errorf("expected one of %s, got %q", strings.Join(arg.Values, ", "), value)
+		}
+	case cdnConfigArgInteger:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", value)
+		}
+		if i < arg.Min || i > arg.Max {
+			return fmt.Errorf("expected an integer between %d and %d, got %d", arg.Min, arg.Max, i)
+		}
+	case cdnConfigArgList:
+		for _, item := range strings.Split(value, ",") {
+			if item == "" {
+				return fmt.Errorf("expected a comma separated list without empty items, got %q", value)
+			}
+		}
+	}
+	return nil
+}
+
+// cdnConfigSuggestion returns the name closest to a misspelled one, or "" when none is close enough to be meant.
+func cdnConfigSuggestion(name string, names []string) string {
+	suggestion, best := "", 3
+	names = append([]string{}, names...)
+	sort.Strings(names)
+	for _, candidate := range names {
+		if strings.EqualFold(name, candidate) {
+			return candidate
+		}
+		if len(candidate) < 4 {
+			continue
+		}
+		if distance := cdnConfigEditDistance(name, candidate); distance < best {
+			suggestion, best = candidate, distance
+		}
+	}
+	return suggestion
+}
+
+func cdnConfigEditDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// cdnConfigFunctionArgs returns the arguments of a config function to send to the API, with normalized values.
+func cdnConfigFunctionArgs(functionArgs []interface{}) []map[string]interface{} {
+	args := make([]map[string]interface{}, len(functionArgs))
+	for key, value := range functionArgs {
+		arg := value.(map[string]interface{})
+		args[key] = map[string]interface{}{
+			"argName":  arg["arg_name"],
+			"argValue": normalizeCdnConfigArgValue(fmt.Sprint(arg["arg_name"]), fmt.Sprint(arg["arg_value"])),
+		}
+	}
+	return args
+}
+
+// cdnConfigFunctionArgsFromApi returns the managed arguments of a config function returned by the API.
+func cdnConfigFunctionArgsFromApi(product string, config map[string]interface{}) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	functionArgs, ok := config["FunctionArgs"].(map[string]interface{})
+	if !ok {
+		return result
+	}
+	args, _ := functionArgs["FunctionArg"].([]interface{})
+	for _, raw := range args {
+		arg := raw.(map[string]interface{})
+		// The API adds extra function args, filter them out.
+		if InArray(fmt.Sprint(arg["ArgName"]), cdnConfigIgnoredArgs[product]) {
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"arg_name":  fmt.Sprint(arg["ArgName"]),
+			"arg_value": fmt.Sprint(arg["ArgValue"]),
+		})
+	}
+	return result
+}
+
+// cdnConfigArgValueDiffSuppressFunc suppresses the diff of an argument value written differently from the value
+// returned by the API.
+func cdnConfigArgValueDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	name, ok := d.Get(strings.TrimSuffix(k, "arg_value") + "arg_name").(string)
+	if !ok || name == "" {
+		return false
+	}
+	return normalizeCdnConfigArgValue(name, old) == normalizeCdnConfigArgValue(name, new)
+}
+
+// cdnConfigFunctionArgsSchema is the schema of the arguments of a config function.
+func cdnConfigFunctionArgsSchema(set schema.SchemaSetFunc) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Set:      set,
+		Required: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"arg_name": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"arg_value": {
+					Type:             schema.TypeString,
+					Required:         true,
+					DiffSuppressFunc: cdnConfigArgValueDiffSuppressFunc,
+				},
+			},
+		},
+	}
+}
+
+// cdnConfigFunctionCustomizeDiff validates the config function of the domain config resources of a product.
+func cdnConfigFunctionCustomizeDiff(product string) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, meta interface{}) error {
+		if !d.NewValueKnown("function_name") || !d.NewValueKnown("function_args") {
+			return nil
+		}
+		return validateCdnConfigFunction(product, d.Get("function_name").(string), d.Get("function_args").(*schema.Set).List())
+	}
+}
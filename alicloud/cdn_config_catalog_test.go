@@ -0,0 +1,104 @@
+package alicloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitCdnConfigCatalog(t *testing.T) {
+	for name, function := range cdnConfigFunctions {
+		for _, arg := range function.Args {
+			_, ok := cdnConfigArgs[arg]
+			assert.True(t, ok, "argument %s of %s is not in the catalog", arg, name)
+		}
+		for _, product := range function.Products {
+			assert.Contains(t, []string{"cdn", "dcdn"}, product)
+		}
+	}
+
+	assert.Equal(t, "on", normalizeCdnConfigArgValue("enable", " ON"))
+	assert.Equal(t, "300", normalizeCdnConfigArgValue("ttl", "0300"))
+	assert.Equal(t, "no_auth", normalizeCdnConfigArgValue("auth_type", "No_Auth"))
+	assert.Equal(t, "jpg,png", normalizeCdnConfigArgValue("file_type", "jpg, png"))
+	assert.Equal(t, "User-Agent", normalizeCdnConfigArgValue("ua", "User-Agent"))
+	assert.Equal(t, "Value", normalizeCdnConfigArgValue("unknown_arg", "Value"))
+	assert.Equal(t, "abc", normalizeCdnConfigArgValue("ttl", "abc"))
+}
+
+func TestUnitCdnConfigValidation(t *testing.T) {
+	args := func(pairs ...string) []interface{} {
+		result := make([]interface{}, 0)
+		for i := 0; i < len(pairs); i += 2 {
+			result = append(result, map[string]interface{}{"arg_name": pairs[i], "arg_value": pairs[i+1]})
+		}
+		return result
+	}
+
+	assert.Nil(t, validateCdnConfigFunction("cdn", "filetype_based_ttl_set", args("ttl", "300", "file_type", "jpg", "weight", "1")))
+	assert.Nil(t, validateCdnConfigFunction("cdn", "ali_ua", args("ua", "User-Agent", "type", "black")))
+	assert.Nil(t, validateCdnConfigFunction("cdn", "https_force", args("enable", "ON")))
+	assert.Nil(t, validateCdnConfigFunction("cdn", "condition", args("rule", "{}")))
+	// functions and arguments missing from the catalog are passed through
+	assert.Nil(t, validateCdnConfigFunction("cdn", "origin_dns_host", args("domain", "example.com")))
+	assert.Nil(t, validateCdnConfigFunction("cdn", "aliauth", args("auth_remote_desc", "{}")))
+	// values not known until apply are not validated
+	assert.Nil(t, validateCdnConfigFunction("cdn", "gzip", args("enable", cdnConfigUnknownValue)))
+	assert.Nil(t, validateCdnConfigFunction("cdn", cdnConfigUnknownValue, args("enable", "yes")))
+
+	err := validateCdnConfigFunction("cdn", "filetype_based_tll_set", nil)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `unknown cdn config function "filetype_based_tll_set", did you mean "filetype_based_ttl_set"?`, err.Error())
+	}
+	err = validateCdnConfigFunction("dcdn", "hsts", nil)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `unknown dcdn config function "hsts", did you mean "HSTS"?`, err.Error())
+	}
+	err = validateCdnConfigFunction("cdn", "brotli", args("enabled", "on"))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `unknown argument "enabled" of the cdn config function "brotli", did you mean "enable"?`, err.Error())
+	}
+	err = validateCdnConfigFunction("cdn", "brotli", args("enable", "on", "brotli_level", "12"))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `invalid argument "brotli_level" of the cdn config function "brotli": expected an integer between 1 and 11, got 12`, err.Error())
+	}
+	err = validateCdnConfigFunction("cdn", "gzip", args("enable", "yes"))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `invalid argument "enable" of the cdn config function "gzip": expected "on" or "off", got "yes"`, err.Error())
+	}
+	err = validateCdnConfigFunction("cdn", "forward_scheme", args("scheme_origin", "ftp"))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `invalid argument "scheme_origin" of the cdn config function "forward_scheme": expected one of follow, http, https, got "ftp"`, err.Error())
+	}
+	err = validateCdnConfigFunction("dcdn", "green_manager", args("enable", "on"))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `the config function "green_manager" is not available for dcdn domains`, err.Error())
+	}
+}
+
+func TestUnitCdnConfigHash(t *testing.T) {
+	assert.Equal(t,
+		expirationCdnDomainConfigHash(map[string]interface{}{"arg_name": "enable", "arg_value": "on"}),
+		expirationCdnDomainConfigHash(map[string]interface{}{"arg_name": "enable", "arg_value": "ON"}))
+	assert.NotEqual(t,
+		expirationCdnDomainConfigHash(map[string]interface{}{"arg_name": "enable", "arg_value": "on"}),
+		expirationCdnDomainConfigHash(map[string]interface{}{"arg_name": "enable", "arg_value": "off"}))
+
+	config := func(parentId string, args ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"function_name": "filetype_based_ttl_set",
+			"parent_id":     parentId,
+			"function_args": schema.NewSet(expirationCdnDomainConfigHash, args),
+			"config_id":     "",
+		}
+	}
+	ttl := map[string]interface{}{"arg_name": "ttl", "arg_value": "300"}
+	fileType := map[string]interface{}{"arg_name": "file_type", "arg_value": "jpg"}
+	read := config("", ttl, fileType)
+	read["config_id"] = "123"
+	read["status"] = "success"
+	assert.Equal(t, cdnDomainConfigsHash(config("", fileType, ttl)), cdnDomainConfigsHash(read))
+	assert.NotEqual(t, cdnDomainConfigsHash(config("", ttl)), cdnDomainConfigsHash(read))
+	assert.NotEqual(t, cdnDomainConfigsHash(config("456", ttl, fileType)), cdnDomainConfigsHash(read))
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"github.com/aliyun/aliyun-tablestore-go-sdk/tablestore"
 	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
 	"github.com/denverdino/aliyungo/common"
 	"github.com/denverdino/aliyungo/cs"
 	"github.com/google/uuid"
@@ -1038,6 +1039,14 @@ func BuildStateConf(pending, target []string, timeout, delay time.Duration, f re
 	}
 }
 
+// BuildTracedStateConf is BuildStateConf for a wait loop that is traced as a child of the operation client runs,
+// when client is the handle of a traced operation.
+func BuildTracedStateConf(client *connectivity.AliyunClient, pending, target []string, timeout, delay time.Duration, f resource.StateRefreshFunc) *resource.StateChangeConf {
+	stateConf := BuildStateConf(pending, target, timeout, delay, f)
+	stateConf.Refresh = traceWait(client, target, f)
+	return stateConf
+}
+
 func incrementalWait(firstDuration time.Duration, increaseDuration time.Duration) func() {
 	retryCount := 1
 	return func() {
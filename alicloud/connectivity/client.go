@@ -162,6 +162,20 @@ type AliyunClient struct {
 	maxcomputeConn               *maxcompute.Client
 
 	Features features.Features
+
+	// regional clients serve resources whose region argument differs from the provider's region.
+	// They are created by the provider's client on first use, and point back to it through parent.
+	parent              *AliyunClient
+	regionalClients     map[string]*AliyunClient
+	regionalClientMutex sync.Mutex
+
+	// dataSourceSlots bounds the extra requests data sources have in flight across the provider, see DataSourceSlots.
+	dataSourceSlots     chan struct{}
+	dataSourceSlotsOnce sync.Once
+
+	// traceScope is only set on the handle of an operation that is traced, see StartTrace. The methods of a handle
+	// run on the client it was started from, which owns the connections, so only the scope tells them apart.
+	traceScope *traceScope
 }
 
 type ApiVersion string
@@ -280,9 +294,82 @@ func (c *Config) Client() (*AliyunClient, error) {
 	return client, nil
 }
 
+// WithRegion returns the client to use for regionId. An empty regionId or the region of the client
+// returns the client itself. Any other region gets a client that is created on first use and reused
+// afterwards. It shares the credentials, the timeouts and the retry settings of the provider, and
+// resolves endpoints the same way, with the endpoints already known for the provider's region
+// rewritten to regionId.
+func (client *AliyunClient) WithRegion(regionId string) (*AliyunClient, error) {
+	if regionId == "" || regionId == client.RegionId {
+		return client, nil
+	}
+	client = client.shared()
+	root := client
+	if client.parent != nil {
+		root = client.parent
+		if regionId == root.RegionId {
+			return root, nil
+		}
+	}
+
+	root.regionalClientMutex.Lock()
+	defer root.regionalClientMutex.Unlock()
+	if regional, ok := root.regionalClients[regionId]; ok {
+		return regional, nil
+	}
+
+	config := *root.config
+	config.Region = Region(regionId)
+	config.RegionId = regionId
+	config.StsEndpoint = strings.ReplaceAll(config.StsEndpoint, root.RegionId, regionId)
+	var endpoints sync.Map
+	if root.config.Endpoints != nil {
+		root.config.Endpoints.Range(func(key, value interface{}) bool {
+			if endpoint, ok := value.(string); ok {
+				value = strings.ReplaceAll(endpoint, root.RegionId, regionId)
+			}
+			endpoints.Store(key, value)
+			return true
+		})
+	}
+	config.Endpoints = &endpoints
+
+	regional, err := config.Client()
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize the client of region %s: %v", regionId, err)
+	}
+	regional.parent = root
+	if root.regionalClients == nil {
+		root.regionalClients = make(map[string]*AliyunClient)
+	}
+	root.regionalClients[regionId] = regional
+	log.Printf("[INFO] initialized the client of region %s.", regionId)
+	return regional, nil
+}
+
+// DataSourceSlots returns the semaphore shared by every data source of the provider, including the ones that read
+// another region, which bounds the requests they make on top of the one each of them always has in flight. It holds
+// one slot less than Features.DataSource.Concurrency, so a single data source never exceeds that concurrency.
+func (client *AliyunClient) DataSourceSlots() chan struct{} {
+	client = client.shared()
+	if client.parent != nil {
+		client = client.parent
+	}
+	client.dataSourceSlotsOnce.Do(func() {
+		concurrency := client.Features.DataSource.Concurrency
+		if concurrency < 1 {
+			concurrency = features.Default().DataSource.Concurrency
+		}
+		client.dataSourceSlots = make(chan struct{}, concurrency-1)
+	})
+	return client.dataSourceSlots
+}
+
 func (client *AliyunClient) WithEcsClient(do func(*ecs.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.ecsconn != nil && !client.config.needRefreshCredential() {
-		return do(client.ecsconn)
+		return scope.traceCall("ecs", func() (interface{}, error) { return do(client.ecsconn) })
 	}
 	product := "ecs"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -322,12 +409,14 @@ func (client *AliyunClient) WithEcsClient(do func(*ecs.Client) (interface{}, err
 	ecsconn.SourceIp = client.config.SourceIp
 	ecsconn.SecureTransport = client.config.SecureTransport
 	client.ecsconn = ecsconn
-	return do(client.ecsconn)
+	return scope.traceCall("ecs", func() (interface{}, error) { return do(client.ecsconn) })
 }
 
 func (client *AliyunClient) WithOfficalCSClient(do func(*officalCS.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.officalCSConn != nil && !client.config.needRefreshCredential() {
-		return do(client.officalCSConn)
+		return scope.traceCall("cs", func() (interface{}, error) { return do(client.officalCSConn) })
 	}
 	product := "cs"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -363,12 +452,14 @@ func (client *AliyunClient) WithOfficalCSClient(do func(*officalCS.Client) (inte
 	csconn.SourceIp = client.config.SourceIp
 	csconn.SecureTransport = client.config.SecureTransport
 	client.officalCSConn = csconn
-	return do(client.officalCSConn)
+	return scope.traceCall("cs", func() (interface{}, error) { return do(client.officalCSConn) })
 }
 
 func (client *AliyunClient) WithPolarDBClient(do func(*polardb.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.polarDBconn != nil && !client.config.needRefreshCredential() {
-		return do(client.polarDBconn)
+		return scope.traceCall("polardb", func() (interface{}, error) { return do(client.polarDBconn) })
 	}
 	product := "polardb"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -404,7 +495,7 @@ func (client *AliyunClient) WithPolarDBClient(do func(*polardb.Client) (interfac
 	polarDBconn.SourceIp = client.config.SourceIp
 	polarDBconn.SecureTransport = client.config.SecureTransport
 	client.polarDBconn = polarDBconn
-	return do(client.polarDBconn)
+	return scope.traceCall("polardb", func() (interface{}, error) { return do(client.polarDBconn) })
 }
 
 // NewPolarDBDynamoClient creates an AWS DynamoDB client (aws-sdk-go-v2) configured
@@ -412,6 +503,7 @@ func (client *AliyunClient) WithPolarDBClient(do func(*polardb.Client) (interfac
 // cluster's DynamoDB endpoint address. If accessKey/secretKey are empty, the provider's
 // credentials are used instead.
 func (client *AliyunClient) NewPolarDBDynamoClient(endpoint, accessKey, secretKey string) (*awsdynamodb.Client, error) {
+	client = client.shared()
 	if endpoint == "" {
 		return nil, fmt.Errorf("endpoint is required for PolarDB DynamoDB operations")
 	}
@@ -462,8 +554,10 @@ func (client *AliyunClient) NewPolarDBDynamoClient(endpoint, accessKey, secretKe
 }
 
 func (client *AliyunClient) WithSlbClient(do func(*slb.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.slbconn != nil && !client.config.needRefreshCredential() {
-		return do(client.slbconn)
+		return scope.traceCall("slb", func() (interface{}, error) { return do(client.slbconn) })
 	}
 	product := "slb"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -499,12 +593,14 @@ func (client *AliyunClient) WithSlbClient(do func(*slb.Client) (interface{}, err
 	slbconn.SourceIp = client.config.SourceIp
 	slbconn.SecureTransport = client.config.SecureTransport
 	client.slbconn = slbconn
-	return do(client.slbconn)
+	return scope.traceCall("slb", func() (interface{}, error) { return do(client.slbconn) })
 }
 
 func (client *AliyunClient) WithVpcClient(do func(*vpc.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.vpcconn != nil && !client.config.needRefreshCredential() {
-		return do(client.vpcconn)
+		return scope.traceCall("vpc", func() (interface{}, error) { return do(client.vpcconn) })
 	}
 	product := "vpc"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -540,12 +636,14 @@ func (client *AliyunClient) WithVpcClient(do func(*vpc.Client) (interface{}, err
 	vpcconn.SourceIp = client.config.SourceIp
 	vpcconn.SecureTransport = client.config.SecureTransport
 	client.vpcconn = vpcconn
-	return do(client.vpcconn)
+	return scope.traceCall("vpc", func() (interface{}, error) { return do(client.vpcconn) })
 }
 
 func (client *AliyunClient) WithEssClient(do func(*ess.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.essconn != nil && !client.config.needRefreshCredential() {
-		return do(client.essconn)
+		return scope.traceCall("ess", func() (interface{}, error) { return do(client.essconn) })
 	}
 	product := "ess"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -581,15 +679,17 @@ func (client *AliyunClient) WithEssClient(do func(*ess.Client) (interface{}, err
 	essconn.SourceIp = client.config.SourceIp
 	essconn.SecureTransport = client.config.SecureTransport
 	client.essconn = essconn
-	return do(client.essconn)
+	return scope.traceCall("ess", func() (interface{}, error) { return do(client.essconn) })
 }
 
 func (client *AliyunClient) WithOssClient(do func(*oss.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	goSdkMutex.Lock()
 	defer goSdkMutex.Unlock()
 
 	if client.ossconn != nil && !client.config.needRefreshCredential() {
-		return do(client.ossconn)
+		return scope.traceCall("oss", func() (interface{}, error) { return do(client.ossconn) })
 	}
 	product := "oss"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -636,12 +736,12 @@ func (client *AliyunClient) WithOssClient(do func(*oss.Client) (interface{}, err
 	}
 
 	client.ossconn = ossconn
-	return do(client.ossconn)
+	return scope.traceCall("oss", func() (interface{}, error) { return do(client.ossconn) })
 }
 
 func (client *AliyunClient) WithOssBucketByName(bucketName string, do func(*oss.Bucket) (interface{}, error)) (interface{}, error) {
 	return client.WithOssClient(func(ossClient *oss.Client) (interface{}, error) {
-		bucket, err := client.ossconn.Bucket(bucketName)
+		bucket, err := ossClient.Bucket(bucketName)
 		if err != nil {
 			return nil, fmt.Errorf("unable to get the bucket %s: %#v", bucketName, err)
 		}
@@ -650,11 +750,13 @@ func (client *AliyunClient) WithOssBucketByName(bucketName string, do func(*oss.
 }
 
 func (client *AliyunClient) WithOssClientV2(do func(*ossv2.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	goSdkMutex.Lock()
 	defer goSdkMutex.Unlock()
 
 	if client.ossconnV2 != nil && !client.config.needRefreshCredential() {
-		return do(client.ossconnV2)
+		return scope.traceCall("oss", func() (interface{}, error) { return do(client.ossconnV2) })
 	}
 	product := "oss"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -688,12 +790,14 @@ func (client *AliyunClient) WithOssClientV2(do func(*ossv2.Client) (interface{},
 	}
 
 	client.ossconnV2 = ossv2.NewClient(cfg)
-	return do(client.ossconnV2)
+	return scope.traceCall("oss", func() (interface{}, error) { return do(client.ossconnV2) })
 }
 
 func (client *AliyunClient) WithDnsClient(do func(*alidns.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.dnsconn != nil && !client.config.needRefreshCredential() {
-		return do(client.dnsconn)
+		return scope.traceCall("alidns", func() (interface{}, error) { return do(client.dnsconn) })
 	}
 	product := "alidns"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -730,12 +834,14 @@ func (client *AliyunClient) WithDnsClient(do func(*alidns.Client) (interface{},
 	dnsconn.SourceIp = client.config.SourceIp
 	dnsconn.SecureTransport = client.config.SecureTransport
 	client.dnsconn = dnsconn
-	return do(client.dnsconn)
+	return scope.traceCall("alidns", func() (interface{}, error) { return do(client.dnsconn) })
 }
 
 func (client *AliyunClient) WithRamClient(do func(*ram.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.ramconn != nil && !client.config.needRefreshCredential() {
-		return do(client.ramconn)
+		return scope.traceCall("ram", func() (interface{}, error) { return do(client.ramconn) })
 	}
 	product := "ram"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -772,12 +878,14 @@ func (client *AliyunClient) WithRamClient(do func(*ram.Client) (interface{}, err
 	ramconn.SecureTransport = client.config.SecureTransport
 	client.ramconn = ramconn
 
-	return do(client.ramconn)
+	return scope.traceCall("ram", func() (interface{}, error) { return do(client.ramconn) })
 }
 
 func (client *AliyunClient) WithCsClient(do func(*cs.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.csconn != nil && !client.config.needRefreshCredential() {
-		return do(client.csconn)
+		return scope.traceCall("cs", func() (interface{}, error) { return do(client.csconn) })
 	}
 	product := "cs"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -799,10 +907,11 @@ func (client *AliyunClient) WithCsClient(do func(*cs.Client) (interface{}, error
 	csconn.SetSourceIp(client.config.SourceIp)
 	csconn.SetSecureTransport(client.config.SecureTransport)
 	client.csconn = csconn
-	return do(client.csconn)
+	return scope.traceCall("cs", func() (interface{}, error) { return do(client.csconn) })
 }
 
 func (client *AliyunClient) NewRoaCsClient() (*roaCS.Client, error) {
+	client = client.shared()
 	product := "cs"
 	endpoint, err := client.loadApiEndpoint(product)
 	if err != nil {
@@ -840,8 +949,10 @@ func (client *AliyunClient) NewRoaCsClient() (*roaCS.Client, error) {
 }
 
 func (client *AliyunClient) WithCrClient(do func(*cr.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.crconn != nil && !client.config.needRefreshCredential() {
-		return do(client.crconn)
+		return scope.traceCall("cr", func() (interface{}, error) { return do(client.crconn) })
 	}
 	product := "cr"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -878,12 +989,14 @@ func (client *AliyunClient) WithCrClient(do func(*cr.Client) (interface{}, error
 	crconn.SecureTransport = client.config.SecureTransport
 	client.crconn = crconn
 
-	return do(client.crconn)
+	return scope.traceCall("cr", func() (interface{}, error) { return do(client.crconn) })
 }
 
 func (client *AliyunClient) WithCrEEClient(do func(*cr_ee.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.creeconn != nil && !client.config.needRefreshCredential() {
-		return do(client.creeconn)
+		return scope.traceCall("cr", func() (interface{}, error) { return do(client.creeconn) })
 	}
 	product := "cr"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -920,10 +1033,12 @@ func (client *AliyunClient) WithCrEEClient(do func(*cr_ee.Client) (interface{},
 	creeconn.SecureTransport = client.config.SecureTransport
 	client.creeconn = creeconn
 
-	return do(client.creeconn)
+	return scope.traceCall("cr", func() (interface{}, error) { return do(client.creeconn) })
 }
 
 func (client *AliyunClient) WithCdnClient(do func(*cdn.CdnClient) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	goSdkMutex.Lock()
 	defer goSdkMutex.Unlock()
 
@@ -942,12 +1057,14 @@ func (client *AliyunClient) WithCdnClient(do func(*cdn.CdnClient) (interface{},
 		}
 		client.cdnconn = cdnconn
 	}
-	return do(client.cdnconn)
+	return scope.traceCall("cdn", func() (interface{}, error) { return do(client.cdnconn) })
 }
 
 func (client *AliyunClient) WithCdnClient_new(do func(*cdn_new.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.cdnconn_new != nil && !client.config.needRefreshCredential() {
-		return do(client.cdnconn_new)
+		return scope.traceCall("cdn", func() (interface{}, error) { return do(client.cdnconn_new) })
 	}
 	product := "cdn"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -984,13 +1101,15 @@ func (client *AliyunClient) WithCdnClient_new(do func(*cdn_new.Client) (interfac
 	cdnconn.SecureTransport = client.config.SecureTransport
 	client.cdnconn_new = cdnconn
 
-	return do(client.cdnconn_new)
+	return scope.traceCall("cdn", func() (interface{}, error) { return do(client.cdnconn_new) })
 }
 
 // WithOtsClient init ots openapi publish sdk client(if necessary), and exec do func by client
 func (client *AliyunClient) WithOtsClient(do func(*ots.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.otsconn != nil && !client.config.needRefreshCredential() {
-		return do(client.otsconn)
+		return scope.traceCall("ots", func() (interface{}, error) { return do(client.otsconn) })
 	}
 	product := "ots"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -1027,11 +1146,12 @@ func (client *AliyunClient) WithOtsClient(do func(*ots.Client) (interface{}, err
 	otsconn.SecureTransport = client.config.SecureTransport
 	client.otsconn = otsconn
 
-	return do(client.otsconn)
+	return scope.traceCall("ots", func() (interface{}, error) { return do(client.otsconn) })
 }
 
 // NewOtsRoaClient rpc client for common sdk
 func (client *AliyunClient) NewOtsRoaClient(productCode string) (*roa.Client, error) {
+	client = client.shared()
 	// first, load endpoint by user setting
 	if v, ok := client.config.Endpoints.Load(productCode); !ok || v.(string) == "" {
 		// second,  load endpoint by serverside rule
@@ -1063,8 +1183,10 @@ func (client *AliyunClient) NewOtsRoaClient(productCode string) (*roa.Client, er
 }
 
 func (client *AliyunClient) WithCmsClient(do func(*cms.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.cmsconn != nil && !client.config.needRefreshCredential() {
-		return do(client.cmsconn)
+		return scope.traceCall("cms", func() (interface{}, error) { return do(client.cmsconn) })
 	}
 	product := "cms"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -1101,12 +1223,14 @@ func (client *AliyunClient) WithCmsClient(do func(*cms.Client) (interface{}, err
 	cmsconn.SecureTransport = client.config.SecureTransport
 	client.cmsconn = cmsconn
 
-	return do(client.cmsconn)
+	return scope.traceCall("cms", func() (interface{}, error) { return do(client.cmsconn) })
 }
 
 func (client *AliyunClient) WithLogPopClient(do func(*slsPop.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.logpopconn != nil && !client.config.needRefreshCredential() {
-		return do(client.logpopconn)
+		return scope.traceCall("sls", func() (interface{}, error) { return do(client.logpopconn) })
 	}
 	product := "sls"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -1143,15 +1267,17 @@ func (client *AliyunClient) WithLogPopClient(do func(*slsPop.Client) (interface{
 	logpopconn.Domain = endpoint + "/open-api"
 	client.logpopconn = logpopconn
 
-	return do(client.logpopconn)
+	return scope.traceCall("sls", func() (interface{}, error) { return do(client.logpopconn) })
 }
 
 func (client *AliyunClient) WithLogClient(do func(*sls.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	goSdkMutex.Lock()
 	defer goSdkMutex.Unlock()
 
 	if client.logconn != nil && !client.config.needRefreshCredential() {
-		return do(client.logconn)
+		return scope.traceCall("sls", func() (interface{}, error) { return do(client.logconn) })
 	}
 	product := "sls"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -1187,12 +1313,14 @@ func (client *AliyunClient) WithLogClient(do func(*sls.Client) (interface{}, err
 	}
 	applyLogClientSignVersion(client.logconn, client.config.SignVersion, "sls")
 
-	return do(client.logconn)
+	return scope.traceCall("sls", func() (interface{}, error) { return do(client.logconn) })
 }
 
 func (client *AliyunClient) WithDrdsClient(do func(*drds.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.drdsconn != nil && !client.config.needRefreshCredential() {
-		return do(client.drdsconn)
+		return scope.traceCall("drds", func() (interface{}, error) { return do(client.drdsconn) })
 	}
 	product := "drds"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -1228,12 +1356,14 @@ func (client *AliyunClient) WithDrdsClient(do func(*drds.Client) (interface{}, e
 	drdsconn.SourceIp = client.config.SourceIp
 	drdsconn.SecureTransport = client.config.SecureTransport
 	client.drdsconn = drdsconn
-	return do(client.drdsconn)
+	return scope.traceCall("drds", func() (interface{}, error) { return do(client.drdsconn) })
 }
 
 func (client *AliyunClient) WithDdsClient(do func(*dds.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.ddsconn != nil && !client.config.needRefreshCredential() {
-		return do(client.ddsconn)
+		return scope.traceCall("dds", func() (interface{}, error) { return do(client.ddsconn) })
 	}
 	product := "dds"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -1271,12 +1401,14 @@ func (client *AliyunClient) WithDdsClient(do func(*dds.Client) (interface{}, err
 	ddsconn.SecureTransport = client.config.SecureTransport
 	client.ddsconn = ddsconn
 
-	return do(client.ddsconn)
+	return scope.traceCall("dds", func() (interface{}, error) { return do(client.ddsconn) })
 }
 
 func (client *AliyunClient) WithGpdbClient(do func(*gpdb.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.gpdbconn != nil && !client.config.needRefreshCredential() {
-		return do(client.gpdbconn)
+		return scope.traceCall("gpdb", func() (interface{}, error) { return do(client.gpdbconn) })
 	}
 	product := "gpdb"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -1313,14 +1445,16 @@ func (client *AliyunClient) WithGpdbClient(do func(*gpdb.Client) (interface{}, e
 	gpdbconn.SourceIp = client.config.SourceIp
 	gpdbconn.SecureTransport = client.config.SecureTransport
 	client.gpdbconn = gpdbconn
-	return do(client.gpdbconn)
+	return scope.traceCall("gpdb", func() (interface{}, error) { return do(client.gpdbconn) })
 }
 
 func (client *AliyunClient) WithFcClient(do func(*fc.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	goSdkMutex.Lock()
 	defer goSdkMutex.Unlock()
 	if client.fcconn != nil && !client.config.needRefreshCredential() {
-		return do(client.fcconn)
+		return scope.traceCall("fc", func() (interface{}, error) { return do(client.fcconn) })
 	}
 	product := "fc"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -1348,12 +1482,14 @@ func (client *AliyunClient) WithFcClient(do func(*fc.Client) (interface{}, error
 	fcconn.Config.SecurityToken = secretToken
 	client.fcconn = fcconn
 
-	return do(client.fcconn)
+	return scope.traceCall("fc", func() (interface{}, error) { return do(client.fcconn) })
 }
 
 func (client *AliyunClient) WithCloudApiClient(do func(*cloudapi.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.cloudapiconn != nil && !client.config.needRefreshCredential() {
-		return do(client.cloudapiconn)
+		return scope.traceCall("cloudapi", func() (interface{}, error) { return do(client.cloudapiconn) })
 	}
 	product := "cloudapi"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -1390,10 +1526,11 @@ func (client *AliyunClient) WithCloudApiClient(do func(*cloudapi.Client) (interf
 	cloudapiconn.SecureTransport = client.config.SecureTransport
 	client.cloudapiconn = cloudapiconn
 
-	return do(client.cloudapiconn)
+	return scope.traceCall("cloudapi", func() (interface{}, error) { return do(client.cloudapiconn) })
 }
 
 func (client *AliyunClient) NewTeaCommonClient(endpoint string) (*rpc.Client, error) {
+	client = client.shared()
 	sdkConfig := client.teaSdkConfig
 	sdkConfig.SetEndpoint(endpoint)
 	credential, err := client.config.Credential.GetCredential()
@@ -1413,6 +1550,7 @@ func (client *AliyunClient) NewTeaCommonClient(endpoint string) (*rpc.Client, er
 	return conn, nil
 }
 func (client *AliyunClient) NewTeaRoaCommonClient(endpoint string) (*roa.Client, error) {
+	client = client.shared()
 	sdkConfig := client.teaRoaSdkConfig
 	sdkConfig.SetEndpoint(endpoint)
 	credential, err := client.config.Credential.GetCredential()
@@ -1433,6 +1571,8 @@ func (client *AliyunClient) NewTeaRoaCommonClient(endpoint string) (*roa.Client,
 }
 
 func (client *AliyunClient) WithDataHubClient(do func(api datahub.DataHubApi) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	goSdkMutex.Lock()
 	defer goSdkMutex.Unlock()
 
@@ -1461,12 +1601,14 @@ func (client *AliyunClient) WithDataHubClient(do func(api datahub.DataHubApi) (i
 		client.dhconn = datahub.NewClientWithConfig(endpoint, config, account)
 	}
 
-	return do(client.dhconn)
+	return scope.traceCall("datahub", func() (interface{}, error) { return do(client.dhconn) })
 }
 
 func (client *AliyunClient) WithElasticsearchClient(do func(*elasticsearch.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.elasticsearchconn != nil && !client.config.needRefreshCredential() {
-		return do(client.elasticsearchconn)
+		return scope.traceCall("elasticsearch", func() (interface{}, error) { return do(client.elasticsearchconn) })
 	}
 	product := "elasticsearch"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -1503,10 +1645,12 @@ func (client *AliyunClient) WithElasticsearchClient(do func(*elasticsearch.Clien
 	elasticsearchconn.SecureTransport = client.config.SecureTransport
 	client.elasticsearchconn = elasticsearchconn
 
-	return do(client.elasticsearchconn)
+	return scope.traceCall("elasticsearch", func() (interface{}, error) { return do(client.elasticsearchconn) })
 }
 
 func (client *AliyunClient) WithMnsClient(do func(*ali_mns.MNSClient) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	goSdkMutex.Lock()
 	defer goSdkMutex.Unlock()
 
@@ -1543,7 +1687,7 @@ func (client *AliyunClient) WithMnsClient(do func(*ali_mns.MNSClient) (interface
 		client.mnsconn = &mnsClient
 	}
 
-	return do(client.mnsconn)
+	return scope.traceCall("mns", func() (interface{}, error) { return do(client.mnsconn) })
 }
 func (client *AliyunClient) WithMnsQueueManager(do func(ali_mns.AliQueueManager) (interface{}, error)) (interface{}, error) {
 	return client.WithMnsClient(func(mnsClient *ali_mns.MNSClient) (interface{}, error) {
@@ -1567,13 +1711,15 @@ func (client *AliyunClient) WithMnsSubscriptionManagerByTopicName(topicName stri
 }
 
 func (client *AliyunClient) WithTableStoreClient(instanceName string, do func(*tablestore.TableStoreClient) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	goSdkMutex.Lock()
 	defer goSdkMutex.Unlock()
 
 	// Initialize the TABLESTORE client if necessary
 	tableStoreClient, ok := client.tablestoreconnByInstanceName[instanceName]
 	if ok && !client.config.needRefreshCredential() {
-		return do(tableStoreClient)
+		return scope.traceCall("ots", func() (interface{}, error) { return do(tableStoreClient) })
 	}
 	endpoint := client.config.OtsEndpoint
 	if endpoint == "" {
@@ -1596,17 +1742,19 @@ func (client *AliyunClient) WithTableStoreClient(instanceName string, do func(*t
 	tableStoreClient = tablestore.NewClientWithExternalHeader(endpoint, instanceName, accessKey, secretKey, token, tablestore.NewDefaultTableStoreConfig(), externalHeaders)
 	client.tablestoreconnByInstanceName[instanceName] = tableStoreClient
 
-	return do(tableStoreClient)
+	return scope.traceCall("ots", func() (interface{}, error) { return do(tableStoreClient) })
 }
 
 func (client *AliyunClient) WithTableStoreTunnelClient(instanceName string, do func(otsTunnel.TunnelClient) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	goSdkMutex.Lock()
 	defer goSdkMutex.Unlock()
 
 	// Initialize the TABLESTORE tunnel client if necessary
 	tunnelClient, ok := client.otsTunnelConnByInstanceName[instanceName]
 	if ok && !client.config.needRefreshCredential() {
-		return do(tunnelClient)
+		return scope.traceCall("ots", func() (interface{}, error) { return do(tunnelClient) })
 	}
 	endpoint := client.config.OtsEndpoint
 	if endpoint == "" {
@@ -1630,10 +1778,12 @@ func (client *AliyunClient) WithTableStoreTunnelClient(instanceName string, do f
 	tunnelClient = otsTunnel.NewTunnelClientWithConfigAndExternalHeader(endpoint, instanceName, accessKey, secretKey, token, otsTunnel.DefaultTunnelConfig, externalHeaders)
 	client.otsTunnelConnByInstanceName[instanceName] = tunnelClient
 
-	return do(tunnelClient)
+	return scope.traceCall("ots", func() (interface{}, error) { return do(tunnelClient) })
 }
 
 func (client *AliyunClient) WithCsProjectClient(clusterId, endpoint string, clusterCerts cs.ClusterCerts, do func(*cs.ProjectClient) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	goSdkMutex.Lock()
 	defer goSdkMutex.Unlock()
 
@@ -1651,10 +1801,11 @@ func (client *AliyunClient) WithCsProjectClient(clusterId, endpoint string, clus
 		client.csprojectconnByKey[key] = csProjectClient
 	}
 
-	return do(csProjectClient)
+	return scope.traceCall("cs", func() (interface{}, error) { return do(csProjectClient) })
 }
 
 func (client *AliyunClient) NewCommonRequest(product, serviceCode, schema string, apiVersion ApiVersion) (*requests.CommonRequest, error) {
+	client = client.shared()
 	endpoint := ""
 	product = strings.ToLower(product)
 	if _, exist := client.config.Endpoints.Load(product); !exist {
@@ -1687,6 +1838,7 @@ func (client *AliyunClient) NewCommonRequest(product, serviceCode, schema string
 }
 
 func (client *AliyunClient) AccountId() (string, error) {
+	client = client.shared()
 	if client.accountId != "" {
 		return client.accountId, nil
 	}
@@ -1712,6 +1864,7 @@ func (client *AliyunClient) AccountId() (string, error) {
 // It then determines whether the account is domestic or international based on the API specific errors.
 // If there is a specific error, the account type should be updates, and meantime corrects the BssOpenApi endpoint.
 func (client *AliyunClient) getAccountType() string {
+	client = client.shared()
 	if client.config.AccountType != "" {
 		return client.config.AccountType
 	}
@@ -1759,9 +1912,11 @@ func (client *AliyunClient) getAccountType() string {
 	return accountType
 }
 func (client *AliyunClient) IsInternationalAccount() bool {
+	client = client.shared()
 	return client.config.AccountType == "International"
 }
 func (client *AliyunClient) isInternationalRegion() bool {
+	client = client.shared()
 	if client.config.RegionId == "cn-hongkong" {
 		return true
 	}
@@ -1769,6 +1924,7 @@ func (client *AliyunClient) isInternationalRegion() bool {
 }
 
 func (client *AliyunClient) getSdkConfig(timeout time.Duration) *sdk.Config {
+	client = client.shared()
 	if timeout == 0 {
 		timeout = time.Duration(30) * time.Second
 	}
@@ -1786,10 +1942,12 @@ func (client *AliyunClient) getSdkConfig(timeout time.Duration) *sdk.Config {
 }
 
 func (client *AliyunClient) getUserAgent() string {
+	client = client.shared()
 	return fmt.Sprintf("%s/%s %s/%s %s/%s", Terraform, client.config.TerraformVersion, Provider, providerVersion, Module, client.config.ConfigurationSource)
 }
 
 func (client *AliyunClient) getTransport() *http.Transport {
+	client = client.shared()
 	handshakeTimeout, err := strconv.Atoi(os.Getenv("TLSHandshakeTimeout"))
 	if err != nil {
 		handshakeTimeout = 120
@@ -1801,6 +1959,7 @@ func (client *AliyunClient) getTransport() *http.Transport {
 }
 
 func (client *AliyunClient) getHttpProxy() (proxy *url.URL, err error) {
+	client = client.shared()
 	if client.config.Protocol == "HTTPS" {
 		if rawurl := os.Getenv("HTTPS_PROXY"); rawurl != "" {
 			proxy, err = url.Parse(rawurl)
@@ -1818,6 +1977,7 @@ func (client *AliyunClient) getHttpProxy() (proxy *url.URL, err error) {
 }
 
 func (client *AliyunClient) skipProxy(endpoint string) (bool, error) {
+	client = client.shared()
 	var urls []string
 	if rawurl := os.Getenv("NO_PROXY"); rawurl != "" {
 		urls = strings.Split(rawurl, ",")
@@ -1840,6 +2000,7 @@ func (client *AliyunClient) skipProxy(endpoint string) (bool, error) {
 }
 
 func (client *AliyunClient) GetCallerIdentity() (*sts.GetCallerIdentityResponse, error) {
+	client = client.shared()
 	args := sts.CreateGetCallerIdentityRequest()
 
 	endpoint := client.config.StsEndpoint
@@ -1869,8 +2030,10 @@ func (client *AliyunClient) GetCallerIdentity() (*sts.GetCallerIdentityResponse,
 	return identity, err
 }
 func (client *AliyunClient) WithDdosbgpClient(do func(*ddosbgp.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.ddosbgpconn != nil && !client.config.needRefreshCredential() {
-		return do(client.ddosbgpconn)
+		return scope.traceCall("ddosbgp", func() (interface{}, error) { return do(client.ddosbgpconn) })
 	}
 	product := "ddosbgp"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -1907,11 +2070,13 @@ func (client *AliyunClient) WithDdosbgpClient(do func(*ddosbgp.Client) (interfac
 	ddosbgpconn.SecureTransport = client.config.SecureTransport
 	client.ddosbgpconn = ddosbgpconn
 
-	return do(client.ddosbgpconn)
+	return scope.traceCall("ddosbgp", func() (interface{}, error) { return do(client.ddosbgpconn) })
 }
 func (client *AliyunClient) WithAlikafkaClient(do func(*alikafka.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.alikafkaconn != nil && !client.config.needRefreshCredential() {
-		return do(client.alikafkaconn)
+		return scope.traceCall("alikafka", func() (interface{}, error) { return do(client.alikafkaconn) })
 	}
 	product := "alikafka"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -1948,12 +2113,14 @@ func (client *AliyunClient) WithAlikafkaClient(do func(*alikafka.Client) (interf
 	alikafkaconn.SecureTransport = client.config.SecureTransport
 	client.alikafkaconn = alikafkaconn
 
-	return do(client.alikafkaconn)
+	return scope.traceCall("alikafka", func() (interface{}, error) { return do(client.alikafkaconn) })
 }
 
 func (client *AliyunClient) WithEmrClient(do func(*emr.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.emrconn != nil && !client.config.needRefreshCredential() {
-		return do(client.emrconn)
+		return scope.traceCall("emr", func() (interface{}, error) { return do(client.emrconn) })
 	}
 	product := "emr"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -1990,12 +2157,14 @@ func (client *AliyunClient) WithEmrClient(do func(*emr.Client) (interface{}, err
 	emrConn.SecureTransport = client.config.SecureTransport
 	client.emrconn = emrConn
 
-	return do(client.emrconn)
+	return scope.traceCall("emr", func() (interface{}, error) { return do(client.emrconn) })
 }
 
 func (client *AliyunClient) WithSagClient(do func(*smartag.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.sagconn != nil && !client.config.needRefreshCredential() {
-		return do(client.sagconn)
+		return scope.traceCall("smartag", func() (interface{}, error) { return do(client.sagconn) })
 	}
 	product := "smartag"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -2032,12 +2201,14 @@ func (client *AliyunClient) WithSagClient(do func(*smartag.Client) (interface{},
 	sagconn.SecureTransport = client.config.SecureTransport
 	client.sagconn = sagconn
 
-	return do(client.sagconn)
+	return scope.traceCall("smartag", func() (interface{}, error) { return do(client.sagconn) })
 }
 
 func (client *AliyunClient) WithDbauditClient(do func(*yundun_dbaudit.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.dbauditconn != nil && !client.config.needRefreshCredential() {
-		return do(client.dbauditconn)
+		return scope.traceCall("yundun_dbaudit", func() (interface{}, error) { return do(client.dbauditconn) })
 	}
 	product := "yundun_dbaudit"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -2074,11 +2245,13 @@ func (client *AliyunClient) WithDbauditClient(do func(*yundun_dbaudit.Client) (i
 	dbauditconn.SecureTransport = client.config.SecureTransport
 	client.dbauditconn = dbauditconn
 
-	return do(client.dbauditconn)
+	return scope.traceCall("yundun_dbaudit", func() (interface{}, error) { return do(client.dbauditconn) })
 }
 func (client *AliyunClient) WithMarketClient(do func(*market.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.marketconn != nil && !client.config.needRefreshCredential() {
-		return do(client.marketconn)
+		return scope.traceCall("market", func() (interface{}, error) { return do(client.marketconn) })
 	}
 	product := "market"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -2115,12 +2288,14 @@ func (client *AliyunClient) WithMarketClient(do func(*market.Client) (interface{
 	marketconn.SecureTransport = client.config.SecureTransport
 	client.marketconn = marketconn
 
-	return do(client.marketconn)
+	return scope.traceCall("market", func() (interface{}, error) { return do(client.marketconn) })
 }
 
 func (client *AliyunClient) WithHbaseClient(do func(*hbase.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.hbaseconn != nil && !client.config.needRefreshCredential() {
-		return do(client.hbaseconn)
+		return scope.traceCall("hbase", func() (interface{}, error) { return do(client.hbaseconn) })
 	}
 	product := "hbase"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -2158,12 +2333,14 @@ func (client *AliyunClient) WithHbaseClient(do func(*hbase.Client) (interface{},
 
 	client.hbaseconn = hbaseconn
 
-	return do(client.hbaseconn)
+	return scope.traceCall("hbase", func() (interface{}, error) { return do(client.hbaseconn) })
 }
 
 func (client *AliyunClient) WithAdbClient(do func(*adb.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.adbconn != nil && !client.config.needRefreshCredential() {
-		return do(client.adbconn)
+		return scope.traceCall("adb", func() (interface{}, error) { return do(client.adbconn) })
 	}
 	product := "adb"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -2201,11 +2378,13 @@ func (client *AliyunClient) WithAdbClient(do func(*adb.Client) (interface{}, err
 	adbconn.SecureTransport = client.config.SecureTransport
 	client.adbconn = adbconn
 
-	return do(client.adbconn)
+	return scope.traceCall("adb", func() (interface{}, error) { return do(client.adbconn) })
 }
 func (client *AliyunClient) WithCbnClient(do func(*cbn.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.cbnConn != nil && !client.config.needRefreshCredential() {
-		return do(client.cbnConn)
+		return scope.traceCall("cbn", func() (interface{}, error) { return do(client.cbnConn) })
 	}
 	product := "cbn"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -2242,12 +2421,14 @@ func (client *AliyunClient) WithCbnClient(do func(*cbn.Client) (interface{}, err
 	cbnConn.SecureTransport = client.config.SecureTransport
 	client.cbnConn = cbnConn
 
-	return do(client.cbnConn)
+	return scope.traceCall("cbn", func() (interface{}, error) { return do(client.cbnConn) })
 }
 
 func (client *AliyunClient) WithEdasClient(do func(*edas.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.edasconn != nil && !client.config.needRefreshCredential() {
-		return do(client.edasconn)
+		return scope.traceCall("edas", func() (interface{}, error) { return do(client.edasconn) })
 	}
 	product := "edas"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -2284,12 +2465,14 @@ func (client *AliyunClient) WithEdasClient(do func(*edas.Client) (interface{}, e
 	edasconn.SecureTransport = client.config.SecureTransport
 	client.edasconn = edasconn
 
-	return do(client.edasconn)
+	return scope.traceCall("edas", func() (interface{}, error) { return do(client.edasconn) })
 }
 
 func (client *AliyunClient) WithAlidnsClient(do func(*alidns.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.alidnsConn != nil && !client.config.needRefreshCredential() {
-		return do(client.alidnsConn)
+		return scope.traceCall("alidns", func() (interface{}, error) { return do(client.alidnsConn) })
 	}
 	product := "alidns"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -2327,12 +2510,14 @@ func (client *AliyunClient) WithAlidnsClient(do func(*alidns.Client) (interface{
 	alidnsConn.SourceIp = client.config.SourceIp
 	alidnsConn.SecureTransport = client.config.SecureTransport
 	client.alidnsConn = alidnsConn
-	return do(client.alidnsConn)
+	return scope.traceCall("alidns", func() (interface{}, error) { return do(client.alidnsConn) })
 }
 
 func (client *AliyunClient) WithCassandraClient(do func(*cassandra.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.cassandraConn != nil && !client.config.needRefreshCredential() {
-		return do(client.cassandraConn)
+		return scope.traceCall("cassandra", func() (interface{}, error) { return do(client.cassandraConn) })
 	}
 	product := "cassandra"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -2368,12 +2553,14 @@ func (client *AliyunClient) WithCassandraClient(do func(*cassandra.Client) (inte
 	cassandraConn.SourceIp = client.config.SourceIp
 	cassandraConn.SecureTransport = client.config.SecureTransport
 	client.cassandraConn = cassandraConn
-	return do(client.cassandraConn)
+	return scope.traceCall("cassandra", func() (interface{}, error) { return do(client.cassandraConn) })
 }
 
 func (client *AliyunClient) WithEciClient(do func(*eci.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.eciConn != nil && !client.config.needRefreshCredential() {
-		return do(client.eciConn)
+		return scope.traceCall("eci", func() (interface{}, error) { return do(client.eciConn) })
 	}
 	product := "eci"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -2411,11 +2598,13 @@ func (client *AliyunClient) WithEciClient(do func(*eci.Client) (interface{}, err
 	eciConn.SourceIp = client.config.SourceIp
 	eciConn.SecureTransport = client.config.SecureTransport
 	client.eciConn = eciConn
-	return do(client.eciConn)
+	return scope.traceCall("eci", func() (interface{}, error) { return do(client.eciConn) })
 }
 func (client *AliyunClient) WithRKvstoreClient(do func(*r_kvstore.Client) (interface{}, error)) (interface{}, error) {
+	scope := client.traceScope
+	client = client.shared()
 	if client.r_kvstoreConn != nil && !client.config.needRefreshCredential() {
-		return do(client.r_kvstoreConn)
+		return scope.traceCall("r_kvstore", func() (interface{}, error) { return do(client.r_kvstoreConn) })
 	}
 	product := "r_kvstore"
 	endpoint, err := client.loadApiEndpoint(product)
@@ -2452,10 +2641,11 @@ func (client *AliyunClient) WithRKvstoreClient(do func(*r_kvstore.Client) (inter
 	r_kvstoreConn.SecureTransport = client.config.SecureTransport
 	client.r_kvstoreConn = r_kvstoreConn
 
-	return do(client.r_kvstoreConn)
+	return scope.traceCall("r_kvstore", func() (interface{}, error) { return do(client.r_kvstoreConn) })
 }
 
 func (client *AliyunClient) NewQuotasClientV2() (*openapi.Client, error) {
+	client = client.shared()
 	productCode := "quotas"
 	endpoint := ""
 	if v, ok := client.config.Endpoints.Load(productCode); !ok || v.(string) == "" {
@@ -2481,6 +2671,7 @@ func (client *AliyunClient) NewQuotasClientV2() (*openapi.Client, error) {
 	return result, nil
 }
 func (client *AliyunClient) loadApiEndpoint(productCode string) (string, error) {
+	client = client.shared()
 	configEndpoints := productCodeToConfigEndpoints[productCode]
 	if configEndpoints == "" {
 		configEndpoints = productCode
@@ -2538,8 +2729,11 @@ func (client *AliyunClient) RpcGet(apiProductCode string, apiVersion string, api
 	return client.rpcRequest("GET", apiProductCode, apiVersion, apiName, query, body, true, "")
 }
 
-func (client *AliyunClient) rpcRequest(method string, apiProductCode string, apiVersion string, apiName string, query map[string]interface{}, body map[string]interface{}, autoRetry bool, endpoint string) (map[string]interface{}, error) {
-	var err error
+func (client *AliyunClient) rpcRequest(method string, apiProductCode string, apiVersion string, apiName string, query map[string]interface{}, body map[string]interface{}, autoRetry bool, endpoint string) (result map[string]interface{}, err error) {
+	scope := client.traceScope
+	client = client.shared()
+	span := scope.startApiSpan(apiProductCode, apiName)
+	defer func() { span.end(result, err) }()
 	if endpoint == "" {
 		apiProductCode = strings.ToLower(ConvertKebabToSnake(apiProductCode))
 		endpoint, err = client.loadApiEndpoint(apiProductCode)
@@ -2709,7 +2903,15 @@ func (client *AliyunClient) RoaPatchWithApiName(apiProductCode string, apiVersio
 	return client.roaRequest("PATCH", apiProductCode, apiVersion, apiName, pathName, query, headers, body, autoRetry)
 }
 
-func (client *AliyunClient) roaRequest(method string, apiProductCode string, apiVersion string, apiName string, pathName string, query map[string]*string, headers map[string]*string, body interface{}, autoRetry bool) (map[string]interface{}, error) {
+func (client *AliyunClient) roaRequest(method string, apiProductCode string, apiVersion string, apiName string, pathName string, query map[string]*string, headers map[string]*string, body interface{}, autoRetry bool) (result map[string]interface{}, err error) {
+	scope := client.traceScope
+	client = client.shared()
+	action := apiName
+	if action == "" {
+		action = method + " " + pathName
+	}
+	span := scope.startApiSpan(apiProductCode, action)
+	defer func() { span.end(result, err) }()
 	apiProductCode = strings.ToLower(ConvertKebabToSnake(apiProductCode))
 	endpoint, err := client.loadApiEndpoint(apiProductCode)
 	if err != nil {
@@ -2763,7 +2965,11 @@ func normalizeOssOpenAPIResponse(response map[string]interface{}) (map[string]in
 //	headers - API parameters in headers
 //	hostMap - API parameters in hostMap
 //	autoRetry - whether to auto retry while the runtime has a 5xx error
-func (client *AliyunClient) Do(apiProductCode string, apiParams *openapi.Params, query map[string]*string, body interface{}, headers map[string]*string, hostMap map[string]*string, autoRetry bool) (map[string]interface{}, error) {
+func (client *AliyunClient) Do(apiProductCode string, apiParams *openapi.Params, query map[string]*string, body interface{}, headers map[string]*string, hostMap map[string]*string, autoRetry bool) (result map[string]interface{}, err error) {
+	scope := client.traceScope
+	client = client.shared()
+	span := scope.startApiSpan(apiProductCode, tea.StringValue(apiParams.Action))
+	defer func() { span.end(result, err) }()
 	apiProductCode = strings.ToLower(ConvertKebabToSnake(apiProductCode))
 	endpoint, err := client.loadApiEndpoint(apiProductCode)
 	if err != nil {
@@ -2932,6 +3138,7 @@ func formatError(response map[string]interface{}, err error) error {
 }
 
 func (client *AliyunClient) formatEndpointWithAccountID(productCode string, endpoint string) (string, error) {
+	client = client.shared()
 	if endpoint == "" {
 		return endpoint, nil
 	}
@@ -2994,6 +3201,7 @@ func (p *ossV2CredentialsProvider) GetCredentials(_ context.Context) (ossv2cred.
 }
 
 func (client *AliyunClient) GetRetryTimeout(defaultTimeout time.Duration) time.Duration {
+	client = client.shared()
 
 	maxRetryTimeout := client.config.MaxRetryTimeout
 	if maxRetryTimeout != 0 {
@@ -545,6 +545,7 @@ var regularProductEndpointReplace = map[string]string{
 
 // NOTE: The productCode must be lowed.
 func (client *AliyunClient) loadEndpoint(productCode string) error {
+	client = client.shared()
 	// Firstly, load endpoint from environment variables
 	endpoint := strings.TrimSpace(os.Getenv(fmt.Sprintf("ALIBABA_CLOUD_ENDPOINT_%s", strings.ToUpper(productCode))))
 	if endpoint == "" {
@@ -645,6 +646,7 @@ func incrementalWait(firstDuration time.Duration, increaseDuration time.Duration
 	}
 }
 func (client *AliyunClient) describeEndpointForService(productCode string) (string, error) {
+	client = client.shared()
 	locationCode := productCodeToLocationCode[productCode]
 	if locationCode == "" {
 		locationCode = productCode
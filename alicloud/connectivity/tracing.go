@@ -0,0 +1,417 @@
+package connectivity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alibabacloud-go/tea/tea"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/aliyun/terraform-provider-alicloud"
+
+// The tracer is only set when the OTEL_* environment variables configure an OTLP trace exporter. Without it, nothing
+// is traced and clients are used as they are.
+var (
+	tracingOnce    sync.Once
+	tracer         trace.Tracer
+	tracerProvider *sdktrace.TracerProvider
+	// tracingRoot is the parent of the operation spans, which continues the trace given by TRACEPARENT if any.
+	tracingRoot = context.Background()
+)
+
+// tracingProtocol returns the OTLP protocol the environment configures for traces, or "" when traces are not exported.
+func tracingProtocol() string {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return ""
+	}
+	exporter := strings.TrimSpace(os.Getenv("OTEL_TRACES_EXPORTER"))
+	if exporter != "" && exporter != "otlp" {
+		return ""
+	}
+	if exporter == "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return ""
+	}
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	if protocol == "" {
+		protocol = "http/protobuf"
+	}
+	return protocol
+}
+
+// InitTracing sets up the export of traces when the standard OTEL_* environment variables configure an OTLP exporter:
+// OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT, or OTEL_TRACES_EXPORTER=otlp. The exporter, the
+// sampler, the batching and the resource attributes read the rest of the OTEL_* variables. It only runs once.
+func InitTracing() error {
+	var err error
+	tracingOnce.Do(func() {
+		protocol := tracingProtocol()
+		if protocol == "" {
+			return
+		}
+		ctx := context.Background()
+		var exporter sdktrace.SpanExporter
+		switch protocol {
+		case "grpc":
+			exporter, err = otlptracegrpc.New(ctx)
+		case "http/protobuf":
+			exporter, err = otlptracehttp.New(ctx)
+		default:
+			err = fmt.Errorf("the OTLP protocol %s is not supported, expected grpc or http/protobuf", protocol)
+		}
+		if err != nil {
+			err = fmt.Errorf("unable to initialize the trace exporter: %v", err)
+			return
+		}
+		res, resErr := sdkresource.Merge(sdkresource.NewSchemaless(
+			attribute.String("service.name", "terraform-provider-alicloud"),
+			attribute.String("service.version", providerVersion),
+		), sdkresource.Environment())
+		if resErr != nil {
+			log.Printf("[WARN] ignore the resource attributes of OTEL_RESOURCE_ATTRIBUTES: %v", resErr)
+			res = sdkresource.NewSchemaless(attribute.String("service.name", "terraform-provider-alicloud"))
+		}
+		tracerProvider = sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+		tracer = tracerProvider.Tracer(tracerName, trace.WithInstrumentationVersion(providerVersion))
+		tracingRoot = propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{
+			"traceparent": os.Getenv("TRACEPARENT"),
+			"tracestate":  os.Getenv("TRACESTATE"),
+		})
+		log.Printf("[INFO] exporting traces over OTLP %s.", protocol)
+	})
+	return err
+}
+
+// ShutdownTracing exports the spans that are still buffered. The provider calls it before its process exits.
+func ShutdownTracing() {
+	if tracerProvider == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tracerProvider.Shutdown(ctx); err != nil {
+		log.Printf("[WARN] unable to export the remaining spans: %v", err)
+	}
+}
+
+// TracingEnabled reports whether traces are exported.
+func TracingEnabled() bool {
+	return tracer != nil
+}
+
+// traceScope is the trace of one operation, shared by the handle that runs it and by the goroutines it fetches and
+// retries in.
+type traceScope struct {
+	ctx context.Context
+	// client is the client the operation was started from, which every method of its handle runs on.
+	client *AliyunClient
+	mutex  sync.Mutex
+	// wait is the context of the wait loop in progress, which the API calls it polls with are children of.
+	wait     context.Context
+	waitSpan trace.Span
+	// pendingRetries counts, by action, the failed calls that are being retried, see TraceRetry.
+	pendingRetries map[string]int
+	// failed are the errors of the calls that may still be retried, which are forgotten when the operation ends.
+	failed  []error
+	calls   int
+	retries int
+}
+
+// failedCalls maps the error of a failed API call to the call, so that TraceRetry can tell which call is retried.
+var failedCalls sync.Map
+
+type failedCall struct {
+	scope   *traceScope
+	key     string
+	retries int
+}
+
+// StartTrace starts the span of an operation, such as the creation of a resource. It returns the handle to run the
+// operation with, whose API calls and wait loops are traced as children of the span, and the function that ends the
+// span with the result of the operation. The handle runs every method on client, so its connections, credentials
+// and regional clients are the ones of client. Without tracing, it returns the client itself.
+func (client *AliyunClient) StartTrace(name string, attributes map[string]string) (*AliyunClient, func(error)) {
+	client = client.shared()
+	if tracer == nil {
+		return client, func(error) {}
+	}
+	attrs := []attribute.KeyValue{attribute.String("alicloud.region", client.RegionId)}
+	if client.config != nil && client.config.TerraformTraceId != "" {
+		attrs = append(attrs, attribute.String("alicloud.terraform_trace_id", client.config.TerraformTraceId))
+	}
+	for key, value := range attributes {
+		attrs = append(attrs, attribute.String(key, value))
+	}
+	ctx, span := tracer.Start(tracingRoot, name, trace.WithAttributes(attrs...))
+	scope := &traceScope{ctx: ctx, client: client, pendingRetries: make(map[string]int)}
+
+	handle := &AliyunClient{
+		Region:          client.Region,
+		RegionId:        client.RegionId,
+		SourceIp:        client.SourceIp,
+		SecureTransport: client.SecureTransport,
+		AccessKey:       client.AccessKey,
+		SecretKey:       client.SecretKey,
+		SecurityToken:   client.SecurityToken,
+		OtsInstanceName: client.OtsInstanceName,
+		Features:        client.Features,
+		traceScope:      scope,
+	}
+
+	return handle, func(err error) {
+		scope.mutex.Lock()
+		if scope.waitSpan != nil {
+			scope.waitSpan.End()
+			scope.wait, scope.waitSpan = nil, nil
+		}
+		for _, failed := range scope.failed {
+			failedCalls.Delete(failed)
+		}
+		scope.failed = nil
+		span.SetAttributes(attribute.Int("alicloud.api.calls", scope.calls), attribute.Int("alicloud.api.retries", scope.retries))
+		scope.mutex.Unlock()
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// shared returns the client a handle was started from, or the client itself when it is not a handle.
+func (client *AliyunClient) shared() *AliyunClient {
+	if client.traceScope != nil {
+		return client.traceScope.client
+	}
+	return client
+}
+
+// TraceWait traces a wait loop polled by refresh as a span that starts with its first poll, and ends when a poll
+// reaches one of target or fails, or when the operation ends. The API calls of the polls are children of the span.
+// refresh is returned as it is when the client is not the handle of a traced operation.
+func (client *AliyunClient) TraceWait(target []string, refresh resource.StateRefreshFunc) resource.StateRefreshFunc {
+	scope := client.traceScope
+	if scope == nil || refresh == nil {
+		return refresh
+	}
+	polls := 0
+	var span trace.Span
+	return func() (interface{}, string, error) {
+		scope.mutex.Lock()
+		if span == nil {
+			var ctx context.Context
+			ctx, span = tracer.Start(scope.ctx, "wait", trace.WithAttributes(attribute.StringSlice("alicloud.wait.target", target)))
+			scope.wait, scope.waitSpan = ctx, span
+		}
+		scope.mutex.Unlock()
+
+		object, state, err := refresh()
+
+		scope.mutex.Lock()
+		defer scope.mutex.Unlock()
+		polls++
+		if scope.waitSpan != span {
+			return object, state, err
+		}
+		span.SetAttributes(attribute.Int("alicloud.wait.polls", polls), attribute.String("alicloud.wait.state", state))
+		done := err != nil
+		for _, t := range target {
+			done = done || state == t
+		}
+		if done {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
+			scope.wait, scope.waitSpan = nil, nil
+		}
+		return object, state, err
+	}
+}
+
+// TraceRetry records that the API call that failed with err is retried, so the next call of the same action in its
+// operation counts as a retry. It is called on the retry path of the resources, see NeedRetry, and does nothing for
+// an error that is not the one of a traced call.
+func TraceRetry(err error) {
+	if tracer == nil || err == nil || reflect.TypeOf(err).Kind() != reflect.Ptr {
+		return
+	}
+	v, ok := failedCalls.LoadAndDelete(err)
+	if !ok {
+		return
+	}
+	call := v.(failedCall)
+	call.scope.mutex.Lock()
+	call.scope.pendingRetries[call.key] = call.retries + 1
+	call.scope.retries++
+	call.scope.mutex.Unlock()
+}
+
+// apiSpan is the span of an API call made by an operation that is traced.
+type apiSpan struct {
+	scope   *traceScope
+	span    trace.Span
+	product string
+	action  string
+}
+
+// startApiSpan starts the span of an API call. It returns nil when the operation that makes the call is not traced,
+// and the action may be empty when only the response tells it.
+func (scope *traceScope) startApiSpan(product, action string) *apiSpan {
+	if scope == nil {
+		return nil
+	}
+	product = strings.ToLower(ConvertKebabToSnake(product))
+	scope.mutex.Lock()
+	parent := scope.ctx
+	if scope.wait != nil {
+		parent = scope.wait
+	}
+	scope.mutex.Unlock()
+	_, span := tracer.Start(parent, apiSpanName(product, action), trace.WithSpanKind(trace.SpanKindClient))
+	return &apiSpan{scope: scope, span: span, product: product, action: action}
+}
+
+func apiSpanName(product, action string) string {
+	if action == "" {
+		return product
+	}
+	return product + "/" + action
+}
+
+// end ends the span of an API call with its result. The call is a retry when TraceRetry was told that the previous
+// call of its action failed and is retried; polls and pages of the same action are not.
+func (s *apiSpan) end(response interface{}, err error) {
+	if s == nil {
+		return
+	}
+	if s.action == "" {
+		if s.action = apiActionOf(response); s.action != "" {
+			s.span.SetName(apiSpanName(s.product, s.action))
+		}
+	}
+	key := s.product + "/" + s.action
+	s.scope.mutex.Lock()
+	retries := s.scope.pendingRetries[key]
+	delete(s.scope.pendingRetries, key)
+	if err != nil && reflect.TypeOf(err).Kind() == reflect.Ptr {
+		failedCalls.Store(err, failedCall{scope: s.scope, key: key, retries: retries})
+		s.scope.failed = append(s.scope.failed, err)
+	}
+	s.scope.calls++
+	s.scope.mutex.Unlock()
+
+	s.span.SetAttributes(
+		attribute.String("alicloud.product", s.product),
+		attribute.String("alicloud.action", s.action),
+		attribute.Int("alicloud.retries", retries),
+	)
+	requestId, code := apiRequestIdOf(response), ""
+	if err != nil {
+		var errRequestId string
+		code, errRequestId = apiErrorOf(err)
+		if requestId == "" {
+			requestId = errRequestId
+		}
+		s.span.SetAttributes(attribute.String("alicloud.error_code", code))
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	if requestId != "" {
+		s.span.SetAttributes(attribute.String("alicloud.request_id", requestId))
+	}
+	s.span.End()
+}
+
+// traceCall traces an API call of the SDKs of the With*Client functions, whose action is told by the type of its
+// response, such as *ecs.DescribeInstancesResponse.
+func (scope *traceScope) traceCall(product string, do func() (interface{}, error)) (interface{}, error) {
+	span := scope.startApiSpan(product, "")
+	if span == nil {
+		return do()
+	}
+	response, err := do()
+	span.end(response, err)
+	return response, err
+}
+
+func apiActionOf(response interface{}) string {
+	t := reflect.TypeOf(response)
+	if t == nil {
+		return ""
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	name := strings.TrimSuffix(t.Name(), "Response")
+	if name == "" || name == t.Name() {
+		return ""
+	}
+	return name
+}
+
+func apiRequestIdOf(response interface{}) string {
+	switch v := response.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}:
+		for _, key := range []string{"RequestId", "requestId", "request_id"} {
+			if id, ok := v[key]; ok && id != nil {
+				return fmt.Sprint(id)
+			}
+		}
+		return ""
+	}
+	value := reflect.ValueOf(response)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return ""
+	}
+	if id := value.FieldByName("RequestId"); id.IsValid() && id.Kind() == reflect.String {
+		return id.String()
+	}
+	return ""
+}
+
+// apiErrorOf returns the error code and the request ID of a failed API call.
+func apiErrorOf(err error) (code, requestId string) {
+	if sdkErr, ok := err.(*tea.SDKError); ok {
+		code = tea.StringValue(sdkErr.Code)
+		var data map[string]interface{}
+		if json.Unmarshal([]byte(tea.StringValue(sdkErr.Data)), &data) == nil {
+			requestId = apiRequestIdOf(data)
+		}
+		return code, requestId
+	}
+	if e, ok := err.(interface{ ErrorCode() string }); ok {
+		code = e.ErrorCode()
+	}
+	if e, ok := err.(interface{ RequestId() string }); ok {
+		requestId = e.RequestId()
+	}
+	return code, requestId
+}
@@ -0,0 +1,166 @@
+package connectivity
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alibabacloud-go/tea/tea"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestUnitTracingProtocol(t *testing.T) {
+	for _, key := range []string{"OTEL_SDK_DISABLED", "OTEL_TRACES_EXPORTER", "OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "OTEL_EXPORTER_OTLP_PROTOCOL", "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"} {
+		t.Setenv(key, "")
+	}
+	assert.Equal(t, "", tracingProtocol())
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	assert.Equal(t, "http/protobuf", tracingProtocol())
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "grpc")
+	assert.Equal(t, "grpc", tracingProtocol())
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "http/protobuf")
+	assert.Equal(t, "http/protobuf", tracingProtocol())
+
+	t.Setenv("OTEL_TRACES_EXPORTER", "none")
+	assert.Equal(t, "", tracingProtocol())
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp")
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	assert.Equal(t, "", tracingProtocol())
+}
+
+func TestUnitTracingApiResults(t *testing.T) {
+	response := ecs.CreateDescribeInstancesResponse()
+	response.RequestId = "4C3D9A4E-0001"
+	assert.Equal(t, "DescribeInstances", apiActionOf(response))
+	assert.Equal(t, "4C3D9A4E-0001", apiRequestIdOf(response))
+	assert.Equal(t, "", apiActionOf(map[string]interface{}{}))
+	assert.Equal(t, "4C3D9A4E-0002", apiRequestIdOf(map[string]interface{}{"RequestId": "4C3D9A4E-0002"}))
+	assert.Equal(t, "", apiRequestIdOf(nil))
+
+	code, requestId := apiErrorOf(tea.NewSDKError(map[string]interface{}{
+		"code":    "Throttling.User",
+		"message": "Request was denied due to user flow control.",
+		"data":    map[string]interface{}{"RequestId": "4C3D9A4E-0003"},
+	}))
+	assert.Equal(t, "Throttling.User", code)
+	assert.Equal(t, "4C3D9A4E-0003", requestId)
+	code, requestId = apiErrorOf(fmt.Errorf("connection reset"))
+	assert.Equal(t, "", code)
+	assert.Equal(t, "", requestId)
+}
+
+func TestUnitTracingOperation(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer func() {
+		tracer = nil
+	}()
+	tracer = provider.Tracer(tracerName)
+
+	client := &AliyunClient{RegionId: "cn-hangzhou", config: &Config{TerraformTraceId: "trace-1", AccountType: "International"}}
+	traced, end := client.StartTrace("alicloud_instance.create", map[string]string{"terraform.resource.type": "alicloud_instance"})
+	assert.NotSame(t, client, traced)
+	assert.Nil(t, client.traceScope)
+	assert.Same(t, client, traced.shared())
+	assert.Equal(t, "cn-hangzhou", traced.RegionId)
+	// the handle has no config of its own, the account type is the one of the shared client
+	assert.True(t, traced.IsInternationalAccount())
+	regional, err := traced.WithRegion("cn-hangzhou")
+	assert.Nil(t, err)
+	assert.Same(t, traced, regional)
+	// a handle started from a handle still runs on the shared client
+	nested, nestedEnd := traced.StartTrace("nested", nil)
+	assert.Same(t, client, nested.shared())
+	nestedEnd(nil)
+
+	scope := traced.traceScope
+	throttled := tea.NewSDKError(map[string]interface{}{"code": "Throttling", "message": "throttled"})
+	scope.startApiSpan("Ecs", "RunInstances").end(nil, throttled)
+	TraceRetry(throttled)
+	scope.startApiSpan("Ecs", "RunInstances").end(map[string]interface{}{"RequestId": "R-1"}, nil)
+	_, err = scope.traceCall("ecs", func() (interface{}, error) {
+		return ecs.CreateDescribeInstancesResponse(), nil
+	})
+	assert.Nil(t, err)
+
+	polls := 0
+	wait := traced.TraceWait([]string{"Running"}, func() (interface{}, string, error) {
+		polls++
+		scope.startApiSpan("ecs", "DescribeInstances").end(nil, nil)
+		if polls < 3 {
+			return struct{}{}, "Pending", nil
+		}
+		return struct{}{}, "Running", nil
+	})
+	for i := 0; i < 3; i++ {
+		wait()
+	}
+	end(nil)
+
+	spans := exporter.GetSpans()
+	names := make([]string, 0, len(spans))
+	byName := make(map[string]tracetest.SpanStub)
+	for _, span := range spans {
+		names = append(names, span.Name)
+		byName[span.Name] = span
+	}
+	assert.Equal(t, []string{"nested", "ecs/RunInstances", "ecs/RunInstances", "ecs/DescribeInstances", "ecs/DescribeInstances", "ecs/DescribeInstances", "ecs/DescribeInstances", "wait", "alicloud_instance.create"}, names)
+	spans = spans[1:]
+
+	operation := byName["alicloud_instance.create"]
+	assert.Contains(t, operation.Attributes, attribute.String("alicloud.terraform_trace_id", "trace-1"))
+	assert.Contains(t, operation.Attributes, attribute.Int("alicloud.api.calls", 6))
+	assert.Contains(t, operation.Attributes, attribute.Int("alicloud.api.retries", 1))
+	assert.Equal(t, operation.SpanContext.SpanID(), spans[0].Parent.SpanID())
+	assert.Contains(t, spans[0].Attributes, attribute.String("alicloud.error_code", "Throttling"))
+	assert.Contains(t, spans[1].Attributes, attribute.Int("alicloud.retries", 1))
+	assert.Contains(t, spans[1].Attributes, attribute.String("alicloud.request_id", "R-1"))
+	assert.Equal(t, operation.SpanContext.SpanID(), spans[2].Parent.SpanID())
+	assert.Equal(t, byName["wait"].SpanContext.SpanID(), spans[3].Parent.SpanID())
+	assert.Contains(t, byName["wait"].Attributes, attribute.Int("alicloud.wait.polls", 3))
+	assert.Contains(t, byName["wait"].Attributes, attribute.String("alicloud.wait.state", "Running"))
+	// the polls of the same action are not retries
+	for _, span := range spans[2:6] {
+		assert.Contains(t, span.Attributes, attribute.Int("alicloud.retries", 0))
+	}
+	_, pending := failedCalls.Load(throttled)
+	assert.False(t, pending)
+}
+
+func TestUnitTracingRetries(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer func() {
+		tracer = nil
+	}()
+	tracer = provider.Tracer(tracerName)
+
+	client := &AliyunClient{RegionId: "cn-hangzhou", config: &Config{}}
+	traced, end := client.StartTrace("alicloud_vpc.read", nil)
+	scope := traced.traceScope
+	for i := 0; i < 2; i++ {
+		throttled := tea.NewSDKError(map[string]interface{}{"code": "Throttling.User", "message": "throttled"})
+		scope.startApiSpan("vpc", "DescribeVpcs").end(nil, throttled)
+		TraceRetry(throttled)
+	}
+	scope.startApiSpan("vpc", "DescribeVpcs").end(nil, nil)
+	// the next page is not a retry
+	scope.startApiSpan("vpc", "DescribeVpcs").end(nil, nil)
+	denied := tea.NewSDKError(map[string]interface{}{"code": "Forbidden.RAM", "message": "denied"})
+	scope.startApiSpan("vpc", "DescribeVSwitches").end(nil, denied)
+	end(denied)
+	// the errors of calls that were not retried are forgotten with their operation
+	TraceRetry(denied)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 6)
+	for i, retries := range []int{0, 1, 2, 0, 0} {
+		assert.Contains(t, spans[i].Attributes, attribute.Int("alicloud.retries", retries), spans[i].Name)
+	}
+	assert.Contains(t, spans[5].Attributes, attribute.Int("alicloud.api.calls", 5))
+	assert.Contains(t, spans[5].Attributes, attribute.Int("alicloud.api.retries", 2))
+}
@@ -146,6 +146,7 @@ func dataSourceAliCloudAmqpOpenSourcePermissionRead(d *schema.ResourceData, meta
 
 	ids := make([]string, 0)
 	s := make([]map[string]interface{}, 0)
+	detailIds := make([]string, 0)
 	for _, objectRaw := range objects {
 		mapping := map[string]interface{}{}
 
@@ -158,20 +159,24 @@ func dataSourceAliCloudAmqpOpenSourcePermissionRead(d *schema.ResourceData, meta
 		mapping["user_name"] = objectRaw["Name"]
 		mapping["vhost"] = objectRaw["Vhost"]
 
-		if detailedEnabled := d.Get("enable_details"); !detailedEnabled.(bool) {
-			ids = append(ids, fmt.Sprint(mapping["id"]))
-			s = append(s, mapping)
-			continue
-		}
+		ids = append(ids, fmt.Sprint(mapping["id"]))
+		s = append(s, mapping)
+		detailIds = append(detailIds, fmt.Sprint(objectRaw["Name"], ":", objectRaw["Vhost"], ":", objectRaw["CInstanceId"]))
+	}
 
-		id := fmt.Sprint(objectRaw["Name"], ":", objectRaw["Vhost"], ":", objectRaw["CInstanceId"])
-		mapping, err = dataSourceAliCloudAmqpOpenSourcePermissionReadDescription(d, id, mapping, meta)
+	if detailedEnabled := d.Get("enable_details"); detailedEnabled.(bool) {
+		err = fetchParallel(dataSourceFetchLimit(meta), detailIds, func(i int) error {
+			mapping, err := dataSourceAliCloudAmqpOpenSourcePermissionReadDescription(d, detailIds[i], s[i], meta)
+			if err != nil {
+				return err
+			}
+			s[i] = mapping
+			ids[i] = fmt.Sprint(mapping["id"])
+			return nil
+		})
 		if err != nil {
 			return WrapError(err)
 		}
-
-		ids = append(ids, fmt.Sprint(mapping["id"]))
-		s = append(s, mapping)
 	}
 
 	d.SetId(dataResourceIdHash(ids))
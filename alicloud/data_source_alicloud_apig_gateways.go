@@ -423,6 +423,7 @@ func dataSourceAliCloudApigGatewayRead(d *schema.ResourceData, meta interface{})
 	ids := make([]string, 0)
 	names := make([]interface{}, 0)
 	s := make([]map[string]interface{}, 0)
+	detailIds := make([]string, 0)
 	for _, objectRaw := range objects {
 		mapping := map[string]interface{}{}
 
@@ -551,22 +552,25 @@ func dataSourceAliCloudApigGatewayRead(d *schema.ResourceData, meta interface{})
 		}
 		mapping["zones"] = zonesMaps
 
-		if detailedEnabled := d.Get("enable_details"); !detailedEnabled.(bool) {
-			ids = append(ids, fmt.Sprint(mapping["id"]))
-			names = append(names, objectRaw["name"])
-			s = append(s, mapping)
-			continue
-		}
+		ids = append(ids, fmt.Sprint(mapping["id"]))
+		names = append(names, objectRaw["name"])
+		s = append(s, mapping)
+		detailIds = append(detailIds, fmt.Sprint(objectRaw["gatewayId"]))
+	}
 
-		id := fmt.Sprint(objectRaw["gatewayId"])
-		mapping, err = dataSourceAliCloudApigGatewayReadDescription(d, id, mapping, meta)
+	if detailedEnabled := d.Get("enable_details"); detailedEnabled.(bool) {
+		err = fetchParallel(dataSourceFetchLimit(meta), detailIds, func(i int) error {
+			mapping, err := dataSourceAliCloudApigGatewayReadDescription(d, detailIds[i], s[i], meta)
+			if err != nil {
+				return err
+			}
+			s[i] = mapping
+			ids[i] = fmt.Sprint(mapping["id"])
+			return nil
+		})
 		if err != nil {
 			return WrapError(err)
 		}
-
-		ids = append(ids, fmt.Sprint(mapping["id"]))
-		names = append(names, objectRaw["name"])
-		s = append(s, mapping)
 	}
 
 	d.SetId(dataResourceIdHash(ids))
@@ -420,6 +420,7 @@ func dataSourceAliCloudCloudMonitorServiceMetricAlarmRuleRead(d *schema.Resource
 
 	ids := make([]string, 0)
 	s := make([]map[string]interface{}, 0)
+	detailIds := make([]string, 0)
 	for _, objectRaw := range objects {
 		mapping := map[string]interface{}{}
 
@@ -574,20 +575,24 @@ func dataSourceAliCloudCloudMonitorServiceMetricAlarmRuleRead(d *schema.Resource
 		}
 		mapping["prometheus"] = prometheusMaps
 
-		if detailedEnabled := d.Get("enable_details"); !detailedEnabled.(bool) {
-			ids = append(ids, fmt.Sprint(mapping["id"]))
-			s = append(s, mapping)
-			continue
-		}
+		ids = append(ids, fmt.Sprint(mapping["id"]))
+		s = append(s, mapping)
+		detailIds = append(detailIds, fmt.Sprint(objectRaw["RuleId"]))
+	}
 
-		id := fmt.Sprint(objectRaw["RuleId"])
-		mapping, err = dataSourceAliCloudCloudMonitorServiceMetricAlarmRuleReadDescription(d, id, mapping, meta)
+	if detailedEnabled := d.Get("enable_details"); detailedEnabled.(bool) {
+		err = fetchParallel(dataSourceFetchLimit(meta), detailIds, func(i int) error {
+			mapping, err := dataSourceAliCloudCloudMonitorServiceMetricAlarmRuleReadDescription(d, detailIds[i], s[i], meta)
+			if err != nil {
+				return err
+			}
+			s[i] = mapping
+			ids[i] = fmt.Sprint(mapping["id"])
+			return nil
+		})
 		if err != nil {
 			return WrapError(err)
 		}
-
-		ids = append(ids, fmt.Sprint(mapping["id"]))
-		s = append(s, mapping)
 	}
 
 	d.SetId(dataResourceIdHash(ids))
@@ -482,6 +482,7 @@ func dataSourceAliCloudCmsEventNotifyPolicyRead(d *schema.ResourceData, meta int
 
 	ids := make([]string, 0)
 	s := make([]map[string]interface{}, 0)
+	detailIds := make([]string, 0)
 	for _, objectRaw := range objects {
 		mapping := map[string]interface{}{}
 
@@ -627,20 +628,24 @@ func dataSourceAliCloudCmsEventNotifyPolicyRead(d *schema.ResourceData, meta int
 		}
 		mapping["notify_strategy"] = notifyStrategyMaps
 
-		if detailedEnabled := d.Get("enable_details"); !detailedEnabled.(bool) {
-			ids = append(ids, fmt.Sprint(mapping["id"]))
-			s = append(s, mapping)
-			continue
-		}
+		ids = append(ids, fmt.Sprint(mapping["id"]))
+		s = append(s, mapping)
+		detailIds = append(detailIds, fmt.Sprint(objectRaw["uuid"], ":", objectRaw["workspace"]))
+	}
 
-		id := fmt.Sprint(objectRaw["uuid"], ":", objectRaw["workspace"])
-		mapping, err = dataSourceAliCloudCmsEventNotifyPolicyReadDescription(d, id, mapping, meta)
+	if detailedEnabled := d.Get("enable_details"); detailedEnabled.(bool) {
+		err = fetchParallel(dataSourceFetchLimit(meta), detailIds, func(i int) error {
+			mapping, err := dataSourceAliCloudCmsEventNotifyPolicyReadDescription(d, detailIds[i], s[i], meta)
+			if err != nil {
+				return err
+			}
+			s[i] = mapping
+			ids[i] = fmt.Sprint(mapping["id"])
+			return nil
+		})
 		if err != nil {
 			return WrapError(err)
 		}
-
-		ids = append(ids, fmt.Sprint(mapping["id"]))
-		s = append(s, mapping)
 	}
 
 	d.SetId(dataResourceIdHash(ids))
@@ -190,6 +190,7 @@ func dataSourceAliCloudCrArtifactSubscriptionRuleRead(d *schema.ResourceData, me
 
 	ids := make([]string, 0)
 	s := make([]map[string]interface{}, 0)
+	detailIds := make([]string, 0)
 	for _, objectRaw := range objects {
 		mapping := map[string]interface{}{}
 
@@ -211,20 +212,24 @@ func dataSourceAliCloudCrArtifactSubscriptionRuleRead(d *schema.ResourceData, me
 		mapping["artifact_subscription_rule_id"] = objectRaw["RuleId"]
 		mapping["instance_id"] = objectRaw["InstanceId"]
 
-		if detailedEnabled := d.Get("enable_details"); !detailedEnabled.(bool) {
-			ids = append(ids, fmt.Sprint(mapping["id"]))
-			s = append(s, mapping)
-			continue
-		}
+		ids = append(ids, fmt.Sprint(mapping["id"]))
+		s = append(s, mapping)
+		detailIds = append(detailIds, fmt.Sprint(objectRaw["InstanceId"], ":", objectRaw["RuleId"]))
+	}
 
-		id := fmt.Sprint(objectRaw["InstanceId"], ":", objectRaw["RuleId"])
-		mapping, err = dataSourceAliCloudCrArtifactSubscriptionRuleReadDescription(d, id, mapping, meta)
+	if detailedEnabled := d.Get("enable_details"); detailedEnabled.(bool) {
+		err = fetchParallel(dataSourceFetchLimit(meta), detailIds, func(i int) error {
+			mapping, err := dataSourceAliCloudCrArtifactSubscriptionRuleReadDescription(d, detailIds[i], s[i], meta)
+			if err != nil {
+				return err
+			}
+			s[i] = mapping
+			ids[i] = fmt.Sprint(mapping["id"])
+			return nil
+		})
 		if err != nil {
 			return WrapError(err)
 		}
-
-		ids = append(ids, fmt.Sprint(mapping["id"]))
-		s = append(s, mapping)
 	}
 
 	d.SetId(dataResourceIdHash(ids))
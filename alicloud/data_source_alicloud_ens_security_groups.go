@@ -226,6 +226,7 @@ func dataSourceAliCloudEnsSecurityGroupRead(d *schema.ResourceData, meta interfa
 	ids := make([]string, 0)
 	names := make([]interface{}, 0)
 	s := make([]map[string]interface{}, 0)
+	detailIds := make([]string, 0)
 	for _, objectRaw := range objects {
 		mapping := map[string]interface{}{}
 
@@ -237,22 +238,25 @@ func dataSourceAliCloudEnsSecurityGroupRead(d *schema.ResourceData, meta interfa
 		mapping["instance_count"] = objectRaw["InstanceCount"]
 		mapping["security_group_id"] = objectRaw["SecurityGroupId"]
 
-		if detailedEnabled := d.Get("enable_details"); !detailedEnabled.(bool) {
-			ids = append(ids, fmt.Sprint(mapping["id"]))
-			names = append(names, objectRaw["SecurityGroupName"])
-			s = append(s, mapping)
-			continue
-		}
+		ids = append(ids, fmt.Sprint(mapping["id"]))
+		names = append(names, objectRaw["SecurityGroupName"])
+		s = append(s, mapping)
+		detailIds = append(detailIds, fmt.Sprint(objectRaw["SecurityGroupId"]))
+	}
 
-		id := fmt.Sprint(objectRaw["SecurityGroupId"])
-		mapping, err = dataSourceAliCloudEnsSecurityGroupReadDescription(d, id, mapping, meta)
+	if detailedEnabled := d.Get("enable_details"); detailedEnabled.(bool) {
+		err = fetchParallel(dataSourceFetchLimit(meta), detailIds, func(i int) error {
+			mapping, err := dataSourceAliCloudEnsSecurityGroupReadDescription(d, detailIds[i], s[i], meta)
+			if err != nil {
+				return err
+			}
+			s[i] = mapping
+			ids[i] = fmt.Sprint(mapping["id"])
+			return nil
+		})
 		if err != nil {
 			return WrapError(err)
 		}
-
-		ids = append(ids, fmt.Sprint(mapping["id"]))
-		names = append(names, objectRaw["SecurityGroupName"])
-		s = append(s, mapping)
 	}
 
 	d.SetId(dataResourceIdHash(ids))
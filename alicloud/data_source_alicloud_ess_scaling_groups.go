@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 
 	"github.com/PaesslerAG/jsonpath"
@@ -227,6 +228,31 @@ func dataSourceAliCloudEssScalingGroups() *schema.Resource {
 							Type:     schema.TypeInt,
 							Computed: true,
 						},
+						"warm_pool": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"size": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"instance_state": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"reuse_on_scale_in": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									"instance_ids": {
+										Type:     schema.TypeList,
+										Computed: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
 						"total_instance_count": {
 							Type:     schema.TypeInt,
 							Computed: true,
@@ -530,6 +556,26 @@ func scalingGroupsDescriptionAttribute(d *schema.ResourceData, scalingGroups []i
 			mapping["suspended_processes"] = suspendedProcesses
 		}
 
+		warmPool := map[string]interface{}{
+			"size":              formatInt(object["StandbyCapacity"]),
+			"reuse_on_scale_in": object["ScalingPolicy"] == "recycle",
+			"instance_ids":      []string{},
+		}
+		if formatInt(object["StandbyCapacity"]) > 0 {
+			pool, err := essService.DescribeEssWarmPoolInstances(fmt.Sprint(object["ScalingGroupId"]))
+			if err != nil {
+				return WrapError(err)
+			}
+			poolIds := make([]string, 0, len(pool))
+			for id := range pool {
+				poolIds = append(poolIds, id)
+			}
+			sort.Strings(poolIds)
+			warmPool["instance_ids"] = poolIds
+			warmPool["instance_state"] = essWarmPoolInstanceState(pool)
+		}
+		mapping["warm_pool"] = []map[string]interface{}{warmPool}
+
 		listTagResourcesObject, err := essService.ListTagResources(d.Id(), client)
 		if err != nil {
 			return WrapError(err)
@@ -2,7 +2,6 @@ package alicloud
 
 import (
 	"regexp"
-	"strconv"
 	"time"
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
@@ -241,54 +240,50 @@ func dataSourceAlicloudInstances() *schema.Resource {
 func dataSourceAlicloudInstancesRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*connectivity.AliyunClient)
 
-	request := ecs.CreateDescribeInstancesRequest()
-	request.RegionId = client.RegionId
-	request.Status = d.Get("status").(string)
-
+	status := d.Get("status").(string)
+	filterInstanceIds := ""
 	if v, ok := d.GetOk("ids"); ok && len(v.([]interface{})) > 0 {
-		request.InstanceIds = convertListToJsonString(v.([]interface{}))
-	}
-	if v, ok := d.GetOk("vpc_id"); ok && v.(string) != "" {
-		request.VpcId = v.(string)
-	}
-	if v, ok := d.GetOk("vswitch_id"); ok && v.(string) != "" {
-		request.VSwitchId = v.(string)
-	}
-	if v, ok := d.GetOk("resource_group_id"); ok && v.(string) != "" {
-		request.ResourceGroupId = v.(string)
-	}
-	if v, ok := d.GetOk("availability_zone"); ok && v.(string) != "" {
-		request.ZoneId = v.(string)
-	}
-	if v, ok := d.GetOk("instance_name"); ok && v.(string) != "" {
-		request.InstanceName = v.(string)
+		filterInstanceIds = convertListToJsonString(v.([]interface{}))
 	}
+	vpcId := d.Get("vpc_id").(string)
+	vswitchId := d.Get("vswitch_id").(string)
+	resourceGroupId := d.Get("resource_group_id").(string)
+	zoneId := d.Get("availability_zone").(string)
+	instanceName := d.Get("instance_name").(string)
+	var tags []ecs.DescribeInstancesTag
 	if v, ok := d.GetOk("tags"); ok {
-		var tags []ecs.DescribeInstancesTag
-
 		for key, value := range v.(map[string]interface{}) {
 			tags = append(tags, ecs.DescribeInstancesTag{
 				Key:   key,
 				Value: value.(string),
 			})
 		}
-		request.Tag = &tags
 	}
 
-	var allInstances []ecs.Instance
-
-	if v, ok := d.GetOk("page_number"); ok && v.(int) > 0 {
-		request.PageNumber = requests.NewInteger(v.(int))
-	} else {
-		request.PageNumber = requests.NewInteger(1)
-	}
+	pageSize := PageSizeXLarge
 	if v, ok := d.GetOk("page_size"); ok && v.(int) > 0 {
-		request.PageSize = requests.NewInteger(v.(int))
-	} else {
-		request.PageSize = requests.NewInteger(PageSizeXLarge)
+		pageSize = v.(int)
 	}
-	var response *ecs.DescribeInstancesResponse
-	for {
+
+	// The pages are fetched concurrently, and a request of the SDK cannot be sent twice at the same time, so
+	// every page builds its own.
+	var totalCount int
+	describeInstances := func(pageNumber int) ([]interface{}, int, error) {
+		request := ecs.CreateDescribeInstancesRequest()
+		request.RegionId = client.RegionId
+		request.Status = status
+		request.InstanceIds = filterInstanceIds
+		request.VpcId = vpcId
+		request.VSwitchId = vswitchId
+		request.ResourceGroupId = resourceGroupId
+		request.ZoneId = zoneId
+		request.InstanceName = instanceName
+		if len(tags) > 0 {
+			request.Tag = &tags
+		}
+		request.PageNumber = requests.NewInteger(pageNumber)
+		request.PageSize = requests.NewInteger(pageSize)
+
 		var raw interface{}
 		var err error
 		wait := incrementalWait(3*time.Second, 3*time.Second)
@@ -308,33 +303,34 @@ func dataSourceAlicloudInstancesRead(d *schema.ResourceData, meta interface{}) e
 		addDebug(request.GetActionName(), raw, request.RpcRequest, request)
 
 		if err != nil {
-			return WrapErrorf(err, DataDefaultErrorMsg, "alicloud_instances", request.GetActionName(), AlibabaCloudSdkGoERROR)
-		}
-
-		response, _ = raw.(*ecs.DescribeInstancesResponse)
-		if isPagingRequest(d) {
-			allInstances = response.Instances.Instance
-			break
-		}
-		if len(response.Instances.Instance) < 1 {
-			break
+			return nil, 0, WrapErrorf(err, DataDefaultErrorMsg, "alicloud_instances", request.GetActionName(), AlibabaCloudSdkGoERROR)
 		}
 
-		allInstances = append(allInstances, response.Instances.Instance...)
-		pageSize, err := strconv.Atoi(string(request.PageSize))
-		if err != nil {
-			return WrapError(err)
+		response, _ := raw.(*ecs.DescribeInstancesResponse)
+		if pageNumber == 1 {
+			// fetchPages reads the first page before the others, so only one call writes the total.
+			totalCount = response.TotalCount
 		}
-
-		if len(response.Instances.Instance) < pageSize {
-			break
+		instances := make([]interface{}, 0, len(response.Instances.Instance))
+		for _, instance := range response.Instances.Instance {
+			instances = append(instances, instance)
 		}
+		return instances, response.TotalCount, nil
+	}
 
-		if page, err := getNextpageNumber(request.PageNumber); err != nil {
-			return WrapError(err)
-		} else {
-			request.PageNumber = page
-		}
+	var objects []interface{}
+	var err error
+	if isPagingRequest(d) {
+		objects, totalCount, err = describeInstances(d.Get("page_number").(int))
+	} else {
+		objects, err = fetchPages(dataSourceFetchLimit(meta), pageSize, describeInstances)
+	}
+	if err != nil {
+		return WrapError(err)
+	}
+	allInstances := make([]ecs.Instance, 0, len(objects))
+	for _, object := range objects {
+		allInstances = append(allInstances, object.(ecs.Instance))
 	}
 
 	var filteredInstancesTemp []ecs.Instance
@@ -363,7 +359,7 @@ func dataSourceAlicloudInstancesRead(d *schema.ResourceData, meta interface{}) e
 		filteredInstancesTemp = allInstances
 	}
 	if v, ok := d.GetOkExists("enable_details"); !ok || !v.(bool) {
-		return instancessDescriptionAttributes(d, filteredInstancesTemp, nil, nil, meta, response.TotalCount)
+		return instancessDescriptionAttributes(d, filteredInstancesTemp, nil, nil, meta, totalCount)
 	}
 	// Filter by ram role name and fetch the instance role name
 	instanceIds := make([]string, 0)
@@ -373,12 +369,18 @@ func dataSourceAlicloudInstancesRead(d *schema.ResourceData, meta interface{}) e
 		}
 		instanceIds = append(instanceIds, inst.InstanceId)
 	}
-	instanceRoleNameMap := make(map[string]string)
+	// DescribeInstanceRamRole parameter InstanceIds supports at most 100 items once, the batches are fetched
+	// concurrently.
+	ramRoleName := d.Get("ram_role_name").(string)
+	batches := make([]string, 0)
 	for index := 0; index < len(instanceIds); index += 100 {
-		// DescribeInstanceRamRole parameter InstanceIds supports at most 100 items once
+		batches = append(batches, convertListToJsonString(convertListStringToListInterface(instanceIds[index:IntMin(index+100, len(instanceIds))])))
+	}
+	batchRoles := make([][]ecs.InstanceRamRoleSet, len(batches))
+	err = fetchParallel(dataSourceFetchLimit(meta), batches, func(i int) error {
 		request := ecs.CreateDescribeInstanceRamRoleRequest()
-		request.InstanceIds = convertListToJsonString(convertListStringToListInterface(instanceIds[index:IntMin(index+100, len(instanceIds))]))
-		request.RamRoleName = d.Get("ram_role_name").(string)
+		request.InstanceIds = batches[i]
+		request.RamRoleName = ramRoleName
 		request.PageSize = requests.NewInteger(PageSizeLarge)
 		request.PageNumber = requests.NewInteger(1)
 		for {
@@ -408,9 +410,7 @@ func dataSourceAlicloudInstancesRead(d *schema.ResourceData, meta interface{}) e
 			if len(response.InstanceRamRoleSets.InstanceRamRoleSet) < 1 {
 				break
 			}
-			for _, role := range response.InstanceRamRoleSets.InstanceRamRoleSet {
-				instanceRoleNameMap[role.InstanceId] = role.RamRoleName
-			}
+			batchRoles[i] = append(batchRoles[i], response.InstanceRamRoleSets.InstanceRamRoleSet...)
 
 			if len(response.InstanceRamRoleSets.InstanceRamRoleSet) < PageSizeLarge {
 				break
@@ -422,13 +422,23 @@ func dataSourceAlicloudInstancesRead(d *schema.ResourceData, meta interface{}) e
 				request.PageNumber = page
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return WrapError(err)
+	}
+	instanceRoleNameMap := make(map[string]string)
+	for _, roles := range batchRoles {
+		for _, role := range roles {
+			instanceRoleNameMap[role.InstanceId] = role.RamRoleName
+		}
 	}
 	instanceDiskMappings, err := getInstanceDisksMappings(instanceRoleNameMap, meta)
 	if err != nil {
 		return WrapError(err)
 	}
 
-	return instancessDescriptionAttributes(d, filteredInstancesTemp, instanceRoleNameMap, instanceDiskMappings, meta, response.TotalCount)
+	return instancessDescriptionAttributes(d, filteredInstancesTemp, instanceRoleNameMap, instanceDiskMappings, meta, totalCount)
 }
 
 // populate the numerous fields that the instance description returns.
@@ -509,12 +519,12 @@ func instancessDescriptionAttributes(d *schema.ResourceData, instances []ecs.Ins
 // Returns a mapping of instance disks
 func getInstanceDisksMappings(instanceMap map[string]string, meta interface{}) (map[string][]map[string]interface{}, error) {
 	client := meta.(*connectivity.AliyunClient)
-	request := ecs.CreateDescribeDisksRequest()
-	request.PageSize = requests.NewInteger(PageSizeXLarge)
-	request.PageNumber = requests.NewInteger(1)
 	instanceDisks := make(map[string][]map[string]interface{})
-	var allDisks []ecs.Disk
-	for {
+	objects, err := fetchPages(dataSourceFetchLimit(meta), PageSizeXLarge, func(pageNumber int) ([]interface{}, int, error) {
+		request := ecs.CreateDescribeDisksRequest()
+		request.PageSize = requests.NewInteger(PageSizeXLarge)
+		request.PageNumber = requests.NewInteger(pageNumber)
+
 		var raw interface{}
 		var err error
 		wait := incrementalWait(3*time.Second, 3*time.Second)
@@ -534,26 +544,25 @@ func getInstanceDisksMappings(instanceMap map[string]string, meta interface{}) (
 		addDebug(request.GetActionName(), raw, request.RpcRequest, request)
 
 		if err != nil {
-			return instanceDisks, WrapErrorf(err, DataDefaultErrorMsg, "alicloud_instances", request.GetActionName(), AlibabaCloudSdkGoERROR)
+			return nil, 0, WrapErrorf(err, DataDefaultErrorMsg, "alicloud_instances", request.GetActionName(), AlibabaCloudSdkGoERROR)
 		}
 
 		response, _ := raw.(*ecs.DescribeDisksResponse)
-
-		if response == nil || len(response.Disks.Disk) < 1 {
-			break
+		if response == nil {
+			return nil, 0, nil
 		}
-
-		allDisks = append(allDisks, response.Disks.Disk...)
-
-		if len(response.Disks.Disk) < PageSizeXLarge {
-			break
+		disks := make([]interface{}, 0, len(response.Disks.Disk))
+		for _, disk := range response.Disks.Disk {
+			disks = append(disks, disk)
 		}
-
-		page, err := getNextpageNumber(request.PageNumber)
-		if err != nil {
-			return instanceDisks, WrapError(err)
-		}
-		request.PageNumber = page
+		return disks, response.TotalCount, nil
+	})
+	if err != nil {
+		return instanceDisks, WrapError(err)
+	}
+	allDisks := make([]ecs.Disk, 0, len(objects))
+	for _, object := range objects {
+		allDisks = append(allDisks, object.(ecs.Disk))
 	}
 	for _, disk := range allDisks {
 		if _, ok := instanceMap[disk.InstanceId]; !ok {
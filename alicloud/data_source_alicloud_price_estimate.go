@@ -0,0 +1,417 @@
+package alicloud
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// priceEstimateHoursPerMonth converts hourly pay-as-you-go prices into monthly ones.
+const priceEstimateHoursPerMonth = 730
+
+func dataSourceAliCloudPriceEstimate() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAliCloudPriceEstimateRead,
+		Schema: map[string]*schema.Schema{
+			"items": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"product": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: StringInSlice([]string{"ecs", "disk", "rds", "slb", "alb", "nlb"}, false),
+						},
+						"instance_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"engine": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"engine_version": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"disk_category": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"disk_size": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: IntAtLeast(0),
+						},
+						"bandwidth": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: IntAtLeast(0),
+						},
+						"charge_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      string(PostPaid),
+							ValidateFunc: StringInSlice([]string{string(PostPaid), string(PrePaid)}, false),
+						},
+						"period": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: IntAtLeast(1),
+						},
+						"period_unit": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "Month",
+							ValidateFunc: StringInSlice([]string{"Month", "Year"}, false),
+						},
+						"quantity": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: IntAtLeast(1),
+						},
+						"product_code": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"product_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"modules": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"module_code": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"config": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"prices": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"product": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"list_price": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"discount": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"trade_price": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"currency": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"total_list_price": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"total_discount": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"total_trade_price": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"currency": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"output_file": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func dataSourceAliCloudPriceEstimateRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	bssOpenApiService := BssOpenApiService{client}
+
+	ids := make([]string, 0)
+	prices := make([]map[string]interface{}, 0)
+	var totalListPrice, totalDiscount, totalTradePrice float64
+	currency := ""
+	for i, raw := range d.Get("items").([]interface{}) {
+		item, _ := raw.(map[string]interface{})
+		if item == nil {
+			continue
+		}
+		productCode, productType, modules, err := priceEstimateModules(item, client.RegionId)
+		if err != nil {
+			return WrapError(fmt.Errorf("items.%d: %v", i, err))
+		}
+		subscriptionType := "PayAsYouGo"
+		if item["charge_type"].(string) == string(PrePaid) {
+			subscriptionType = "Subscription"
+		}
+		if len(modules) == 0 {
+			pricingModules, err := bssOpenApiService.DescribePricingModules(productCode, productType, subscriptionType)
+			if err != nil {
+				return WrapError(err)
+			}
+			modules = priceEstimateFixedFeeModules(pricingModules, client.RegionId)
+			if len(modules) == 0 {
+				return WrapError(fmt.Errorf("items.%d: product %s has no hourly pricing module priced by region alone, set modules to quote it", i, productCode))
+			}
+		}
+		data, err := bssOpenApiService.GetPrice(bssOpenApiPriceOptions{
+			ProductCode:      productCode,
+			ProductType:      productType,
+			SubscriptionType: subscriptionType,
+			Period:           item["period"].(int),
+			PeriodUnit:       item["period_unit"].(string),
+			Quantity:         item["quantity"].(int),
+			Modules:          modules,
+		})
+		if err != nil {
+			return WrapError(err)
+		}
+
+		factor := priceEstimateMonthlyFactor(item["charge_type"].(string), item["period"].(int), item["period_unit"].(string), item["quantity"].(int))
+		listPrice, discount, tradePrice := priceEstimateModuleCosts(data)
+		listPrice, discount, tradePrice = priceEstimateRound(listPrice*factor), priceEstimateRound(discount*factor), priceEstimateRound(tradePrice*factor)
+		itemCurrency := ""
+		if len(data) > 0 {
+			itemCurrency = fmt.Sprint(data[0]["Currency"])
+		}
+		if currency == "" {
+			currency = itemCurrency
+		} else if currency != itemCurrency {
+			return WrapError(fmt.Errorf("items.%d is priced in %s while the other items are priced in %s", i, itemCurrency, currency))
+		}
+
+		totalListPrice += listPrice
+		totalDiscount += discount
+		totalTradePrice += tradePrice
+		ids = append(ids, fmt.Sprintf("%s:%v", item["product"], modules))
+		prices = append(prices, map[string]interface{}{
+			"name":        item["name"],
+			"product":     item["product"],
+			"list_price":  listPrice,
+			"discount":    discount,
+			"trade_price": tradePrice,
+			"currency":    itemCurrency,
+		})
+	}
+
+	d.SetId(dataResourceIdHash(ids))
+	if err := d.Set("prices", prices); err != nil {
+		return WrapError(err)
+	}
+	d.Set("total_list_price", priceEstimateRound(totalListPrice))
+	d.Set("total_discount", priceEstimateRound(totalDiscount))
+	d.Set("total_trade_price", priceEstimateRound(totalTradePrice))
+	d.Set("currency", currency)
+	if output, ok := d.GetOk("output_file"); ok && output.(string) != "" {
+		writeToFile(output.(string), prices)
+	}
+	return nil
+}
+
+// priceEstimateModules returns the product and the pricing modules of an item. Modules given explicitly replace the
+// ones derived from the item's arguments, for products and options the derivation does not cover, and product_code
+// and product_type override the product the item is quoted for.
+func priceEstimateModules(item map[string]interface{}, regionId string) (productCode, productType string, modules []map[string]interface{}, err error) {
+	product := item["product"].(string)
+	postPaid := item["charge_type"].(string) != string(PrePaid)
+	instanceType, _ := item["instance_type"].(string)
+	diskCategory, _ := item["disk_category"].(string)
+	diskSize, _ := item["disk_size"].(int)
+	bandwidth, _ := item["bandwidth"].(int)
+
+	add := func(moduleCode, config string) {
+		module := map[string]interface{}{
+			"ModuleCode": moduleCode,
+			"Config":     config,
+		}
+		if postPaid {
+			module["PriceType"] = "Hour"
+		}
+		modules = append(modules, module)
+	}
+	requireInstanceType := func() error {
+		if instanceType == "" {
+			return fmt.Errorf("instance_type is required for product %s", product)
+		}
+		return nil
+	}
+
+	if custom, ok := item["modules"].([]interface{}); ok && len(custom) > 0 {
+		for _, raw := range custom {
+			if module, ok := raw.(map[string]interface{}); ok {
+				add(module["module_code"].(string), module["config"].(string))
+			}
+		}
+		productCode = product
+		if product == "disk" {
+			productCode = "ecs"
+		}
+		product = ""
+	}
+
+	switch product {
+	case "ecs":
+		if err := requireInstanceType(); err != nil {
+			return "", "", nil, err
+		}
+		productCode = "ecs"
+		add("InstanceType", fmt.Sprintf("InstanceType:%s,IoOptimized:IoOptimized,ImageOs:linux,Region:%s", instanceType, regionId))
+		if diskCategory != "" && diskSize > 0 {
+			add("SystemDisk", fmt.Sprintf("SystemDisk.Category:%s,SystemDisk.Size:%d,Region:%s", diskCategory, diskSize, regionId))
+		}
+		if bandwidth > 0 {
+			add("InternetMaxBandwidthOut", fmt.Sprintf("InternetMaxBandwidthOut:%d,Region:%s", bandwidth*1024, regionId))
+		}
+	case "disk":
+		if diskCategory == "" || diskSize <= 0 {
+			return "", "", nil, fmt.Errorf("disk_category and disk_size are required for product disk")
+		}
+		productCode = "ecs"
+		add("DataDisk", fmt.Sprintf("DataDisk.Category:%s,DataDisk.Size:%d,Region:%s", diskCategory, diskSize, regionId))
+	case "rds":
+		if err := requireInstanceType(); err != nil {
+			return "", "", nil, err
+		}
+		engine, _ := item["engine"].(string)
+		engineVersion, _ := item["engine_version"].(string)
+		if engine == "" || engineVersion == "" {
+			return "", "", nil, fmt.Errorf("engine and engine_version are required for product rds")
+		}
+		productCode, productType = "rds", "rds"
+		if postPaid {
+			productType = "bards"
+		}
+		add("DBInstanceClass", fmt.Sprintf("DBInstanceClass:%s,Engine:%s,EngineVersion:%s,Region:%s", instanceType, engine, engineVersion, regionId))
+		if diskSize > 0 {
+			add("DBInstanceStorage", fmt.Sprintf("DBInstanceStorage:%d,DBInstanceStorageType:%s,Region:%s", diskSize, diskCategory, regionId))
+		}
+	case "slb":
+		productCode = "slb"
+		if instanceType != "" {
+			add("LoadBalancerSpec", fmt.Sprintf("LoadBalancerSpec:%s,Region:%s", instanceType, regionId))
+		}
+		if bandwidth > 0 {
+			add("Bandwidth", fmt.Sprintf("Bandwidth:%d,Region:%s", bandwidth*1024, regionId))
+		}
+		if len(modules) == 0 {
+			return "", "", nil, fmt.Errorf("instance_type or bandwidth is required for product slb")
+		}
+	case "alb", "nlb":
+		// their modules are looked up with DescribePricingModule, see priceEstimateFixedFeeModules
+		if !postPaid {
+			return "", "", nil, fmt.Errorf("product %s only supports the charge type %s", product, PostPaid)
+		}
+		productCode = product
+	}
+
+	if v, ok := item["product_code"].(string); ok && v != "" {
+		productCode = v
+	}
+	if v, ok := item["product_type"].(string); ok && v != "" {
+		productType = v
+	}
+	return productCode, productType, modules, nil
+}
+
+// priceEstimateFixedFeeModules returns the hourly modules among the pricing modules of a product, as returned by
+// DescribePricingModule, that are priced by region alone, such as the instance fee of a load balancer. Fees that
+// depend on usage are priced by more than the region and left out.
+func priceEstimateFixedFeeModules(pricingModules []interface{}, regionId string) []map[string]interface{} {
+	modules := make([]map[string]interface{}, 0)
+	for _, raw := range pricingModules {
+		module, ok := raw.(map[string]interface{})
+		if !ok || fmt.Sprint(module["PriceType"]) != "Hour" {
+			continue
+		}
+		configs, _ := jsonpath.Get("$.ConfigList.ConfigList", module)
+		configList, _ := configs.([]interface{})
+		if len(configList) != 1 || fmt.Sprint(configList[0]) != "Region" {
+			continue
+		}
+		modules = append(modules, map[string]interface{}{
+			"ModuleCode": module["ModuleCode"],
+			"Config":     fmt.Sprintf("Region:%s", regionId),
+			"PriceType":  "Hour",
+		})
+	}
+	return modules
+}
+
+// priceEstimateModuleCosts adds up the list price, the discount and the price after discount of every module quoted.
+func priceEstimateModuleCosts(data []map[string]interface{}) (listPrice, discount, tradePrice float64) {
+	for _, item := range data {
+		details, _ := item["ModuleDetails"].(map[string]interface{})
+		modules, _ := details["ModuleDetail"].([]interface{})
+		for _, raw := range modules {
+			module, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			listPrice += formatFloat64(module["OriginalCost"])
+			discount += formatFloat64(module["InvoiceDiscount"])
+			tradePrice += formatFloat64(module["CostAfterDiscount"])
+		}
+	}
+	return listPrice, discount, tradePrice
+}
+
+// priceEstimateMonthlyFactor turns a quoted price into a monthly price. Pay-as-you-go prices are quoted per hour and
+// instance, subscription prices for the whole period and every instance.
+func priceEstimateMonthlyFactor(chargeType string, period int, periodUnit string, quantity int) float64 {
+	if chargeType != string(PrePaid) {
+		return priceEstimateHoursPerMonth * float64(quantity)
+	}
+	months := period
+	if periodUnit == "Year" {
+		months = period * 12
+	}
+	if months <= 0 {
+		return 1
+	}
+	return 1 / float64(months)
+}
+
+func priceEstimateRound(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,129 @@
+package alicloud
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccAliCloudPriceEstimateDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAliCloudPriceEstimateDataSource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.alicloud_price_estimate.default", "prices.#", "2"),
+					resource.TestCheckResourceAttr("data.alicloud_price_estimate.default", "prices.0.name", "web"),
+					resource.TestCheckResourceAttrSet("data.alicloud_price_estimate.default", "prices.0.trade_price"),
+					resource.TestCheckResourceAttrSet("data.alicloud_price_estimate.default", "prices.1.list_price"),
+					resource.TestCheckResourceAttrSet("data.alicloud_price_estimate.default", "total_trade_price"),
+					resource.TestCheckResourceAttrSet("data.alicloud_price_estimate.default", "currency"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCheckAliCloudPriceEstimateDataSource = `
+data "alicloud_price_estimate" "default" {
+  items {
+    name          = "web"
+    product       = "ecs"
+    instance_type = "ecs.g7.large"
+    disk_category = "cloud_essd"
+    disk_size     = 40
+    bandwidth     = 5
+    quantity      = 2
+  }
+  items {
+    name          = "data"
+    product       = "disk"
+    disk_category = "cloud_essd"
+    disk_size     = 500
+    charge_type   = "PrePaid"
+    period        = 1
+    period_unit   = "Year"
+  }
+}
+`
+
+func TestUnitAliCloudPriceEstimate(t *testing.T) {
+	item := map[string]interface{}{
+		"product": "ecs", "instance_type": "ecs.g7.large", "disk_category": "cloud_essd", "disk_size": 40, "bandwidth": 5,
+		"charge_type": "PostPaid", "modules": []interface{}{},
+	}
+	productCode, productType, modules, err := priceEstimateModules(item, "cn-hangzhou")
+	assert.Nil(t, err)
+	assert.Equal(t, "ecs", productCode)
+	assert.Equal(t, "", productType)
+	assert.Equal(t, []map[string]interface{}{
+		{"ModuleCode": "InstanceType", "Config": "InstanceType:ecs.g7.large,IoOptimized:IoOptimized,ImageOs:linux,Region:cn-hangzhou", "PriceType": "Hour"},
+		{"ModuleCode": "SystemDisk", "Config": "SystemDisk.Category:cloud_essd,SystemDisk.Size:40,Region:cn-hangzhou", "PriceType": "Hour"},
+		{"ModuleCode": "InternetMaxBandwidthOut", "Config": "InternetMaxBandwidthOut:5120,Region:cn-hangzhou", "PriceType": "Hour"},
+	}, modules)
+
+	item = map[string]interface{}{
+		"product": "rds", "instance_type": "mysql.n2.medium.1", "engine": "MySQL", "engine_version": "8.0",
+		"disk_category": "cloud_essd", "disk_size": 100, "charge_type": "PrePaid",
+	}
+	productCode, productType, modules, err = priceEstimateModules(item, "cn-hangzhou")
+	assert.Nil(t, err)
+	assert.Equal(t, "rds", productCode)
+	assert.Equal(t, "rds", productType)
+	assert.Len(t, modules, 2)
+	assert.NotContains(t, modules[0], "PriceType")
+
+	item["engine"] = ""
+	_, _, _, err = priceEstimateModules(item, "cn-hangzhou")
+	assert.EqualError(t, err, "engine and engine_version are required for product rds")
+
+	item = map[string]interface{}{
+		"product": "slb", "charge_type": "PostPaid", "product_code": "nlb", "product_type": "nlb_post",
+		"modules": []interface{}{map[string]interface{}{"module_code": "Lcu", "config": "Lcu:1"}},
+	}
+	productCode, productType, modules, err = priceEstimateModules(item, "cn-hangzhou")
+	assert.Nil(t, err)
+	assert.Equal(t, "nlb", productCode)
+	assert.Equal(t, "nlb_post", productType)
+	assert.Equal(t, []map[string]interface{}{{"ModuleCode": "Lcu", "Config": "Lcu:1", "PriceType": "Hour"}}, modules)
+
+	// the modules of an alb or nlb item are the hourly ones DescribePricingModule prices by region alone
+	item = map[string]interface{}{"product": "alb", "charge_type": "PostPaid"}
+	productCode, _, modules, err = priceEstimateModules(item, "cn-hangzhou")
+	assert.Nil(t, err)
+	assert.Equal(t, "alb", productCode)
+	assert.Empty(t, modules)
+	item["charge_type"] = "PrePaid"
+	_, _, _, err = priceEstimateModules(item, "cn-hangzhou")
+	assert.EqualError(t, err, "product alb only supports the charge type PostPaid")
+
+	var pricingModules map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"ModuleList":{"Module":[
+		{"ModuleCode":"InstanceRent","PriceType":"Hour","Currency":"CNY","ConfigList":{"ConfigList":["Region"]}},
+		{"ModuleCode":"Lcu","PriceType":"Usage","Currency":"CNY","ConfigList":{"ConfigList":["Region"]}},
+		{"ModuleCode":"Edition","PriceType":"Hour","Currency":"CNY","ConfigList":{"ConfigList":["Edition","Region"]}}]}}`), &pricingModules))
+	assert.Equal(t, []map[string]interface{}{
+		{"ModuleCode": "InstanceRent", "Config": "Region:cn-hangzhou", "PriceType": "Hour"},
+	}, priceEstimateFixedFeeModules(pricingModules["ModuleList"].(map[string]interface{})["Module"].([]interface{}), "cn-hangzhou"))
+
+	var data []map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(`[{"Currency":"CNY","ModuleDetails":{"ModuleDetail":[
+		{"OriginalCost":0.5,"InvoiceDiscount":0.1,"CostAfterDiscount":0.4},
+		{"OriginalCost":"0.25","InvoiceDiscount":"0","CostAfterDiscount":"0.25"}]}},
+		{"Currency":"CNY","ModuleDetails":{"ModuleDetail":[{"OriginalCost":1,"InvoiceDiscount":0,"CostAfterDiscount":1}]}}]`), &data))
+	listPrice, discount, tradePrice := priceEstimateModuleCosts(data)
+	assert.InDelta(t, 1.75, listPrice, 1e-9)
+	assert.InDelta(t, 0.1, discount, 1e-9)
+	assert.InDelta(t, 1.65, tradePrice, 1e-9)
+	assert.Equal(t, []float64{0.5, 0.25, 1}, bssOpenApiOriginalCosts(data))
+
+	assert.Equal(t, float64(1460), priceEstimateMonthlyFactor("PostPaid", 1, "Month", 2))
+	assert.InDelta(t, 1.0/12, priceEstimateMonthlyFactor("PrePaid", 1, "Year", 3), 1e-9)
+	assert.Equal(t, 0.33, priceEstimateRound(1.0/3))
+}
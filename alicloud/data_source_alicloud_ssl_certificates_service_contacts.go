@@ -141,6 +141,7 @@ func dataSourceAliCloudSslCertificatesServiceContactRead(d *schema.ResourceData,
 
 	ids := make([]string, 0)
 	s := make([]map[string]interface{}, 0)
+	detailIds := make([]string, 0)
 	for _, objectRaw := range objects {
 		mapping := map[string]interface{}{}
 
@@ -149,20 +150,24 @@ func dataSourceAliCloudSslCertificatesServiceContactRead(d *schema.ResourceData,
 		mapping["name"] = objectRaw["Name"]
 		mapping["contact_id"] = objectRaw["ContactId"]
 
-		if detailedEnabled := d.Get("enable_details"); !detailedEnabled.(bool) {
-			ids = append(ids, fmt.Sprint(mapping["id"]))
-			s = append(s, mapping)
-			continue
-		}
+		ids = append(ids, fmt.Sprint(mapping["id"]))
+		s = append(s, mapping)
+		detailIds = append(detailIds, fmt.Sprint(objectRaw["ContactId"]))
+	}
 
-		id := fmt.Sprint(objectRaw["ContactId"])
-		mapping, err = dataSourceAliCloudSslCertificatesServiceContactReadDescription(d, id, mapping, meta)
+	if detailedEnabled := d.Get("enable_details"); detailedEnabled.(bool) {
+		err = fetchParallel(dataSourceFetchLimit(meta), detailIds, func(i int) error {
+			mapping, err := dataSourceAliCloudSslCertificatesServiceContactReadDescription(d, detailIds[i], s[i], meta)
+			if err != nil {
+				return err
+			}
+			s[i] = mapping
+			ids[i] = fmt.Sprint(mapping["id"])
+			return nil
+		})
 		if err != nil {
 			return WrapError(err)
 		}
-
-		ids = append(ids, fmt.Sprint(mapping["id"]))
-		s = append(s, mapping)
 	}
 
 	d.SetId(dataResourceIdHash(ids))
@@ -0,0 +1,381 @@
+package alicloud
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// subscriptionInstancesPageSize is the largest page QueryAvailableInstances returns.
+const subscriptionInstancesPageSize = 300
+
+func dataSourceAliCloudSubscriptionInstances() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAliCloudSubscriptionInstancesRead,
+		Schema: map[string]*schema.Schema{
+			"ids": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"product_code": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"product_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"subscription_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "Subscription",
+				ValidateFunc: StringInSlice([]string{"Subscription", "PayAsYouGo"}, false),
+			},
+			"instance_region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"renewal_status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: StringInSlice([]string{"AutoRenewal", "ManualRenewal", "NotRenewal"}, false),
+			},
+			"expires_within_days": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ForceNew:      true,
+				ValidateFunc:  validation.IntAtLeast(0),
+				ConflictsWith: []string{"end_time_start", "end_time_end"},
+			},
+			"end_time_start": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ValidateFunc:  validation.IsRFC3339Time,
+				ConflictsWith: []string{"expires_within_days"},
+			},
+			"end_time_end": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ValidateFunc:  validation.IsRFC3339Time,
+				ConflictsWith: []string{"expires_within_days"},
+			},
+			"tags": tagsSchema(),
+			"output_file": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"instances": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"instance_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"product_code": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"product_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"subscription_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"instance_region": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"sub_status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"create_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"end_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"stop_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"release_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"expected_release_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"days_until_expiry": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"renewal_status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"renewal_duration": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"renewal_duration_unit": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"seller": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAliCloudSubscriptionInstancesRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	now := time.Now().UTC()
+
+	request := map[string]interface{}{
+		"SubscriptionType": d.Get("subscription_type"),
+		"PageSize":         subscriptionInstancesPageSize,
+	}
+	if v, ok := d.GetOk("product_code"); ok {
+		request["ProductCode"] = v
+	}
+	if v, ok := d.GetOk("product_type"); ok {
+		request["ProductType"] = v
+	}
+	if v, ok := d.GetOk("instance_region"); ok {
+		request["Region"] = v
+	}
+	if v, ok := d.GetOk("renewal_status"); ok {
+		request["RenewStatus"] = v
+	}
+	if v, ok := d.GetOkExists("expires_within_days"); ok {
+		request["EndTimeStart"] = now.Format("2006-01-02T15:04:05Z")
+		request["EndTimeEnd"] = now.Add(time.Duration(v.(int)) * 24 * time.Hour).Format("2006-01-02T15:04:05Z")
+	}
+	if v, ok := d.GetOk("end_time_start"); ok {
+		endTimeStart, _ := time.Parse(time.RFC3339, v.(string))
+		request["EndTimeStart"] = endTimeStart.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	if v, ok := d.GetOk("end_time_end"); ok {
+		endTimeEnd, _ := time.Parse(time.RFC3339, v.(string))
+		request["EndTimeEnd"] = endTimeEnd.UTC().Format("2006-01-02T15:04:05Z")
+	}
+
+	// QueryAvailableInstances takes at most 100 instance IDs at once.
+	idBatches := []string{""}
+	if v, ok := d.GetOk("ids"); ok && len(v.([]interface{})) > 0 {
+		instanceIds := expandStringList(v.([]interface{}))
+		idBatches = idBatches[:0]
+		for index := 0; index < len(instanceIds); index += 100 {
+			idBatches = append(idBatches, strings.Join(instanceIds[index:IntMin(index+100, len(instanceIds))], ","))
+		}
+	}
+
+	action := "QueryAvailableInstances"
+	var endpoint string
+	objects := make([]interface{}, 0)
+	for _, idBatch := range idBatches {
+		result, err := fetchPages(dataSourceFetchLimit(meta), subscriptionInstancesPageSize, func(pageNumber int) ([]interface{}, int, error) {
+			pageRequest := make(map[string]interface{}, len(request)+2)
+			for k, v := range request {
+				pageRequest[k] = v
+			}
+			pageRequest["PageNum"] = pageNumber
+			if idBatch != "" {
+				pageRequest["InstanceIDs"] = idBatch
+			}
+			// Only the first page, which is read before the others, switches to the endpoint of the
+			// international site.
+			pageEndpoint := endpoint
+			var response map[string]interface{}
+			var err error
+			wait := incrementalWait(3*time.Second, 3*time.Second)
+			err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+				response, err = client.RpcPostWithEndpoint("BssOpenApi", "2017-12-14", action, nil, pageRequest, true, pageEndpoint)
+				if err != nil {
+					if NeedRetry(err) {
+						wait()
+						return resource.RetryableError(err)
+					}
+					if pageNumber == 1 && !client.IsInternationalAccount() && IsExpectedErrors(err, []string{"NotApplicable"}) && pageEndpoint == "" {
+						pageEndpoint = connectivity.BssOpenAPIEndpointInternational
+						endpoint = pageEndpoint
+						return resource.RetryableError(err)
+					}
+					return resource.NonRetryableError(err)
+				}
+				return nil
+			})
+			addDebug(action, response, pageRequest)
+			if err != nil {
+				return nil, 0, WrapErrorf(err, DataDefaultErrorMsg, "alicloud_subscription_instances", action, AlibabaCloudSdkGoERROR)
+			}
+			resp, err := jsonpath.Get("$.Data.InstanceList", response)
+			if err != nil {
+				return nil, 0, WrapErrorf(err, FailedGetAttributeMsg, action, "$.Data.InstanceList", response)
+			}
+			totalCount := -1
+			if v, err := jsonpath.Get("$.Data.TotalCount", response); err == nil && v != nil {
+				totalCount = formatInt(v)
+			}
+			result, _ := resp.([]interface{})
+			return result, totalCount, nil
+		})
+		if err != nil {
+			return WrapError(err)
+		}
+		objects = append(objects, result...)
+	}
+
+	// The Tag service only returns the resources of the region it is called in, so it is called in every region the
+	// instances are in.
+	var taggedIds map[string]map[string]bool
+	if v, ok := d.GetOk("tags"); ok && len(v.(map[string]interface{})) > 0 {
+		regions := subscriptionInstanceRegions(objects, client.RegionId)
+		taggedIds = make(map[string]map[string]bool, len(regions))
+		resourceIds := make([]map[string]bool, len(regions))
+		err := fetchParallel(dataSourceFetchLimit(meta), regions, func(i int) error {
+			regionalClient, err := client.WithRegion(regions[i])
+			if err != nil {
+				return err
+			}
+			tagService := TagService{regionalClient}
+			resourceIds[i], err = tagService.ListTaggedResourceIds(v.(map[string]interface{}))
+			return err
+		})
+		if err != nil {
+			return WrapError(err)
+		}
+		for i, region := range regions {
+			taggedIds[region] = resourceIds[i]
+		}
+	}
+
+	ids := make([]string, 0)
+	s := make([]map[string]interface{}, 0)
+	for _, v := range objects {
+		object, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if taggedIds != nil && !taggedIds[subscriptionInstanceRegion(object, client.RegionId)][fmt.Sprint(object["InstanceID"])] {
+			continue
+		}
+		mapping := subscriptionInstanceMapping(object, now)
+		ids = append(ids, fmt.Sprint(mapping["id"]))
+		s = append(s, mapping)
+	}
+
+	d.SetId(dataResourceIdHash(ids))
+	if err := d.Set("ids", ids); err != nil {
+		return WrapError(err)
+	}
+	if err := d.Set("instances", s); err != nil {
+		return WrapError(err)
+	}
+	if output, ok := d.GetOk("output_file"); ok && output.(string) != "" {
+		writeToFile(output.(string), s)
+	}
+	return nil
+}
+
+// subscriptionInstanceRegion returns the region of an instance of QueryAvailableInstances, or defaultRegion for an
+// instance that reports none.
+func subscriptionInstanceRegion(object map[string]interface{}, defaultRegion string) string {
+	if v, ok := object["Region"]; ok && v != nil && fmt.Sprint(v) != "" {
+		return fmt.Sprint(v)
+	}
+	return defaultRegion
+}
+
+// subscriptionInstanceRegions returns the regions the instances of QueryAvailableInstances are in, sorted.
+func subscriptionInstanceRegions(objects []interface{}, defaultRegion string) []string {
+	seen := make(map[string]bool)
+	regions := make([]string, 0)
+	for _, v := range objects {
+		object, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if region := subscriptionInstanceRegion(object, defaultRegion); !seen[region] {
+			seen[region] = true
+			regions = append(regions, region)
+		}
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// subscriptionInstanceMapping flattens an instance of QueryAvailableInstances, counting the whole days left from now
+// until it expires. An instance that already expired has a negative count.
+func subscriptionInstanceMapping(object map[string]interface{}, now time.Time) map[string]interface{} {
+	value := func(key string) string {
+		if v, ok := object[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+	mapping := map[string]interface{}{
+		"id":                    value("InstanceID"),
+		"instance_id":           value("InstanceID"),
+		"product_code":          value("ProductCode"),
+		"product_type":          value("ProductType"),
+		"subscription_type":     value("SubscriptionType"),
+		"instance_region":       value("Region"),
+		"status":                value("Status"),
+		"sub_status":            value("SubStatus"),
+		"create_time":           value("CreateTime"),
+		"end_time":              value("EndTime"),
+		"stop_time":             value("StopTime"),
+		"release_time":          value("ReleaseTime"),
+		"expected_release_time": value("ExpectedReleaseTime"),
+		"renewal_status":        value("RenewStatus"),
+		"renewal_duration_unit": value("RenewalDurationUnit"),
+		"seller":                value("Seller"),
+	}
+	if v, ok := object["RenewalDuration"]; ok && v != nil {
+		mapping["renewal_duration"] = formatInt(v)
+	}
+	if endTime, err := time.Parse(time.RFC3339, value("EndTime")); err == nil {
+		mapping["days_until_expiry"] = int(math.Floor(endTime.Sub(now).Hours() / 24))
+	}
+	return mapping
+}
@@ -0,0 +1,167 @@
+package alicloud
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccAliCloudSubscriptionInstancesDataSource(t *testing.T) {
+	rand := acctest.RandIntRange(1000000, 9999999)
+
+	productConf := dataSourceTestAccConfig{
+		existConfig: testAccCheckAliCloudSubscriptionInstancesSourceConfig(rand, map[string]string{
+			"product_code": `"acr"`,
+			"ids":          `["${alicloud_cr_ee_instance.default.id}"]`,
+		}),
+		fakeConfig: testAccCheckAliCloudSubscriptionInstancesSourceConfig(rand, map[string]string{
+			"product_code": `"acr"`,
+			"ids":          `["${alicloud_cr_ee_instance.default.id}_fake"]`,
+		}),
+	}
+
+	expiryConf := dataSourceTestAccConfig{
+		existConfig: testAccCheckAliCloudSubscriptionInstancesSourceConfig(rand, map[string]string{
+			"ids":                 `["${alicloud_cr_ee_instance.default.id}"]`,
+			"expires_within_days": `40`,
+			"renewal_status":      `"ManualRenewal"`,
+		}),
+		fakeConfig: testAccCheckAliCloudSubscriptionInstancesSourceConfig(rand, map[string]string{
+			"ids":                 `["${alicloud_cr_ee_instance.default.id}"]`,
+			"expires_within_days": `1`,
+			"renewal_status":      `"ManualRenewal"`,
+		}),
+	}
+
+	renewalConf := dataSourceTestAccConfig{
+		existConfig: testAccCheckAliCloudSubscriptionInstancesSourceConfig(rand, map[string]string{
+			"ids":            `["${alicloud_cr_ee_instance.default.id}"]`,
+			"renewal_status": `"ManualRenewal"`,
+		}),
+		fakeConfig: testAccCheckAliCloudSubscriptionInstancesSourceConfig(rand, map[string]string{
+			"ids":            `["${alicloud_cr_ee_instance.default.id}"]`,
+			"renewal_status": `"AutoRenewal"`,
+		}),
+	}
+
+	AliCloudSubscriptionInstancesCheckInfo.dataSourceTestCheck(t, rand, productConf, expiryConf, renewalConf)
+}
+
+var existAliCloudSubscriptionInstancesMapFunc = func(rand int) map[string]string {
+	return map[string]string{
+		"ids.#":                             "1",
+		"instances.#":                       "1",
+		"instances.0.id":                    CHECKSET,
+		"instances.0.instance_id":           CHECKSET,
+		"instances.0.product_code":          CHECKSET,
+		"instances.0.subscription_type":     "Subscription",
+		"instances.0.instance_region":       CHECKSET,
+		"instances.0.status":                CHECKSET,
+		"instances.0.create_time":           CHECKSET,
+		"instances.0.end_time":              CHECKSET,
+		"instances.0.days_until_expiry":     CHECKSET,
+		"instances.0.renewal_status":        "ManualRenewal",
+		"instances.0.renewal_duration_unit": CHECKSET,
+	}
+}
+
+var fakeAliCloudSubscriptionInstancesMapFunc = func(rand int) map[string]string {
+	return map[string]string{
+		"ids.#":       "0",
+		"instances.#": "0",
+	}
+}
+
+var AliCloudSubscriptionInstancesCheckInfo = dataSourceAttr{
+	resourceId:   "data.alicloud_subscription_instances.default",
+	existMapFunc: existAliCloudSubscriptionInstancesMapFunc,
+	fakeMapFunc:  fakeAliCloudSubscriptionInstancesMapFunc,
+}
+
+func testAccCheckAliCloudSubscriptionInstancesSourceConfig(rand int, attrMap map[string]string) string {
+	var pairs []string
+	for k, v := range attrMap {
+		pairs = append(pairs, k+" = "+v)
+	}
+	config := fmt.Sprintf(`
+variable "name" {
+  default = "tf-testacc%d"
+}
+
+resource "alicloud_cr_ee_instance" "default" {
+  payment_type   = "Subscription"
+  period         = 1
+  renew_period   = 0
+  renewal_status = "ManualRenewal"
+  instance_type  = "Basic"
+  instance_name  = var.name
+}
+
+data "alicloud_subscription_instances" "default" {
+  %s
+}
+`, rand, strings.Join(pairs, "\n  "))
+	return config
+}
+
+func TestUnitAliCloudSubscriptionInstanceMapping(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	object := map[string]interface{}{
+		"InstanceID":          "i-bp1abc",
+		"ProductCode":         "ecs",
+		"ProductType":         "ecs",
+		"SubscriptionType":    "Subscription",
+		"Region":              "cn-hangzhou",
+		"Status":              "Normal",
+		"SubStatus":           "Normal",
+		"CreateTime":          "2025-11-01T02:00:00Z",
+		"EndTime":             "2026-11-01T16:00:00Z",
+		"RenewStatus":         "ManualRenewal",
+		"RenewalDuration":     float64(1),
+		"RenewalDurationUnit": "M",
+		"Seller":              "26842",
+	}
+
+	mapping := subscriptionInstanceMapping(object, now)
+	assert.Equal(t, "i-bp1abc", mapping["id"])
+	assert.Equal(t, "i-bp1abc", mapping["instance_id"])
+	assert.Equal(t, "cn-hangzhou", mapping["instance_region"])
+	assert.Equal(t, "ManualRenewal", mapping["renewal_status"])
+	assert.Equal(t, 1, mapping["renewal_duration"])
+	assert.Equal(t, "", mapping["stop_time"])
+	assert.Equal(t, 13, mapping["days_until_expiry"])
+
+	object["EndTime"] = "2026-10-19T06:00:00Z"
+	assert.Equal(t, -1, subscriptionInstanceMapping(object, now)["days_until_expiry"])
+
+	object["EndTime"] = "2026-10-19T18:00:00Z"
+	assert.Equal(t, 0, subscriptionInstanceMapping(object, now)["days_until_expiry"])
+
+	delete(object, "EndTime")
+	delete(object, "RenewalDuration")
+	mapping = subscriptionInstanceMapping(object, now)
+	_, ok := mapping["days_until_expiry"]
+	assert.False(t, ok)
+	_, ok = mapping["renewal_duration"]
+	assert.False(t, ok)
+
+	assert.Equal(t, "i-bp1abc", tagResourceIdFromArn("acs:ecs:cn-hangzhou:123456789:instance/i-bp1abc"))
+	assert.Equal(t, "rm-bp1abc", tagResourceIdFromArn("acs:rds:cn-hangzhou:123456789:dbinstance/rm-bp1abc"))
+	assert.Equal(t, "bucket-name", tagResourceIdFromArn("acs:oss:cn-hangzhou:123456789:bucket-name"))
+	assert.Equal(t, "", tagResourceIdFromArn("i-bp1abc"))
+
+	objects := []interface{}{
+		map[string]interface{}{"InstanceID": "i-1", "Region": "cn-shanghai"},
+		map[string]interface{}{"InstanceID": "i-2", "Region": "cn-hangzhou"},
+		map[string]interface{}{"InstanceID": "i-3", "Region": ""},
+		map[string]interface{}{"InstanceID": "i-4", "Region": "cn-shanghai"},
+		map[string]interface{}{"InstanceID": "i-5"},
+	}
+	assert.Equal(t, []string{"cn-beijing", "cn-hangzhou", "cn-shanghai"}, subscriptionInstanceRegions(objects, "cn-beijing"))
+	assert.Equal(t, "cn-beijing", subscriptionInstanceRegion(objects[4].(map[string]interface{}), "cn-beijing"))
+	assert.Equal(t, []string{}, subscriptionInstanceRegions(nil, "cn-beijing"))
+}
@@ -0,0 +1,624 @@
+package alicloud
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func dataSourceAliCloudVpcReachability() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAliCloudVpcReachabilityRead,
+		Schema: map[string]*schema.Schema{
+			"source": {
+				Type:     schema.TypeList,
+				Required: true,
+				MaxItems: 1,
+				Elem:     vpcReachabilityEndpointSchema(),
+			},
+			"destination": {
+				Type:     schema.TypeList,
+				Required: true,
+				MaxItems: 1,
+				Elem:     vpcReachabilityEndpointSchema(),
+			},
+			"protocol": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: StringInSlice([]string{"tcp", "udp", "icmp"}, false),
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: IntBetween(1, 65535),
+			},
+			"reachable": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"blocking_component_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"blocking_component_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"hops": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"component_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"component_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"action": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"explanation": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func vpcReachabilityEndpointSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"instance_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"network_interface_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"vswitch_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"ip_address": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsIPv4Address,
+			},
+		},
+	}
+}
+
+func dataSourceAliCloudVpcReachabilityRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	vpcServiceV2 := VpcServiceV2{client}
+	ecsService := EcsService{client}
+
+	protocol := d.Get("protocol").(string)
+	port := d.Get("port").(int)
+	if protocol != "icmp" && port == 0 {
+		return WrapError(fmt.Errorf("port is required when protocol is %s", protocol))
+	}
+
+	source, err := resolveVpcReachabilityEndpoint(client, d.Get("source").([]interface{}), "")
+	if err != nil {
+		return WrapError(err)
+	}
+	if source.VSwitchId == "" {
+		return WrapError(fmt.Errorf("the source must be an instance, a network interface or a vSwitch, or an ip_address together with its vswitch_id"))
+	}
+	destination, err := resolveVpcReachabilityEndpoint(client, d.Get("destination").([]interface{}), source.VpcId)
+	if err != nil {
+		return WrapError(err)
+	}
+
+	path := &vpcReachabilityPath{
+		Source:         source,
+		Destination:    destination,
+		Protocol:       protocol,
+		Port:           port,
+		NetworkAcls:    make(map[string]map[string]interface{}),
+		SecurityGroups: make(map[string]map[string]interface{}),
+	}
+	if source.NetworkAclId != "" {
+		if path.NetworkAcls[source.NetworkAclId], err = vpcServiceV2.DescribeVpcNetworkAcl(source.NetworkAclId); err != nil {
+			return WrapError(err)
+		}
+	}
+	if destination.NetworkAclId != "" && path.NetworkAcls[destination.NetworkAclId] == nil {
+		if path.NetworkAcls[destination.NetworkAclId], err = vpcServiceV2.DescribeVpcNetworkAcl(destination.NetworkAclId); err != nil {
+			return WrapError(err)
+		}
+	}
+	if path.RouteEntries, err = vpcServiceV2.DescribeVpcRouteTableRouteEntries(source.RouteTableId, ""); err != nil {
+		return WrapError(err)
+	}
+	for _, groupId := range append(append([]string{}, source.SecurityGroupIds...), destination.SecurityGroupIds...) {
+		if path.SecurityGroups[groupId] != nil {
+			continue
+		}
+		if path.SecurityGroups[groupId], err = ecsService.DescribeSecurityGroupAttribute(groupId); err != nil {
+			return WrapError(err)
+		}
+	}
+
+	result := evaluateVpcReachability(path)
+	hops := make([]map[string]interface{}, 0, len(result.Hops))
+	for _, hop := range result.Hops {
+		hops = append(hops, map[string]interface{}{
+			"component_type": hop.ComponentType,
+			"component_id":   hop.ComponentId,
+			"action":         hop.Action,
+			"explanation":    hop.Explanation,
+		})
+	}
+
+	d.SetId(dataResourceIdHash([]string{source.Id, destination.Id, protocol, strconv.Itoa(port)}))
+	if err := d.Set("hops", hops); err != nil {
+		return WrapError(err)
+	}
+	d.Set("reachable", result.Reachable)
+	d.Set("blocking_component_type", result.Blocking.ComponentType)
+	d.Set("blocking_component_id", result.Blocking.ComponentId)
+	return nil
+}
+
+// vpcReachabilityEndpoint is one end of the analysed path. Prefix is a single address for instances, network
+// interfaces and IP addresses, and the whole CIDR block for a vSwitch.
+type vpcReachabilityEndpoint struct {
+	Id               string
+	Prefix           *net.IPNet
+	VpcId            string
+	VSwitchId        string
+	RouteTableId     string
+	NetworkAclId     string
+	SecurityGroupIds []string
+}
+
+// vpcReachabilityEphemeralPorts are the destination ports of return traffic, which goes back to the port the source
+// sent from.
+var vpcReachabilityEphemeralPorts = [2]int{1024, 65535}
+
+type vpcReachabilityPath struct {
+	Source      *vpcReachabilityEndpoint
+	Destination *vpcReachabilityEndpoint
+	Protocol    string
+	Port        int
+	// PortTo makes the destination ports the range from Port to PortTo, which a rule must cover as a whole. It is 0
+	// for a single port.
+	PortTo         int
+	RouteEntries   []map[string]interface{}
+	NetworkAcls    map[string]map[string]interface{}
+	SecurityGroups map[string]map[string]interface{}
+}
+
+type vpcReachabilityHop struct {
+	ComponentType string
+	ComponentId   string
+	Action        string
+	Explanation   string
+}
+
+type vpcReachabilityResult struct {
+	Reachable bool
+	Hops      []vpcReachabilityHop
+	Blocking  vpcReachabilityHop
+}
+
+func resolveVpcReachabilityEndpoint(client *connectivity.AliyunClient, config []interface{}, vpcId string) (*vpcReachabilityEndpoint, error) {
+	vpcServiceV2 := VpcServiceV2{client}
+	ecsService := EcsService{client}
+	endpoint := &vpcReachabilityEndpoint{}
+	var instanceId, networkInterfaceId, vswitchId, ipAddress string
+	if len(config) > 0 && config[0] != nil {
+		item := config[0].(map[string]interface{})
+		instanceId = fmt.Sprint(item["instance_id"])
+		networkInterfaceId = fmt.Sprint(item["network_interface_id"])
+		vswitchId = fmt.Sprint(item["vswitch_id"])
+		ipAddress = fmt.Sprint(item["ip_address"])
+	}
+
+	switch {
+	case instanceId != "" && networkInterfaceId == "":
+		object, err := ecsService.DescribeEcsInstance(instanceId)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.Id = instanceId
+		if v, err := jsonpath.Get("$.VpcAttributes.VSwitchId", object); err == nil {
+			vswitchId = fmt.Sprint(v)
+		}
+		if ipAddress == "" {
+			if v, err := jsonpath.Get("$.VpcAttributes.PrivateIpAddress.IpAddress[0]", object); err == nil {
+				ipAddress = fmt.Sprint(v)
+			}
+		}
+		if v, err := jsonpath.Get("$.SecurityGroupIds.SecurityGroupId", object); err == nil {
+			endpoint.SecurityGroupIds = expandStringList(v.([]interface{}))
+		}
+	case networkInterfaceId != "" && instanceId == "":
+		object, err := ecsService.DescribeEcsNetworkInterface(networkInterfaceId)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.Id = networkInterfaceId
+		vswitchId = fmt.Sprint(object["VSwitchId"])
+		if ipAddress == "" {
+			ipAddress = fmt.Sprint(object["PrivateIpAddress"])
+		}
+		if v, err := jsonpath.Get("$.SecurityGroupIds.SecurityGroupId", object); err == nil {
+			endpoint.SecurityGroupIds = expandStringList(v.([]interface{}))
+		}
+	case instanceId != "" && networkInterfaceId != "":
+		return nil, fmt.Errorf("only one of instance_id and network_interface_id can be set for an endpoint")
+	case ipAddress != "":
+		endpoint.Id = ipAddress
+	case vswitchId != "":
+		endpoint.Id = vswitchId
+	default:
+		return nil, fmt.Errorf("one of instance_id, network_interface_id, vswitch_id and ip_address must be set for an endpoint")
+	}
+
+	if ipAddress != "" {
+		ip := net.ParseIP(ipAddress).To4()
+		if ip == nil {
+			return nil, fmt.Errorf("%s of endpoint %s is not an IPv4 address", ipAddress, endpoint.Id)
+		}
+		endpoint.Prefix = &net.IPNet{IP: ip, Mask: net.CIDRMask(32, 32)}
+	}
+
+	// a bare IP address is looked up among the vSwitches of the source VPC and is left outside of it otherwise
+	if vswitchId == "" && vpcId != "" {
+		vswitches, err := vpcServiceV2.DescribeVpcVpcVswitches(vpcId)
+		if err != nil {
+			return nil, err
+		}
+		for _, vswitch := range vswitches {
+			if _, cidr, err := net.ParseCIDR(fmt.Sprint(vswitch["CidrBlock"])); err == nil && cidr.Contains(endpoint.Prefix.IP) {
+				vswitchId = fmt.Sprint(vswitch["VSwitchId"])
+				break
+			}
+		}
+	}
+	if vswitchId == "" {
+		return endpoint, nil
+	}
+
+	object, err := vpcServiceV2.DescribeVpcVswitch(vswitchId)
+	if err != nil {
+		return nil, err
+	}
+	_, cidr, err := net.ParseCIDR(fmt.Sprint(object["CidrBlock"]))
+	if err != nil {
+		return nil, WrapErrorf(err, FailedGetAttributeMsg, vswitchId, "CidrBlock", object)
+	}
+	if endpoint.Prefix == nil {
+		endpoint.Prefix = cidr
+	} else if !cidr.Contains(endpoint.Prefix.IP) {
+		return nil, fmt.Errorf("%s is not within %s of vSwitch %s", endpoint.Prefix.IP, cidr, vswitchId)
+	}
+	endpoint.VSwitchId = vswitchId
+	endpoint.VpcId = fmt.Sprint(object["VpcId"])
+	if v, err := jsonpath.Get("$.RouteTable.RouteTableId", object); err == nil {
+		endpoint.RouteTableId = fmt.Sprint(v)
+	}
+	if v, ok := object["NetworkAclId"].(string); ok {
+		endpoint.NetworkAclId = v
+	}
+	return endpoint, nil
+}
+
+// evaluateVpcReachability walks the forward path from the source to the destination: the source security groups,
+// the network ACL of the source vSwitch, the route table of the source vSwitch, then the network ACL and the security
+// groups of the destination. Network ACLs are stateless, so the return traffic to the ephemeral ports of the source is
+// then checked against the egress entries of the destination network ACL and the ingress entries of the source one.
+// Traffic within a single vSwitch is not subject to network ACLs or routing, and a route that leaves the VPC ends the
+// analysis at its next hop.
+func evaluateVpcReachability(path *vpcReachabilityPath) (result vpcReachabilityResult) {
+	source, destination := path.Source, path.Destination
+	step := func(hop vpcReachabilityHop) bool {
+		result.Hops = append(result.Hops, hop)
+		if hop.Action == "deny" {
+			result.Blocking = hop
+			return false
+		}
+		return true
+	}
+
+	if len(source.SecurityGroupIds) > 0 {
+		if !step(evaluateVpcReachabilitySecurityGroups(path, "egress")) {
+			return
+		}
+	}
+
+	if source.VSwitchId == destination.VSwitchId {
+		result.Reachable = step(vpcReachabilityHop{
+			ComponentType: "VSwitch",
+			ComponentId:   source.VSwitchId,
+			Action:        "forward",
+			Explanation:   fmt.Sprintf("%s and %s are in the same vSwitch, traffic is delivered directly", source.Prefix, destination.Prefix),
+		})
+		if result.Reachable && len(destination.SecurityGroupIds) > 0 {
+			result.Reachable = step(evaluateVpcReachabilitySecurityGroups(path, "ingress"))
+		}
+		return
+	}
+
+	if source.NetworkAclId != "" {
+		if !step(evaluateVpcReachabilityNetworkAcl(path, source.NetworkAclId, "egress")) {
+			return
+		}
+	}
+
+	route := vpcReachabilityLongestPrefixMatch(path.RouteEntries, destination.Prefix)
+	if route == nil {
+		step(vpcReachabilityHop{
+			ComponentType: "RouteTable",
+			ComponentId:   source.RouteTableId,
+			Action:        "deny",
+			Explanation:   fmt.Sprintf("no route in %s covers %s", source.RouteTableId, destination.Prefix),
+		})
+		return
+	}
+	nextHopType, nextHopId := "", ""
+	if v, err := jsonpath.Get("$.NextHops.NextHop[0]", route); err == nil {
+		if nextHop, ok := v.(map[string]interface{}); ok {
+			nextHopType, nextHopId = fmt.Sprint(nextHop["NextHopType"]), fmt.Sprint(nextHop["NextHopId"])
+		}
+	}
+	if !strings.EqualFold(nextHopType, "local") {
+		result.Reachable = step(vpcReachabilityHop{
+			ComponentType: "RouteTable",
+			ComponentId:   source.RouteTableId,
+			Action:        "forward",
+			Explanation: fmt.Sprintf("route %s sends traffic for %s to %s %s, the path beyond this next hop is not evaluated",
+				route["DestinationCidrBlock"], destination.Prefix, nextHopType, nextHopId),
+		})
+		return
+	}
+	step(vpcReachabilityHop{
+		ComponentType: "RouteTable",
+		ComponentId:   source.RouteTableId,
+		Action:        "forward",
+		Explanation:   fmt.Sprintf("system route %s delivers traffic for %s within the VPC", route["DestinationCidrBlock"], destination.Prefix),
+	})
+
+	if destination.NetworkAclId != "" {
+		if !step(evaluateVpcReachabilityNetworkAcl(path, destination.NetworkAclId, "ingress")) {
+			return
+		}
+	}
+	if len(destination.SecurityGroupIds) > 0 {
+		if !step(evaluateVpcReachabilitySecurityGroups(path, "ingress")) {
+			return
+		}
+	}
+
+	reply := &vpcReachabilityPath{
+		Source:      destination,
+		Destination: source,
+		Protocol:    path.Protocol,
+		Port:        vpcReachabilityEphemeralPorts[0],
+		PortTo:      vpcReachabilityEphemeralPorts[1],
+		NetworkAcls: path.NetworkAcls,
+	}
+	for _, acl := range []struct{ networkAclId, direction string }{{destination.NetworkAclId, "egress"}, {source.NetworkAclId, "ingress"}} {
+		if acl.networkAclId == "" {
+			continue
+		}
+		hop := evaluateVpcReachabilityNetworkAcl(reply, acl.networkAclId, acl.direction)
+		hop.Explanation = "return traffic: " + hop.Explanation
+		if !step(hop) {
+			return
+		}
+	}
+	result.Reachable = true
+	return
+}
+
+func vpcReachabilityLongestPrefixMatch(entries []map[string]interface{}, prefix *net.IPNet) map[string]interface{} {
+	var matched map[string]interface{}
+	matchedOnes := -1
+	for _, entry := range entries {
+		if status, ok := entry["Status"].(string); ok && status != "" && status != "Available" {
+			continue
+		}
+		_, cidr, err := net.ParseCIDR(fmt.Sprint(entry["DestinationCidrBlock"]))
+		if err != nil || !vpcReachabilityCidrCovers(cidr, prefix) {
+			continue
+		}
+		if ones, _ := cidr.Mask.Size(); ones > matchedOnes {
+			matched, matchedOnes = entry, ones
+		}
+	}
+	return matched
+}
+
+// vpcReachabilityCidrCovers reports whether outer contains every address of inner, so that a rule only applies to a
+// vSwitch endpoint when it covers the whole vSwitch.
+func vpcReachabilityCidrCovers(outer, inner *net.IPNet) bool {
+	if outer == nil || inner == nil || outer.IP.To4() == nil || inner.IP.To4() == nil {
+		return false
+	}
+	outerOnes, _ := outer.Mask.Size()
+	innerOnes, _ := inner.Mask.Size()
+	return outerOnes <= innerOnes && outer.Contains(inner.IP)
+}
+
+func vpcReachabilityPortMatches(portRange string, protocol string, port int) bool {
+	return vpcReachabilityPortRangeCovers(portRange, protocol, port, port)
+}
+
+// vpcReachabilityPortRangeCovers reports whether a port range of a rule covers every port from portFrom to portTo.
+func vpcReachabilityPortRangeCovers(portRange string, protocol string, portFrom, portTo int) bool {
+	if protocol == "icmp" || portRange == "" || portRange == "-1/-1" {
+		return true
+	}
+	parts := strings.Split(portRange, "/")
+	if len(parts) != 2 {
+		return false
+	}
+	from, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	to, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false
+	}
+	return from <= portFrom && portTo <= to
+}
+
+// evaluateVpcReachabilityNetworkAcl applies the entries of a network ACL in order, the first matching entry wins.
+func evaluateVpcReachabilityNetworkAcl(path *vpcReachabilityPath, networkAclId string, direction string) vpcReachabilityHop {
+	entriesPath, cidrKey, peer := "$.EgressAclEntries.EgressAclEntry", "DestinationCidrIp", path.Destination.Prefix
+	if direction == "ingress" {
+		entriesPath, cidrKey, peer = "$.IngressAclEntries.IngressAclEntry", "SourceCidrIp", path.Source.Prefix
+	}
+	hop := vpcReachabilityHop{ComponentType: "NetworkAcl", ComponentId: networkAclId}
+	var entries []interface{}
+	if v, err := jsonpath.Get(entriesPath, path.NetworkAcls[networkAclId]); err == nil {
+		entries, _ = v.([]interface{})
+	}
+	for _, item := range entries {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if protocol := strings.ToLower(fmt.Sprint(entry["Protocol"])); protocol != "all" && protocol != path.Protocol {
+			continue
+		}
+		portTo := path.Port
+		if path.PortTo > 0 {
+			portTo = path.PortTo
+		}
+		if !vpcReachabilityPortRangeCovers(fmt.Sprint(entry["Port"]), path.Protocol, path.Port, portTo) {
+			continue
+		}
+		if _, cidr, err := net.ParseCIDR(fmt.Sprint(entry[cidrKey])); err != nil || !vpcReachabilityCidrCovers(cidr, peer) {
+			continue
+		}
+		hop.Action = "allow"
+		if !strings.EqualFold(fmt.Sprint(entry["Policy"]), "accept") {
+			hop.Action = "deny"
+		}
+		hop.Explanation = fmt.Sprintf("%s entry %v (%s %s %s) of %s matches", direction, entry["NetworkAclEntryName"],
+			entry["Policy"], entry["Protocol"], entry[cidrKey], networkAclId)
+		return hop
+	}
+	hop.Action = "deny"
+	hop.Explanation = fmt.Sprintf("no %s entry of %s matches %s %s", direction, networkAclId, path.Protocol, peer)
+	return hop
+}
+
+// evaluateVpcReachabilitySecurityGroups merges the rules of every security group of the endpoint, orders them by
+// priority with drop rules ahead of accept rules of the same priority, and applies the first one that matches.
+// Without a matching rule, traffic between members of a group follows its inner access policy, inbound traffic is
+// denied and outbound traffic is allowed.
+func evaluateVpcReachabilitySecurityGroups(path *vpcReachabilityPath, direction string) vpcReachabilityHop {
+	endpoint, peer := path.Source, path.Destination
+	cidrKey, groupKey := "DestCidrIp", "DestGroupId"
+	if direction == "ingress" {
+		endpoint, peer = path.Destination, path.Source
+		cidrKey, groupKey = "SourceCidrIp", "SourceGroupId"
+	}
+	peerGroups := make(map[string]bool)
+	for _, groupId := range peer.SecurityGroupIds {
+		peerGroups[groupId] = true
+	}
+
+	type rule struct {
+		groupId    string
+		priority   int
+		permission map[string]interface{}
+	}
+	rules := make([]rule, 0)
+	for _, groupId := range endpoint.SecurityGroupIds {
+		v, err := jsonpath.Get("$.Permissions.Permission", path.SecurityGroups[groupId])
+		if err != nil {
+			continue
+		}
+		permissions, _ := v.([]interface{})
+		for _, item := range permissions {
+			permission, ok := item.(map[string]interface{})
+			if !ok || !strings.EqualFold(fmt.Sprint(permission["Direction"]), direction) {
+				continue
+			}
+			priority, err := strconv.Atoi(fmt.Sprint(permission["Priority"]))
+			if err != nil {
+				priority = 1
+			}
+			rules = append(rules, rule{groupId, priority, permission})
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].priority != rules[j].priority {
+			return rules[i].priority < rules[j].priority
+		}
+		return strings.EqualFold(fmt.Sprint(rules[i].permission["Policy"]), "drop") &&
+			!strings.EqualFold(fmt.Sprint(rules[j].permission["Policy"]), "drop")
+	})
+
+	hop := vpcReachabilityHop{ComponentType: "SecurityGroup"}
+	for _, r := range rules {
+		if protocol := strings.ToLower(fmt.Sprint(r.permission["IpProtocol"])); protocol != "all" && protocol != path.Protocol {
+			continue
+		}
+		if !vpcReachabilityPortMatches(fmt.Sprint(r.permission["PortRange"]), path.Protocol, path.Port) {
+			continue
+		}
+		target := ""
+		if groupId, ok := r.permission[groupKey].(string); ok && groupId != "" {
+			if !peerGroups[groupId] {
+				continue
+			}
+			target = groupId
+		} else {
+			_, cidr, err := net.ParseCIDR(fmt.Sprint(r.permission[cidrKey]))
+			if err != nil || !vpcReachabilityCidrCovers(cidr, peer.Prefix) {
+				continue
+			}
+			target = cidr.String()
+		}
+		hop.ComponentId = r.groupId
+		hop.Action = "allow"
+		if strings.EqualFold(fmt.Sprint(r.permission["Policy"]), "drop") {
+			hop.Action = "deny"
+		}
+		hop.Explanation = fmt.Sprintf("%s rule of %s (priority %d, %v %v %v %v) matches", direction, r.groupId, r.priority,
+			r.permission["Policy"], r.permission["IpProtocol"], r.permission["PortRange"], target)
+		return hop
+	}
+
+	for _, groupId := range endpoint.SecurityGroupIds {
+		if peerGroups[groupId] && strings.EqualFold(fmt.Sprint(path.SecurityGroups[groupId]["InnerAccessPolicy"]), "accept") {
+			hop.ComponentId = groupId
+			hop.Action = "allow"
+			hop.Explanation = fmt.Sprintf("no %s rule matches, both ends belong to %s which accepts traffic between its members", direction, groupId)
+			return hop
+		}
+	}
+	hop.ComponentId = strings.Join(endpoint.SecurityGroupIds, ",")
+	if direction == "egress" {
+		hop.Action = "allow"
+		hop.Explanation = fmt.Sprintf("no egress rule of %s matches, outbound traffic is allowed by default", hop.ComponentId)
+	} else {
+		hop.Action = "deny"
+		hop.Explanation = fmt.Sprintf("no ingress rule of %s matches, inbound traffic is denied by default", hop.ComponentId)
+	}
+	return hop
+}
@@ -0,0 +1,257 @@
+package alicloud
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccAliCloudVpcReachabilityDataSource(t *testing.T) {
+	rand := acctest.RandIntRange(10000, 99999)
+	name := fmt.Sprintf("tf-testacc-vpc-reachability-%d", rand)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAliCloudVpcReachabilityDataSource(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.alicloud_vpc_reachability.ssh", "reachable", "true"),
+					resource.TestCheckResourceAttr("data.alicloud_vpc_reachability.ssh", "blocking_component_id", ""),
+					resource.TestCheckResourceAttrSet("data.alicloud_vpc_reachability.ssh", "hops.#"),
+					resource.TestCheckResourceAttr("data.alicloud_vpc_reachability.mysql", "reachable", "false"),
+					resource.TestCheckResourceAttr("data.alicloud_vpc_reachability.mysql", "blocking_component_type", "SecurityGroup"),
+					resource.TestCheckResourceAttrPair("data.alicloud_vpc_reachability.mysql", "blocking_component_id", "alicloud_security_group.server", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAliCloudVpcReachabilityDataSource(name string) string {
+	return fmt.Sprintf(`
+variable "name" {
+  default = "%s"
+}
+
+data "alicloud_zones" "default" {
+  available_resource_creation = "VSwitch"
+}
+
+resource "alicloud_vpc" "default" {
+  vpc_name   = var.name
+  cidr_block = "172.16.0.0/16"
+}
+
+resource "alicloud_vswitch" "client" {
+  vswitch_name = var.name
+  vpc_id       = alicloud_vpc.default.id
+  cidr_block   = "172.16.1.0/24"
+  zone_id      = data.alicloud_zones.default.zones.0.id
+}
+
+resource "alicloud_vswitch" "server" {
+  vswitch_name = var.name
+  vpc_id       = alicloud_vpc.default.id
+  cidr_block   = "172.16.2.0/24"
+  zone_id      = data.alicloud_zones.default.zones.0.id
+}
+
+resource "alicloud_security_group" "client" {
+  security_group_name = var.name
+  vpc_id              = alicloud_vpc.default.id
+}
+
+resource "alicloud_security_group" "server" {
+  security_group_name = var.name
+  vpc_id              = alicloud_vpc.default.id
+}
+
+resource "alicloud_security_group_rule" "ssh" {
+  type              = "ingress"
+  ip_protocol       = "tcp"
+  port_range        = "22/22"
+  security_group_id = alicloud_security_group.server.id
+  cidr_ip           = alicloud_vswitch.client.cidr_block
+}
+
+resource "alicloud_ecs_network_interface" "client" {
+  network_interface_name = var.name
+  vswitch_id             = alicloud_vswitch.client.id
+  security_group_ids     = [alicloud_security_group.client.id]
+}
+
+resource "alicloud_ecs_network_interface" "server" {
+  network_interface_name = var.name
+  vswitch_id             = alicloud_vswitch.server.id
+  security_group_ids     = [alicloud_security_group.server.id]
+}
+
+data "alicloud_vpc_reachability" "ssh" {
+  source {
+    network_interface_id = alicloud_ecs_network_interface.client.id
+  }
+  destination {
+    network_interface_id = alicloud_ecs_network_interface.server.id
+  }
+  protocol   = "tcp"
+  port       = 22
+  depends_on = [alicloud_security_group_rule.ssh]
+}
+
+data "alicloud_vpc_reachability" "mysql" {
+  source {
+    network_interface_id = alicloud_ecs_network_interface.client.id
+  }
+  destination {
+    network_interface_id = alicloud_ecs_network_interface.server.id
+  }
+  protocol   = "tcp"
+  port       = 3306
+  depends_on = [alicloud_security_group_rule.ssh]
+}
+`, name)
+}
+
+func TestUnitAliCloudVpcReachabilityEvaluate(t *testing.T) {
+	prefix := func(cidr string) *net.IPNet {
+		_, n, err := net.ParseCIDR(cidr)
+		assert.Nil(t, err)
+		return n
+	}
+	newPath := func() *vpcReachabilityPath {
+		return &vpcReachabilityPath{
+			Source: &vpcReachabilityEndpoint{
+				Id: "eni-client", Prefix: prefix("172.16.1.10/32"), VpcId: "vpc-1", VSwitchId: "vsw-client",
+				RouteTableId: "vtb-1", NetworkAclId: "nacl-client", SecurityGroupIds: []string{"sg-client"},
+			},
+			Destination: &vpcReachabilityEndpoint{
+				Id: "eni-server", Prefix: prefix("172.16.2.20/32"), VpcId: "vpc-1", VSwitchId: "vsw-server",
+				RouteTableId: "vtb-1", NetworkAclId: "nacl-server", SecurityGroupIds: []string{"sg-server"},
+			},
+			Protocol: "tcp",
+			Port:     22,
+			RouteEntries: []map[string]interface{}{
+				{"DestinationCidrBlock": "172.16.1.0/24", "Type": "System", "Status": "Available", "NextHops": map[string]interface{}{"NextHop": []interface{}{map[string]interface{}{"NextHopType": "local", "NextHopId": ""}}}},
+				{"DestinationCidrBlock": "172.16.2.0/24", "Type": "System", "Status": "Available", "NextHops": map[string]interface{}{"NextHop": []interface{}{map[string]interface{}{"NextHopType": "local", "NextHopId": ""}}}},
+				{"DestinationCidrBlock": "0.0.0.0/0", "Type": "Custom", "Status": "Available", "NextHops": map[string]interface{}{"NextHop": []interface{}{map[string]interface{}{"NextHopType": "NatGateway", "NextHopId": "ngw-1"}}}},
+			},
+			NetworkAcls: map[string]map[string]interface{}{
+				"nacl-client": {
+					"EgressAclEntries": map[string]interface{}{"EgressAclEntry": []interface{}{
+						map[string]interface{}{"NetworkAclEntryName": "all", "Policy": "accept", "Protocol": "all", "Port": "-1/-1", "DestinationCidrIp": "0.0.0.0/0"},
+					}},
+					"IngressAclEntries": map[string]interface{}{"IngressAclEntry": []interface{}{
+						map[string]interface{}{"NetworkAclEntryName": "replies", "Policy": "accept", "Protocol": "tcp", "Port": "1024/65535", "SourceCidrIp": "172.16.0.0/16"},
+					}},
+				},
+				"nacl-server": {
+					"IngressAclEntries": map[string]interface{}{"IngressAclEntry": []interface{}{
+						map[string]interface{}{"NetworkAclEntryName": "no-mysql", "Policy": "drop", "Protocol": "tcp", "Port": "3306/3306", "SourceCidrIp": "0.0.0.0/0"},
+						map[string]interface{}{"NetworkAclEntryName": "vpc", "Policy": "accept", "Protocol": "all", "Port": "-1/-1", "SourceCidrIp": "172.16.0.0/16"},
+					}},
+					"EgressAclEntries": map[string]interface{}{"EgressAclEntry": []interface{}{
+						map[string]interface{}{"NetworkAclEntryName": "all", "Policy": "accept", "Protocol": "all", "Port": "-1/-1", "DestinationCidrIp": "0.0.0.0/0"},
+					}},
+				},
+			},
+			SecurityGroups: map[string]map[string]interface{}{
+				"sg-client": {"InnerAccessPolicy": "Accept", "Permissions": map[string]interface{}{"Permission": []interface{}{}}},
+				"sg-server": {"InnerAccessPolicy": "Accept", "Permissions": map[string]interface{}{"Permission": []interface{}{
+					map[string]interface{}{"Direction": "ingress", "IpProtocol": "TCP", "PortRange": "1/1024", "SourceCidrIp": "172.16.1.0/24", "Policy": "Accept", "Priority": "10"},
+					map[string]interface{}{"Direction": "ingress", "IpProtocol": "TCP", "PortRange": "22/22", "SourceCidrIp": "172.16.1.10/32", "Policy": "Drop", "Priority": "10"},
+					map[string]interface{}{"Direction": "ingress", "IpProtocol": "ALL", "PortRange": "-1/-1", "SourceGroupId": "sg-client", "Policy": "Accept", "Priority": "1"},
+				}}},
+			},
+		}
+	}
+
+	// the group rule has the highest priority
+	result := evaluateVpcReachability(newPath())
+	assert.True(t, result.Reachable)
+	assert.Len(t, result.Hops, 7)
+	assert.Equal(t, "sg-server", result.Hops[4].ComponentId)
+	assert.Contains(t, result.Hops[4].Explanation, "sg-client")
+	assert.Equal(t, "return traffic: egress entry all (accept all 0.0.0.0/0) of nacl-server matches", result.Hops[5].Explanation)
+	assert.Equal(t, "return traffic: ingress entry replies (accept tcp 172.16.0.0/16) of nacl-client matches", result.Hops[6].Explanation)
+
+	// network ACLs are stateless, the return traffic needs an entry of its own
+	path := newPath()
+	path.NetworkAcls["nacl-client"]["IngressAclEntries"] = map[string]interface{}{"IngressAclEntry": []interface{}{
+		map[string]interface{}{"NetworkAclEntryName": "ssh", "Policy": "accept", "Protocol": "tcp", "Port": "22/22", "SourceCidrIp": "172.16.0.0/16"},
+	}}
+	result = evaluateVpcReachability(path)
+	assert.False(t, result.Reachable)
+	assert.Equal(t, vpcReachabilityHop{ComponentType: "NetworkAcl", ComponentId: "nacl-client", Action: "deny",
+		Explanation: "return traffic: no ingress entry of nacl-client matches tcp 172.16.2.20/32"}, result.Blocking)
+	path = newPath()
+	delete(path.NetworkAcls["nacl-server"], "EgressAclEntries")
+	result = evaluateVpcReachability(path)
+	assert.False(t, result.Reachable)
+	assert.Equal(t, "nacl-server", result.Blocking.ComponentId)
+	assert.Contains(t, result.Blocking.Explanation, "return traffic: no egress entry")
+
+	// a drop rule wins over an accept rule of the same priority
+	path = newPath()
+	path.Source.SecurityGroupIds = []string{"sg-other"}
+	path.SecurityGroups["sg-other"] = map[string]interface{}{}
+	result = evaluateVpcReachability(path)
+	assert.False(t, result.Reachable)
+	assert.Equal(t, "SecurityGroup", result.Blocking.ComponentType)
+	assert.Equal(t, "sg-server", result.Blocking.ComponentId)
+
+	// the destination network ACL is evaluated before its security groups
+	path = newPath()
+	path.Port = 3306
+	result = evaluateVpcReachability(path)
+	assert.False(t, result.Reachable)
+	assert.Equal(t, vpcReachabilityHop{ComponentType: "NetworkAcl", ComponentId: "nacl-server", Action: "deny",
+		Explanation: "ingress entry no-mysql (drop tcp 0.0.0.0/0) of nacl-server matches"}, result.Blocking)
+
+	// without a matching ingress rule inbound traffic is denied
+	path = newPath()
+	path.Source.SecurityGroupIds = []string{"sg-other"}
+	path.Source.Prefix = prefix("172.16.1.11/32")
+	path.Port = 2048
+	result = evaluateVpcReachability(path)
+	assert.False(t, result.Reachable)
+	assert.Contains(t, result.Blocking.Explanation, "denied by default")
+
+	// a destination outside of the VPC ends at the next hop of the route
+	path = newPath()
+	path.Destination = &vpcReachabilityEndpoint{Id: "8.8.8.8", Prefix: prefix("8.8.8.8/32")}
+	result = evaluateVpcReachability(path)
+	assert.True(t, result.Reachable)
+	assert.Len(t, result.Hops, 3)
+	assert.Contains(t, result.Hops[2].Explanation, "NatGateway ngw-1")
+
+	// no route covers the destination
+	path.RouteEntries = path.RouteEntries[:2]
+	result = evaluateVpcReachability(path)
+	assert.False(t, result.Reachable)
+	assert.Equal(t, "RouteTable", result.Blocking.ComponentType)
+
+	// traffic within a vSwitch skips network ACLs and routing
+	path = newPath()
+	path.Destination.VSwitchId = "vsw-client"
+	path.Destination.Prefix = prefix("172.16.1.20/32")
+	path.Port = 3306
+	result = evaluateVpcReachability(path)
+	assert.True(t, result.Reachable)
+	assert.Equal(t, "VSwitch", result.Hops[1].ComponentType)
+
+	// a vSwitch endpoint only matches rules covering the whole vSwitch
+	assert.True(t, vpcReachabilityCidrCovers(prefix("172.16.0.0/16"), prefix("172.16.1.0/24")))
+	assert.False(t, vpcReachabilityCidrCovers(prefix("172.16.1.0/25"), prefix("172.16.1.0/24")))
+	assert.True(t, vpcReachabilityPortMatches("20/30", "tcp", 22))
+	assert.False(t, vpcReachabilityPortMatches("20/30", "udp", 31))
+	assert.True(t, vpcReachabilityPortMatches("20/30", "icmp", 0))
+	assert.True(t, vpcReachabilityPortRangeCovers("1024/65535", "tcp", 1024, 65535))
+	assert.False(t, vpcReachabilityPortRangeCovers("32768/65535", "tcp", 1024, 65535))
+}
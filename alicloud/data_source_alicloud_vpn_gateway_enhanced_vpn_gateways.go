@@ -189,6 +189,7 @@ func dataSourceAliCloudVpnGatewayEnhancedVpnGatewayRead(d *schema.ResourceData,
 
 	ids := make([]string, 0)
 	s := make([]map[string]interface{}, 0)
+	detailIds := make([]string, 0)
 	for _, objectRaw := range objects {
 		mapping := map[string]interface{}{}
 
@@ -210,20 +211,24 @@ func dataSourceAliCloudVpnGatewayEnhancedVpnGatewayRead(d *schema.ResourceData,
 		tagsMaps, _ := jsonpath.Get("$.Tags.Tag", objectRaw)
 		mapping["tags"] = tagsToMap(tagsMaps)
 
-		if detailedEnabled := d.Get("enable_details"); !detailedEnabled.(bool) {
-			ids = append(ids, fmt.Sprint(mapping["id"]))
-			s = append(s, mapping)
-			continue
-		}
+		ids = append(ids, fmt.Sprint(mapping["id"]))
+		s = append(s, mapping)
+		detailIds = append(detailIds, fmt.Sprint(objectRaw["VpnGatewayId"]))
+	}
 
-		id := fmt.Sprint(objectRaw["VpnGatewayId"])
-		mapping, err = dataSourceAliCloudVpnGatewayEnhancedVpnGatewayReadDescription(d, id, mapping, meta)
+	if detailedEnabled := d.Get("enable_details"); detailedEnabled.(bool) {
+		err = fetchParallel(dataSourceFetchLimit(meta), detailIds, func(i int) error {
+			mapping, err := dataSourceAliCloudVpnGatewayEnhancedVpnGatewayReadDescription(d, detailIds[i], s[i], meta)
+			if err != nil {
+				return err
+			}
+			s[i] = mapping
+			ids[i] = fmt.Sprint(mapping["id"])
+			return nil
+		})
 		if err != nil {
 			return WrapError(err)
 		}
-
-		ids = append(ids, fmt.Sprint(mapping["id"]))
-		s = append(s, mapping)
 	}
 
 	d.SetId(dataResourceIdHash(ids))
@@ -477,6 +477,7 @@ func dataSourceAliCloudWafv3DefenseRuleRead(d *schema.ResourceData, meta interfa
 
 	ids := make([]string, 0)
 	s := make([]map[string]interface{}, 0)
+	detailIds := make([]string, 0)
 	for _, objectRaw := range objects {
 		mapping := map[string]interface{}{}
 
@@ -702,20 +703,24 @@ func dataSourceAliCloudWafv3DefenseRuleRead(d *schema.ResourceData, meta interfa
 		}
 		mapping["config"] = configMaps
 
-		if detailedEnabled := d.Get("enable_details"); !detailedEnabled.(bool) {
-			ids = append(ids, fmt.Sprint(mapping["id"]))
-			s = append(s, mapping)
-			continue
-		}
+		ids = append(ids, fmt.Sprint(mapping["id"]))
+		s = append(s, mapping)
+		detailIds = append(detailIds, fmt.Sprint(request["InstanceId"], ":", objectRaw["DefenseType"], ":", objectRaw["RuleId"]))
+	}
 
-		id := fmt.Sprint(request["InstanceId"], ":", objectRaw["DefenseType"], ":", objectRaw["RuleId"])
-		mapping, err = dataSourceAliCloudWafv3DefenseRuleReadDescription(d, id, mapping, meta)
+	if detailedEnabled := d.Get("enable_details"); detailedEnabled.(bool) {
+		err = fetchParallel(dataSourceFetchLimit(meta), detailIds, func(i int) error {
+			mapping, err := dataSourceAliCloudWafv3DefenseRuleReadDescription(d, detailIds[i], s[i], meta)
+			if err != nil {
+				return err
+			}
+			s[i] = mapping
+			ids[i] = fmt.Sprint(mapping["id"])
+			return nil
+		})
 		if err != nil {
 			return WrapError(err)
 		}
-
-		ids = append(ids, fmt.Sprint(mapping["id"]))
-		s = append(s, mapping)
 	}
 
 	d.SetId(dataResourceIdHash(ids))
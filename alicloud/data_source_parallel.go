@@ -0,0 +1,180 @@
+package alicloud
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/features"
+)
+
+// parallelFetchErrorLimit caps how many of the failed items a parallelFetchError lists.
+const parallelFetchErrorLimit = 10
+
+// parallelFetchError reports the items a parallel fetch could not read, in the order of the items.
+type parallelFetchError struct {
+	Total  int
+	Keys   []string
+	Errors []error
+}
+
+func (e *parallelFetchError) Error() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("failed to fetch %d of %d items:", len(e.Errors), e.Total))
+	for i, err := range e.Errors {
+		if i == parallelFetchErrorLimit {
+			b.WriteString(fmt.Sprintf("\n  - and %d more", len(e.Errors)-parallelFetchErrorLimit))
+			break
+		}
+		b.WriteString(fmt.Sprintf("\n  - %s: %s", e.Keys[i], err))
+	}
+	return b.String()
+}
+
+// fetchLimit bounds the requests of a parallel fetch. concurrency caps the calls in flight for the fetch, and every
+// call beyond the first also takes a slot of slots, which the data sources of the provider share. The first call
+// needs no slot, so a fetch always makes progress however many slots the others hold. Without slots, a fetch is
+// only bounded by concurrency.
+type fetchLimit struct {
+	concurrency int
+	slots       chan struct{}
+}
+
+// acquire takes a slot for one more call in flight, and reports false when all slots are taken.
+func (limit fetchLimit) acquire() bool {
+	if limit.slots == nil {
+		return true
+	}
+	select {
+	case limit.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// dataSourceFetchLimit returns how many requests a data source may have in flight at the same time, and the slots it
+// shares with the other data sources of the provider for the requests beyond the first.
+func dataSourceFetchLimit(meta interface{}) fetchLimit {
+	if client, ok := meta.(*connectivity.AliyunClient); ok && client != nil {
+		limit := fetchLimit{concurrency: client.Features.DataSource.Concurrency, slots: client.DataSourceSlots()}
+		if limit.concurrency < 1 {
+			limit.concurrency = features.Default().DataSource.Concurrency
+		}
+		return limit
+	}
+	return fetchLimit{concurrency: features.Default().DataSource.Concurrency}
+}
+
+// fetchParallel calls fetch for every index of keys with at most limit.concurrency calls in flight. A call beyond the
+// first is only started when it gets a slot of limit.slots, and it is tried again before each item, so a fetch speeds
+// up once other data sources release their slots. The calls write their result by index, which keeps the output in
+// the order of keys whatever order they finish in. Every item is fetched even when some fail, and the failures are
+// returned together as a *parallelFetchError keyed by keys. Throttling is left to the retries inside fetch, as in
+// every other call to the API.
+func fetchParallel(limit fetchLimit, keys []string, fetch func(i int) error) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(keys))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	work := func(slot bool) {
+		defer wg.Done()
+		if slot {
+			defer func() { <-limit.slots }()
+		}
+		for i := range indexes {
+			errs[i] = fetchParallelItem(fetch, i)
+		}
+	}
+	wg.Add(1)
+	go work(false)
+	workers := 1
+	for i := range keys {
+		if workers < limit.concurrency && limit.acquire() {
+			wg.Add(1)
+			workers++
+			go work(limit.slots != nil)
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	fetchErr := &parallelFetchError{Total: len(keys)}
+	for i, err := range errs {
+		if err != nil {
+			fetchErr.Keys = append(fetchErr.Keys, keys[i])
+			fetchErr.Errors = append(fetchErr.Errors, err)
+		}
+	}
+	if len(fetchErr.Errors) > 0 {
+		return fetchErr
+	}
+	return nil
+}
+
+// fetchParallelItem turns a panic of fetch into the error of its item, as a panic in a worker would otherwise
+// take down the provider with no hint of the item it was reading.
+func fetchParallelItem(fetch func(i int) error, i int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fetch(i)
+}
+
+// fetchPages reads every page of a paged API, starting at page 1. fetch returns the items of a page and the total
+// number of items, or -1 when the API does not report it. With a total, the pages after the first are fetched
+// concurrently; without one, they are fetched one after the other until a page comes back short. The items are
+// returned in page order.
+func fetchPages(limit fetchLimit, pageSize int, fetch func(pageNumber int) ([]interface{}, int, error)) ([]interface{}, error) {
+	items, total, err := fetch(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if total < 0 {
+		for pageNumber := 2; len(items) > 0 && len(items)%pageSize == 0; pageNumber++ {
+			page, _, err := fetch(pageNumber)
+			if err != nil {
+				return nil, err
+			}
+			if len(page) == 0 {
+				break
+			}
+			items = append(items, page...)
+		}
+		return items, nil
+	}
+
+	pageCount := (total + pageSize - 1) / pageSize
+	if pageCount <= 1 || len(items) < pageSize {
+		return items, nil
+	}
+
+	pages := make([][]interface{}, pageCount-1)
+	keys := make([]string, pageCount-1)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("page %d", i+2)
+	}
+	err = fetchParallel(limit, keys, func(i int) error {
+		page, _, err := fetch(i + 2)
+		if err != nil {
+			return err
+		}
+		pages[i] = page
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, page := range pages {
+		items = append(items, page...)
+	}
+	return items, nil
+}
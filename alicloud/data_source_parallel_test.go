@@ -0,0 +1,184 @@
+package alicloud
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitFetchParallel(t *testing.T) {
+	keys := make([]string, 50)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("i-%02d", i)
+	}
+
+	var inFlight, maxInFlight int32
+	results := make([]string, len(keys))
+	err := fetchParallel(fetchLimit{concurrency: 4}, keys, func(i int) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		// later items finish first
+		time.Sleep(time.Duration(len(keys)-i) * 100 * time.Microsecond)
+		results[i] = "detail-" + keys[i]
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.True(t, maxInFlight <= 4, "%d calls in flight", maxInFlight)
+	for i, result := range results {
+		assert.Equal(t, "detail-"+keys[i], result)
+	}
+
+	var calls int32
+	err = fetchParallel(fetchLimit{concurrency: 3}, keys, func(i int) error {
+		atomic.AddInt32(&calls, 1)
+		switch {
+		case i == 7:
+			panic("boom")
+		case i%10 == 3:
+			return fmt.Errorf("Throttling.User")
+		}
+		return nil
+	})
+	assert.Equal(t, int32(len(keys)), calls)
+	if assert.IsType(t, &parallelFetchError{}, err) {
+		fetchErr := err.(*parallelFetchError)
+		assert.Equal(t, []string{"i-03", "i-07", "i-13", "i-23", "i-33", "i-43"}, fetchErr.Keys)
+		assert.Equal(t, strings.Join([]string{
+			"failed to fetch 6 of 50 items:",
+			"  - i-03: Throttling.User",
+			"  - i-07: panic: boom",
+			"  - i-13: Throttling.User",
+			"  - i-23: Throttling.User",
+			"  - i-33: Throttling.User",
+			"  - i-43: Throttling.User",
+		}, "\n"), err.Error())
+	}
+
+	err = fetchParallel(fetchLimit{concurrency: 8}, keys, func(i int) error {
+		return fmt.Errorf("denied")
+	})
+	assert.True(t, strings.HasSuffix(err.Error(), "  - i-09: denied\n  - and 40 more"), err.Error())
+
+	assert.Nil(t, fetchParallel(fetchLimit{concurrency: 8}, nil, func(i int) error {
+		return fmt.Errorf("unexpected")
+	}))
+}
+
+func TestUnitFetchParallelSharedSlots(t *testing.T) {
+	keys := make([]string, 20)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("i-%02d", i)
+	}
+	limit := fetchLimit{concurrency: 8, slots: make(chan struct{}, 2)}
+
+	var inFlight, maxInFlight int32
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for f := range errs {
+		wg.Add(1)
+		go func(f int) {
+			defer wg.Done()
+			errs[f] = fetchParallel(limit, keys, func(i int) error {
+				n := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				for {
+					m := atomic.LoadInt32(&maxInFlight)
+					if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				return nil
+			})
+		}(f)
+	}
+	wg.Wait()
+	assert.Equal(t, []error{nil, nil, nil}, errs)
+	// one call per fetch, and the two slots shared by all of them
+	assert.True(t, maxInFlight <= 5, "%d calls in flight", maxInFlight)
+	assert.Equal(t, 0, len(limit.slots))
+
+	// a fetch inside a fetch makes progress with every slot taken
+	limit.slots <- struct{}{}
+	limit.slots <- struct{}{}
+	var calls int32
+	err := fetchParallel(limit, keys[:3], func(i int) error {
+		return fetchParallel(limit, keys, func(j int) error {
+			atomic.AddInt32(&calls, 1)
+			return nil
+		})
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3*len(keys)), calls)
+}
+
+func TestUnitFetchPages(t *testing.T) {
+	items := make([]interface{}, 23)
+	for i := range items {
+		items[i] = i
+	}
+	page := func(pageNumber, pageSize int) []interface{} {
+		start := (pageNumber - 1) * pageSize
+		if start >= len(items) {
+			return []interface{}{}
+		}
+		return items[start:IntMin(start+pageSize, len(items))]
+	}
+
+	var mutex sync.Mutex
+	fetched := make([]int, 0)
+	result, err := fetchPages(fetchLimit{concurrency: 4}, 5, func(pageNumber int) ([]interface{}, int, error) {
+		mutex.Lock()
+		fetched = append(fetched, pageNumber)
+		mutex.Unlock()
+		time.Sleep(time.Duration(10-pageNumber) * time.Millisecond)
+		return page(pageNumber, 5), len(items), nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, items, result)
+	assert.Equal(t, 1, fetched[0])
+	assert.ElementsMatch(t, []int{1, 2, 3, 4, 5}, fetched)
+
+	fetched = fetched[:0]
+	result, err = fetchPages(fetchLimit{concurrency: 4}, 5, func(pageNumber int) ([]interface{}, int, error) {
+		fetched = append(fetched, pageNumber)
+		return page(pageNumber, 5), -1, nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, items, result)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, fetched)
+
+	result, err = fetchPages(fetchLimit{concurrency: 4}, 23, func(pageNumber int) ([]interface{}, int, error) {
+		return page(pageNumber, 23), -1, nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, items, result)
+
+	_, err = fetchPages(fetchLimit{concurrency: 4}, 5, func(pageNumber int) ([]interface{}, int, error) {
+		if pageNumber == 4 {
+			return nil, 0, fmt.Errorf("ServiceUnavailable")
+		}
+		return page(pageNumber, 5), len(items), nil
+	})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "failed to fetch 1 of 4 items:\n  - page 4: ServiceUnavailable", err.Error())
+	}
+
+	_, err = fetchPages(fetchLimit{concurrency: 4}, 5, func(pageNumber int) ([]interface{}, int, error) {
+		return nil, 0, fmt.Errorf("Forbidden.RAM")
+	})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Forbidden.RAM", err.Error())
+	}
+}
@@ -13,6 +13,7 @@ import (
 	sls "github.com/aliyun/aliyun-log-go-sdk"
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
 	"github.com/denverdino/aliyungo/common"
 )
 
@@ -204,7 +205,17 @@ func IsExpectedErrors(err error, expectCodes []string) bool {
 	return false
 }
 
+// NeedRetry reports whether the API call that failed with err is to be retried. The caller retries it when it is,
+// so the retry is recorded on the trace of the call.
 func NeedRetry(err error) bool {
+	if needRetry(err) {
+		connectivity.TraceRetry(err)
+		return true
+	}
+	return false
+}
+
+func needRetry(err error) bool {
 	if err == nil {
 		return false
 	}
@@ -5,7 +5,10 @@ package features
 
 // Features mirrors the provider's features block.
 type Features struct {
-	EcsInstance EcsInstance
+	EcsInstance   EcsInstance
+	TagPolicy     TagPolicy
+	CapacityCheck CapacityCheck
+	DataSource    DataSource
 }
 
 // EcsInstance holds the toggles of the features.ecs_instance block.
@@ -15,6 +18,36 @@ type EcsInstance struct {
 	ReplaceOnImageUpdate bool
 }
 
+// TagPolicy holds the toggles of the features.tag_policy block.
+type TagPolicy struct {
+	// Enforce validates the planned tags of every taggable resource against the effective tag policy,
+	// so a violation fails the plan instead of the apply.
+	Enforce bool
+	// TargetId is the resource directory member whose effective tag policy is enforced. Empty means
+	// the account the provider is authenticated as.
+	TargetId string
+}
+
+// CapacityCheck holds the toggles of the features.capacity_check block.
+type CapacityCheck struct {
+	// Enabled adds up the capacity planned per region and zone, and fails the plan when it would exceed a
+	// quota or ask for an instance type that is out of stock.
+	Enabled bool
+	// VcpuQuotaActionCode, EipQuotaActionCode and DiskQuotaActionCode override the quotas the planned vCPUs of
+	// pay-as-you-go instances, EIPs and GiB of pay-as-you-go disks are checked against. Empty means the default one.
+	VcpuQuotaActionCode string
+	EipQuotaActionCode  string
+	DiskQuotaActionCode string
+}
+
+// DataSource holds the toggles of the features.data_source block.
+type DataSource struct {
+	// Concurrency bounds how many pages or per-item details a data source fetches at the same time.
+	// 1 fetches them one after the other. The requests beyond the first of each data source share
+	// Concurrency-1 slots across the provider, see AliyunClient.DataSourceSlots.
+	Concurrency int
+}
+
 // Default returns the behaviour of a provider that configures no features block at all. Defaults
 // live here as well as in the schema because an absent nested block contributes no schema default.
 func Default() Features {
@@ -22,5 +55,14 @@ func Default() Features {
 		EcsInstance: EcsInstance{
 			ReplaceOnImageUpdate: false,
 		},
+		TagPolicy: TagPolicy{
+			Enforce: false,
+		},
+		CapacityCheck: CapacityCheck{
+			Enabled: false,
+		},
+		DataSource: DataSource{
+			Concurrency: 8,
+		},
 	}
 }
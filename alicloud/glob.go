@@ -0,0 +1,39 @@
+package alicloud
+
+import (
+	"path"
+	"strings"
+)
+
+// globMatchAny reports whether a slash separated path matches one of the patterns, see globMatch.
+func globMatchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if globMatch(strings.Split(pattern, "/"), strings.Split(name, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// globMatch matches path segments against pattern segments. Besides the path.Match syntax within a
+// segment, a "**" segment matches any number of directories.
+func globMatch(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if globMatch(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,18 @@
+package alicloud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitGlobMatch(t *testing.T) {
+	assert.True(t, globMatchAny([]string{"*.html"}, "index.html"))
+	assert.False(t, globMatchAny([]string{"*.html"}, "docs/index.html"))
+	assert.True(t, globMatchAny([]string{"**/*.html"}, "index.html"))
+	assert.True(t, globMatchAny([]string{"**/*.html"}, "docs/a/b/index.html"))
+	assert.True(t, globMatchAny([]string{"docs/**"}, "docs/a/b.txt"))
+	assert.False(t, globMatchAny([]string{"docs/**/b.txt"}, "doc/b.txt"))
+	assert.True(t, globMatchAny([]string{"a.txt", "b?.txt"}, "b1.txt"))
+	assert.False(t, globMatchAny(nil, "a.txt"))
+}
@@ -0,0 +1,398 @@
+package alicloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// importLookup is an import ID that names the resource instead of identifying it, written as
+// name=<name> or tag:<key>=<value>. It is resolved to the real ID through the List/Describe API of
+// the resource, so it must match exactly one resource.
+type importLookup struct {
+	Name     string
+	TagKey   string
+	TagValue string
+}
+
+func (l *importLookup) String() string {
+	if l.TagKey != "" {
+		return fmt.Sprintf("tag:%s=%s", l.TagKey, l.TagValue)
+	}
+	return fmt.Sprintf("name=%s", l.Name)
+}
+
+// parseImportLookup reports whether id uses the lookup syntax. Any other id, including the
+// colon separated ids of child resources, is left to the resource's own parsing.
+func parseImportLookup(id string) (*importLookup, bool, error) {
+	switch {
+	case strings.HasPrefix(id, "name="):
+		name := strings.TrimPrefix(id, "name=")
+		if name == "" {
+			return nil, true, fmt.Errorf("invalid import id %q: the name of name=<name> can not be empty", id)
+		}
+		return &importLookup{Name: name}, true, nil
+	case strings.HasPrefix(id, "tag:"):
+		parts := strings.SplitN(strings.TrimPrefix(id, "tag:"), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, true, fmt.Errorf("invalid import id %q: expected tag:<key>=<value>", id)
+		}
+		return &importLookup{TagKey: parts[0], TagValue: parts[1]}, true, nil
+	}
+	return nil, false, nil
+}
+
+// importLookupFunc returns the ids of every resource matching the lookup.
+type importLookupFunc func(client *connectivity.AliyunClient, lookup *importLookup) ([]string, error)
+
+// resourceImportStateByLookup returns an importer that accepts the lookup syntax next to the plain
+// id, which is passed through unchanged.
+func resourceImportStateByLookup(resourceType string, lookupFunc importLookupFunc) schema.StateFunc {
+	return func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		lookup, ok, err := parseImportLookup(d.Id())
+		if err != nil {
+			return nil, WrapError(err)
+		}
+		if !ok {
+			return []*schema.ResourceData{d}, nil
+		}
+		ids, err := lookupFunc(meta.(*connectivity.AliyunClient), lookup)
+		if err != nil {
+			return nil, WrapError(err)
+		}
+		id, err := singleImportLookupId(resourceType, lookup, ids)
+		if err != nil {
+			return nil, WrapError(err)
+		}
+		d.SetId(id)
+		return []*schema.ResourceData{d}, nil
+	}
+}
+
+func singleImportLookupId(resourceType string, lookup *importLookup, ids []string) (string, error) {
+	ids = Unique(ids)
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("no %s matches %s", resourceType, lookup)
+	case 1:
+		return ids[0], nil
+	}
+	return "", fmt.Errorf("%s matches %d %s resources (%s), import one of them by id instead", lookup, len(ids), resourceType, strings.Join(ids, ", "))
+}
+
+// importLookupPaging is the pagination style of a List/Describe API.
+type importLookupPaging int
+
+const (
+	importLookupPageNumber importLookupPaging = iota
+	importLookupNextToken
+	importLookupMarker
+)
+
+// rpcImportLookup describes how a paged RPC List/Describe API filters by name and by tag.
+type rpcImportLookup struct {
+	product   string
+	version   string
+	action    string
+	request   map[string]interface{}
+	paging    importLookupPaging
+	nameParam string
+	// tagParam builds the tag filter of the request, keyed by the request field.
+	tagParam  func(key, value string) (map[string]interface{}, error)
+	itemsPath string
+	idField   string
+	nameField string
+}
+
+// rpcTagListParam is the Tag.N.Key/Tag.N.Value filter of most RPC APIs.
+func rpcTagListParam(key, value string) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"Tag": ConvertTags(map[string]interface{}{key: value}),
+	}, nil
+}
+
+func (r rpcImportLookup) lookup(client *connectivity.AliyunClient, lookup *importLookup) ([]string, error) {
+	request := map[string]interface{}{}
+	for k, v := range r.request {
+		request[k] = v
+	}
+	if lookup.TagKey != "" {
+		tags, err := r.tagParam(lookup.TagKey, lookup.TagValue)
+		if err != nil {
+			return nil, WrapError(err)
+		}
+		for k, v := range tags {
+			request[k] = v
+		}
+	} else if r.nameParam != "" {
+		request[r.nameParam] = lookup.Name
+	}
+	switch r.paging {
+	case importLookupPageNumber:
+		request["PageSize"] = PageSizeLarge
+		request["PageNumber"] = 1
+	case importLookupNextToken:
+		request["MaxResults"] = PageSizeLarge
+	case importLookupMarker:
+		request["MaxItems"] = PageSizeLarge
+	}
+
+	ids := make([]string, 0)
+	for {
+		var response map[string]interface{}
+		var err error
+		wait := incrementalWait(3*time.Second, 3*time.Second)
+		err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+			response, err = client.RpcPost(r.product, r.version, r.action, nil, request, true)
+			if err != nil {
+				if NeedRetry(err) {
+					wait()
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		})
+		addDebug(r.action, response, request)
+		if err != nil {
+			return nil, WrapErrorf(err, DefaultErrorMsg, lookup.String(), r.action, AlibabaCloudSdkGoERROR)
+		}
+		resp, err := jsonpath.Get(r.itemsPath, response)
+		if err != nil {
+			return nil, WrapErrorf(err, FailedGetAttributeMsg, r.action, r.itemsPath, response)
+		}
+		result, _ := resp.([]interface{})
+		for _, v := range result {
+			item, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			// Most name filters are fuzzy, so the name is matched again here.
+			if lookup.TagKey == "" && fmt.Sprint(item[r.nameField]) != lookup.Name {
+				continue
+			}
+			ids = append(ids, fmt.Sprint(item[r.idField]))
+		}
+
+		switch r.paging {
+		case importLookupPageNumber:
+			if len(result) < PageSizeLarge {
+				return ids, nil
+			}
+			request["PageNumber"] = request["PageNumber"].(int) + 1
+		case importLookupNextToken:
+			nextToken, _ := response["NextToken"].(string)
+			if nextToken == "" {
+				return ids, nil
+			}
+			request["NextToken"] = nextToken
+		case importLookupMarker:
+			if truncated, ok := response["IsTruncated"].(bool); !ok || !truncated {
+				return ids, nil
+			}
+			request["Marker"] = response["Marker"]
+		}
+	}
+}
+
+func vpcImportLookup(client *connectivity.AliyunClient, lookup *importLookup) ([]string, error) {
+	return rpcImportLookup{
+		product:   "Vpc",
+		version:   "2016-04-28",
+		action:    "DescribeVpcs",
+		request:   map[string]interface{}{"RegionId": client.RegionId},
+		nameParam: "VpcName",
+		tagParam:  rpcTagListParam,
+		itemsPath: "$.Vpcs.Vpc",
+		idField:   "VpcId",
+		nameField: "VpcName",
+	}.lookup(client, lookup)
+}
+
+func vswitchImportLookup(client *connectivity.AliyunClient, lookup *importLookup) ([]string, error) {
+	return rpcImportLookup{
+		product:   "Vpc",
+		version:   "2016-04-28",
+		action:    "DescribeVSwitches",
+		request:   map[string]interface{}{"RegionId": client.RegionId},
+		nameParam: "VSwitchName",
+		tagParam:  rpcTagListParam,
+		itemsPath: "$.VSwitches.VSwitch",
+		idField:   "VSwitchId",
+		nameField: "VSwitchName",
+	}.lookup(client, lookup)
+}
+
+func securityGroupImportLookup(client *connectivity.AliyunClient, lookup *importLookup) ([]string, error) {
+	return rpcImportLookup{
+		product:   "Ecs",
+		version:   "2014-05-26",
+		action:    "DescribeSecurityGroups",
+		request:   map[string]interface{}{"RegionId": client.RegionId},
+		nameParam: "SecurityGroupName",
+		tagParam:  rpcTagListParam,
+		itemsPath: "$.SecurityGroups.SecurityGroup",
+		idField:   "SecurityGroupId",
+		nameField: "SecurityGroupName",
+	}.lookup(client, lookup)
+}
+
+func instanceImportLookup(client *connectivity.AliyunClient, lookup *importLookup) ([]string, error) {
+	return rpcImportLookup{
+		product:   "Ecs",
+		version:   "2014-05-26",
+		action:    "DescribeInstances",
+		request:   map[string]interface{}{"RegionId": client.RegionId},
+		paging:    importLookupNextToken,
+		nameParam: "InstanceName",
+		tagParam:  rpcTagListParam,
+		itemsPath: "$.Instances.Instance",
+		idField:   "InstanceId",
+		nameField: "InstanceName",
+	}.lookup(client, lookup)
+}
+
+func slbLoadBalancerImportLookup(client *connectivity.AliyunClient, lookup *importLookup) ([]string, error) {
+	return rpcImportLookup{
+		product:   "Slb",
+		version:   "2014-05-15",
+		action:    "DescribeLoadBalancers",
+		request:   map[string]interface{}{"RegionId": client.RegionId},
+		nameParam: "LoadBalancerName",
+		tagParam:  rpcTagListParam,
+		itemsPath: "$.LoadBalancers.LoadBalancer",
+		idField:   "LoadBalancerId",
+		nameField: "LoadBalancerName",
+	}.lookup(client, lookup)
+}
+
+func albLoadBalancerImportLookup(client *connectivity.AliyunClient, lookup *importLookup) ([]string, error) {
+	return rpcImportLookup{
+		product:   "Alb",
+		version:   "2020-06-16",
+		action:    "ListLoadBalancers",
+		paging:    importLookupNextToken,
+		nameParam: "LoadBalancerNames.1",
+		tagParam:  rpcTagListParam,
+		itemsPath: "$.LoadBalancers",
+		idField:   "LoadBalancerId",
+		nameField: "LoadBalancerName",
+	}.lookup(client, lookup)
+}
+
+func nlbLoadBalancerImportLookup(client *connectivity.AliyunClient, lookup *importLookup) ([]string, error) {
+	return rpcImportLookup{
+		product:   "Nlb",
+		version:   "2022-04-30",
+		action:    "ListLoadBalancers",
+		request:   map[string]interface{}{"RegionId": client.RegionId},
+		paging:    importLookupNextToken,
+		nameParam: "LoadBalancerNames.1",
+		tagParam:  rpcTagListParam,
+		itemsPath: "$.LoadBalancers",
+		idField:   "LoadBalancerId",
+		nameField: "LoadBalancerName",
+	}.lookup(client, lookup)
+}
+
+func dbInstanceImportLookup(client *connectivity.AliyunClient, lookup *importLookup) ([]string, error) {
+	return rpcImportLookup{
+		product: "Rds",
+		version: "2014-08-15",
+		action:  "DescribeDBInstances",
+		request: map[string]interface{}{"RegionId": client.RegionId},
+		// DescribeDBInstances has no exact name filter, DBInstanceDescription is matched locally.
+		tagParam: func(key, value string) (map[string]interface{}, error) {
+			bs, err := json.Marshal(map[string]string{key: value})
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"Tags": string(bs)}, nil
+		},
+		itemsPath: "$.Items.DBInstance",
+		idField:   "DBInstanceId",
+		nameField: "DBInstanceDescription",
+	}.lookup(client, lookup)
+}
+
+func ramRoleImportLookup(client *connectivity.AliyunClient, lookup *importLookup) ([]string, error) {
+	return rpcImportLookup{
+		product: "Ram",
+		version: "2015-05-01",
+		action:  "ListRoles",
+		paging:  importLookupMarker,
+		tagParam: func(key, value string) (map[string]interface{}, error) {
+			tags, err := convertListMapToJsonString(ConvertTags(map[string]interface{}{key: value}))
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"Tag": tags}, nil
+		},
+		itemsPath: "$.Roles.Role",
+		idField:   "RoleName",
+		nameField: "RoleName",
+	}.lookup(client, lookup)
+}
+
+func ramUserImportLookup(client *connectivity.AliyunClient, lookup *importLookup) ([]string, error) {
+	return rpcImportLookup{
+		product: "Ram",
+		version: "2015-05-01",
+		action:  "ListUsers",
+		paging:  importLookupMarker,
+		tagParam: func(key, value string) (map[string]interface{}, error) {
+			tags, err := convertListMapToJsonString(ConvertTags(map[string]interface{}{key: value}))
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"Tag": tags}, nil
+		},
+		itemsPath: "$.Users.User",
+		idField:   "UserId",
+		nameField: "UserName",
+	}.lookup(client, lookup)
+}
+
+// ossBucketImportLookup resolves a tag lookup through ListBuckets. The id of a bucket is its name,
+// so a name lookup only checks that the bucket exists.
+func ossBucketImportLookup(client *connectivity.AliyunClient, lookup *importLookup) ([]string, error) {
+	var options []oss.Option
+	if lookup.TagKey != "" {
+		options = append(options, oss.TagKey(lookup.TagKey), oss.TagValue(lookup.TagValue))
+	} else {
+		options = append(options, oss.Prefix(lookup.Name))
+	}
+	ids := make([]string, 0)
+	nextMarker := ""
+	for {
+		pageOptions := options
+		if nextMarker != "" {
+			pageOptions = append(pageOptions, oss.Marker(nextMarker))
+		}
+		raw, err := client.WithOssClient(func(ossClient *oss.Client) (interface{}, error) {
+			return ossClient.ListBuckets(pageOptions...)
+		})
+		if err != nil {
+			return nil, WrapErrorf(err, DefaultErrorMsg, lookup.String(), "ListBuckets", AliyunOssGoSdk)
+		}
+		addDebug("ListBuckets", raw, map[string]interface{}{"options": pageOptions})
+		response, _ := raw.(oss.ListBucketsResult)
+		for _, bucket := range response.Buckets {
+			if lookup.TagKey == "" && bucket.Name != lookup.Name {
+				continue
+			}
+			ids = append(ids, bucket.Name)
+		}
+		if !response.IsTruncated || response.NextMarker == "" {
+			return ids, nil
+		}
+		nextMarker = response.NextMarker
+	}
+}
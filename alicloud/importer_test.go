@@ -0,0 +1,53 @@
+package alicloud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitImporterParseImportLookup(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		expect    *importLookup
+		isLookup  bool
+		expectErr bool
+	}{
+		{"plain id", "vpc-abc123", nil, false, false},
+		{"composite id", "vpc-abc123:vtb-abc123", nil, false, false},
+		{"name", "name=my-vpc", &importLookup{Name: "my-vpc"}, true, false},
+		{"name with equal sign", "name=a=b", &importLookup{Name: "a=b"}, true, false},
+		{"empty name", "name=", nil, true, true},
+		{"tag", "tag:Env=prod", &importLookup{TagKey: "Env", TagValue: "prod"}, true, false},
+		{"tag with empty value", "tag:Env=", &importLookup{TagKey: "Env"}, true, false},
+		{"tag without value", "tag:Env", nil, true, true},
+		{"tag without key", "tag:=prod", nil, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookup, ok, err := parseImportLookup(tt.id)
+			assert.Equal(t, tt.isLookup, ok)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expect, lookup)
+		})
+	}
+}
+
+func TestUnitImporterSingleImportLookupId(t *testing.T) {
+	lookup := &importLookup{TagKey: "Env", TagValue: "prod"}
+
+	id, err := singleImportLookupId("alicloud_vpc", lookup, []string{"vpc-1", "vpc-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "vpc-1", id)
+
+	_, err = singleImportLookupId("alicloud_vpc", lookup, nil)
+	assert.EqualError(t, err, "no alicloud_vpc matches tag:Env=prod")
+
+	_, err = singleImportLookupId("alicloud_vpc", lookup, []string{"vpc-1", "vpc-2"})
+	assert.EqualError(t, err, "tag:Env=prod matches 2 alicloud_vpc resources (vpc-1, vpc-2), import one of them by id instead")
+}
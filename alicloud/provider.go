@@ -830,6 +830,7 @@ func Provider() terraform.ResourceProvider {
 			"alicloud_resource_manager_delegated_administrators":        dataSourceAlicloudResourceManagerDelegatedAdministrators(),
 			"alicloud_polardb_global_database_networks":                 dataSourceAlicloudPolarDBGlobalDatabaseNetworks(),
 			"alicloud_vpc_ipv4_gateways":                                dataSourceAlicloudVpcIpv4Gateways(),
+			"alicloud_vpc_reachability":                                 dataSourceAliCloudVpcReachability(),
 			"alicloud_api_gateway_backends":                             dataSourceAlicloudApiGatewayBackends(),
 			"alicloud_vpc_prefix_lists":                                 dataSourceAlicloudVpcPrefixLists(),
 			"alicloud_cms_event_rules":                                  dataSourceAlicloudCmsEventRules(),
@@ -904,7 +905,9 @@ func Provider() terraform.ResourceProvider {
 			"alicloud_threat_detection_honeypot_presets":                dataSourceAlicloudThreatDetectionHoneypotPresets(),
 			"alicloud_cen_transit_router_multicast_domain_sources":      dataSourceAlicloudCenTransitRouterMulticastDomainSources(),
 			"alicloud_bss_open_api_products":                            dataSourceAlicloudBssOpenApiProducts(),
+			"alicloud_price_estimate":                                   dataSourceAliCloudPriceEstimate(),
 			"alicloud_bss_open_api_pricing_modules":                     dataSourceAlicloudBssOpenApiPricingModules(),
+			"alicloud_subscription_instances":                           dataSourceAliCloudSubscriptionInstances(),
 			"alicloud_service_catalog_provisioned_products":             dataSourceAlicloudServiceCatalogProvisionedProducts(),
 			"alicloud_service_catalog_product_as_end_users":             dataSourceAlicloudServiceCatalogProductAsEndUsers(),
 			"alicloud_service_catalog_product_versions":                 dataSourceAlicloudServiceCatalogProductVersions(),
@@ -1093,6 +1096,8 @@ func Provider() terraform.ResourceProvider {
 			"alicloud_esa_routine_code_deployment":                          resourceAliCloudEsaRoutineCodeDeployment(),
 			"alicloud_esa_video_processing":                                 resourceAliCloudEsaVideoProcessing(),
 			"alicloud_esa_kv":                                               resourceAliCloudEsaKv(),
+			"alicloud_esa_kv_entries":                                       resourceAliCloudEsaKvEntries(),
+			"alicloud_esa_cache_refresh":                                    resourceAliCloudEsaCacheRefresh(),
 			"alicloud_lindorm_public_network":                               resourceAliCloudLindormPublicNetwork(),
 			"alicloud_eflo_vsc":                                             resourceAliCloudEfloVsc(),
 			"alicloud_ecs_ram_role_attachment":                              resourceAliCloudEcsRamRoleAttachment(),
@@ -1451,6 +1456,7 @@ func Provider() terraform.ResourceProvider {
 			"alicloud_slb_server_certificate":        resourceAlicloudSlbServerCertificate(),
 			"alicloud_oss_bucket":                    resourceAlicloudOssBucket(),
 			"alicloud_oss_bucket_object":             resourceAlicloudOssBucketObject(),
+			"alicloud_oss_bucket_objects_sync":       resourceAliCloudOssBucketObjectsSync(),
 			"alicloud_oss_bucket_replication":        resourceAlicloudOssBucketReplication(),
 			"alicloud_ons_instance":                  resourceAlicloudOnsInstance(),
 			"alicloud_ons_topic":                     resourceAlicloudOnsTopic(),
@@ -1500,6 +1506,8 @@ func Provider() terraform.ResourceProvider {
 			"alicloud_cdn_domain":                                            resourceAlicloudCdnDomain(),
 			"alicloud_cdn_domain_new":                                        resourceAliCloudCdnDomain(),
 			"alicloud_cdn_domain_config":                                     resourceAliCloudCdnDomainConfig(),
+			"alicloud_cdn_domain_configs":                                    resourceAliCloudCdnDomainConfigs(),
+			"alicloud_cdn_cache_refresh":                                     resourceAliCloudCdnCacheRefresh(),
 			"alicloud_router_interface":                                      resourceAlicloudRouterInterface(),
 			"alicloud_router_interface_connection":                           resourceAlicloudRouterInterfaceConnection(),
 			"alicloud_ots_table":                                             resourceAlicloudOtsTable(),
@@ -1775,6 +1783,7 @@ func Provider() terraform.ResourceProvider {
 			"alicloud_data_works_folder":                                     resourceAlicloudDataWorksFolder(),
 			"alicloud_arms_alert_contact_group":                              resourceAlicloudArmsAlertContactGroup(),
 			"alicloud_dcdn_domain_config":                                    resourceAliCloudDcdnDomainConfig(),
+			"alicloud_dcdn_cache_refresh":                                    resourceAliCloudDcdnCacheRefresh(),
 			"alicloud_scdn_domain_config":                                    resourceAlicloudScdnDomainConfig(),
 			"alicloud_cloud_storage_gateway_gateway":                         resourceAliCloudCloudStorageGatewayGateway(),
 			"alicloud_lindorm_instance":                                      resourceAliCloudLindormInstance(),
@@ -2142,8 +2151,23 @@ func Provider() terraform.ResourceProvider {
 			"alicloud_polardb_zonal_db_cluster":                              resourceAliCloudPolarDbZonalCluster(),
 			"alicloud_polardb_zonal_endpoint":                                resourceAlicloudPolarDBZonalEndpoint(),
 			"alicloud_polardb_zonal_account":                                 resourceAlicloudPolarDBZonalAccount(),
+			"alicloud_kms_key_material":                                      resourceAliCloudKmsKeyMaterial(),
 		},
 	}
+	for name, r := range provider.ResourcesMap {
+		if tags, ok := r.Schema["tags"]; ok && tags.Type == schema.TypeMap {
+			r.CustomizeDiff = tagPolicyCustomizeDiff(name, r.CustomizeDiff)
+		}
+		if usageFunc, ok := capacityCheckResources[name]; ok {
+			r.CustomizeDiff = capacityCheckCustomizeDiff(name, r.Schema, usageFunc, r.CustomizeDiff)
+		}
+		tracedResource(name, r, false)
+		regionalResource(r, false)
+	}
+	for name, r := range provider.DataSourcesMap {
+		tracedResource(name, r, true)
+		regionalResource(r, true)
+	}
 	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
 		return providerConfigure(d, provider)
 	}
@@ -2533,6 +2557,9 @@ func providerConfigure(d *schema.ResourceData, p *schema.Provider) (interface{},
 				"for more information about providing credentials.")
 	}
 
+	if err := connectivity.InitTracing(); err != nil {
+		log.Printf("[WARN] traces are not exported: %v", err)
+	}
 	client, err := config.Client()
 	if err != nil {
 		return nil, err
@@ -2991,6 +3018,75 @@ func featuresSchema() *schema.Schema {
 					},
 					Description: "The behaviour toggles of the `alicloud_instance` resource.",
 				},
+				"tag_policy": {
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enforce": {
+								Type:        schema.TypeBool,
+								Optional:    true,
+								Default:     false,
+								Description: "Whether the planned `tags` of every taggable resource are validated against the effective tag policy, so a violation fails the plan.",
+							},
+							"target_id": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "The ID of the resource directory member whose effective tag policy is enforced. Defaults to the account the provider is authenticated as.",
+							},
+						},
+					},
+					Description: "The plan-time enforcement of the tag policies attached to the account.",
+				},
+				"capacity_check": {
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:        schema.TypeBool,
+								Optional:    true,
+								Default:     false,
+								Description: "Whether the vCPU, EIP and disk capacity planned per region is checked against the quotas of the account, and the planned instance types against the stock of their zone, so a shortage fails the plan.",
+							},
+							"vcpu_quota_action_code": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "The action code of the quota the planned vCPUs of pay-as-you-go instances are checked against, instead of the default one.",
+							},
+							"eip_quota_action_code": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "The action code of the quota the planned EIPs are checked against, instead of the default one.",
+							},
+							"disk_quota_action_code": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "The action code of the quota the planned capacity of pay-as-you-go disks is checked against, instead of the default one.",
+							},
+						},
+					},
+					Description: "The plan-time check of the planned capacity against quotas and stock.",
+				},
+				"data_source": {
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"concurrency": {
+								Type:         schema.TypeInt,
+								Optional:     true,
+								Default:      8,
+								ValidateFunc: validation.IntBetween(1, 64),
+								Description:  "How many pages or per-item details a data source fetches at the same time. `1` fetches them one after the other.",
+							},
+						},
+					},
+					Description: "The concurrency of the data sources that page through results and fetch per-item details.",
+				},
 			},
 		},
 		Description: descriptions["features"],
@@ -3011,6 +3107,36 @@ func expandFeatures(featuresList []interface{}) features.Features {
 			expanded.EcsInstance.ReplaceOnImageUpdate = v
 		}
 	}
+	if tagPolicyList, ok := featuresMap["tag_policy"].([]interface{}); ok && len(tagPolicyList) > 0 && tagPolicyList[0] != nil {
+		tagPolicy := tagPolicyList[0].(map[string]interface{})
+		if v, ok := tagPolicy["enforce"].(bool); ok {
+			expanded.TagPolicy.Enforce = v
+		}
+		if v, ok := tagPolicy["target_id"].(string); ok {
+			expanded.TagPolicy.TargetId = strings.TrimSpace(v)
+		}
+	}
+	if capacityCheckList, ok := featuresMap["capacity_check"].([]interface{}); ok && len(capacityCheckList) > 0 && capacityCheckList[0] != nil {
+		capacityCheck := capacityCheckList[0].(map[string]interface{})
+		if v, ok := capacityCheck["enabled"].(bool); ok {
+			expanded.CapacityCheck.Enabled = v
+		}
+		if v, ok := capacityCheck["vcpu_quota_action_code"].(string); ok {
+			expanded.CapacityCheck.VcpuQuotaActionCode = strings.TrimSpace(v)
+		}
+		if v, ok := capacityCheck["eip_quota_action_code"].(string); ok {
+			expanded.CapacityCheck.EipQuotaActionCode = strings.TrimSpace(v)
+		}
+		if v, ok := capacityCheck["disk_quota_action_code"].(string); ok {
+			expanded.CapacityCheck.DiskQuotaActionCode = strings.TrimSpace(v)
+		}
+	}
+	if dataSourceList, ok := featuresMap["data_source"].([]interface{}); ok && len(dataSourceList) > 0 && dataSourceList[0] != nil {
+		dataSource := dataSourceList[0].(map[string]interface{})
+		if v, ok := dataSource["concurrency"].(int); ok && v > 0 {
+			expanded.DataSource.Concurrency = v
+		}
+	}
 	return expanded
 }
 
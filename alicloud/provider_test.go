@@ -121,6 +121,29 @@ func TestProviderFeatures(t *testing.T) {
 			}},
 			expected: features.Default(),
 		},
+		{
+			name:     "an empty tag_policy block",
+			features: []interface{}{map[string]interface{}{"tag_policy": []interface{}{}}},
+			expected: features.Default(),
+		},
+		{
+			name: "tag_policy enforced for a resource directory member",
+			features: []interface{}{map[string]interface{}{
+				"tag_policy": []interface{}{map[string]interface{}{"enforce": true, "target_id": " 151266687691**** "}},
+			}},
+			expected: features.Features{
+				TagPolicy: features.TagPolicy{Enforce: true, TargetId: "151266687691****"},
+			},
+		},
+		{
+			name: "capacity_check enabled with a vCPU quota of its own",
+			features: []interface{}{map[string]interface{}{
+				"capacity_check": []interface{}{map[string]interface{}{"enabled": true, "vcpu_quota_action_code": " q_custom-vcpu "}},
+			}},
+			expected: features.Features{
+				CapacityCheck: features.CapacityCheck{Enabled: true, VcpuQuotaActionCode: "q_custom-vcpu"},
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
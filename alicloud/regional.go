@@ -0,0 +1,129 @@
+package alicloud
+
+import (
+	"regexp"
+
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// regionalImportIdPattern matches the region suffix of an import id written as <id>@<region>. The suffix has to look
+// like a region id, so ids that contain an email address keep their @.
+var regionalImportIdPattern = regexp.MustCompile(`^(.+)@([a-z]{2,}(-[a-z0-9]+)+)$`)
+
+// parseRegionalImportId splits an import id written as <id>@<region>. Any other id is returned unchanged with an
+// empty region.
+func parseRegionalImportId(id string) (string, string) {
+	if parts := regionalImportIdPattern.FindStringSubmatch(id); parts != nil {
+		return parts[1], parts[2]
+	}
+	return id, ""
+}
+
+// regionalClient returns the client of region, or meta itself when region is empty.
+func regionalClient(meta interface{}, region string) (interface{}, error) {
+	client, ok := meta.(*connectivity.AliyunClient)
+	if !ok || client == nil || region == "" {
+		return meta, nil
+	}
+	regional, err := client.WithRegion(region)
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	return regional, nil
+}
+
+// regionalResource adds the region argument to a resource or data source, and runs all of its functions with the
+// client of that region. Resources and data sources that already have a region argument of their own keep it and are
+// left unchanged. The region is stored in state, so a resource keeps using the region it was created in.
+func regionalResource(r *schema.Resource, isDataSource bool) {
+	if _, ok := r.Schema["region"]; ok {
+		return
+	}
+	r.Schema["region"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+		ForceNew: !isDataSource,
+	}
+
+	wrap := func(f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+		if f == nil {
+			return nil
+		}
+		return func(d *schema.ResourceData, meta interface{}) error {
+			client, err := regionalClient(meta, d.Get("region").(string))
+			if err != nil {
+				return err
+			}
+			if err := f(d, client); err != nil {
+				return err
+			}
+			if aliyunClient, ok := client.(*connectivity.AliyunClient); ok && d.Id() != "" {
+				d.Set("region", aliyunClient.RegionId)
+			}
+			return nil
+		}
+	}
+	r.Read = wrap(r.Read)
+	if isDataSource {
+		return
+	}
+	r.Create = wrap(r.Create)
+	r.Update = wrap(r.Update)
+	if r.Delete != nil {
+		del := r.Delete
+		r.Delete = func(d *schema.ResourceData, meta interface{}) error {
+			client, err := regionalClient(meta, d.Get("region").(string))
+			if err != nil {
+				return err
+			}
+			return del(d, client)
+		}
+	}
+	if r.Exists != nil {
+		exists := r.Exists
+		r.Exists = func(d *schema.ResourceData, meta interface{}) (bool, error) {
+			client, err := regionalClient(meta, d.Get("region").(string))
+			if err != nil {
+				return false, err
+			}
+			return exists(d, client)
+		}
+	}
+	if r.CustomizeDiff != nil {
+		customizeDiff := r.CustomizeDiff
+		r.CustomizeDiff = func(diff *schema.ResourceDiff, meta interface{}) error {
+			client, err := regionalClient(meta, diff.Get("region").(string))
+			if err != nil {
+				return err
+			}
+			return customizeDiff(diff, client)
+		}
+	}
+	if r.Importer != nil && r.Importer.State != nil {
+		state := r.Importer.State
+		r.Importer.State = func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			id, region := parseRegionalImportId(d.Id())
+			if region == "" {
+				return state(d, meta)
+			}
+			d.SetId(id)
+			d.Set("region", region)
+			client, err := regionalClient(meta, region)
+			if err != nil {
+				return nil, err
+			}
+			results, err := state(d, client)
+			if err != nil {
+				return nil, err
+			}
+			for _, result := range results {
+				if result.Get("region").(string) == "" {
+					result.Set("region", region)
+				}
+			}
+			return results, nil
+		}
+	}
+}
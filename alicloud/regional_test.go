@@ -0,0 +1,56 @@
+package alicloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitRegionalImportId(t *testing.T) {
+	for id, expected := range map[string][2]string{
+		"vpc-abc123":                       {"vpc-abc123", ""},
+		"vpc-abc123@cn-shanghai":           {"vpc-abc123", "cn-shanghai"},
+		"name=web@ap-southeast-1":          {"name=web", "ap-southeast-1"},
+		"lb-123:listener-80@eu-central-1":  {"lb-123:listener-80", "eu-central-1"},
+		"user@example.com":                 {"user@example.com", ""},
+		"ops@example.com@cn-hangzhou":      {"ops@example.com", "cn-hangzhou"},
+		"@cn-hangzhou":                     {"@cn-hangzhou", ""},
+		"i-abc@CN-HANGZHOU":                {"i-abc@CN-HANGZHOU", ""},
+		"cn-hangzhou-finance-1@cn-beijing": {"cn-hangzhou-finance-1", "cn-beijing"},
+	} {
+		id2, region := parseRegionalImportId(id)
+		assert.Equal(t, expected, [2]string{id2, region}, id)
+	}
+}
+
+func TestUnitRegionalResource(t *testing.T) {
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Required: true},
+		},
+		Importer: &schema.ResourceImporter{State: schema.ImportStatePassthrough},
+	}
+	regionalResource(r, false)
+	assert.True(t, r.Schema["region"].Optional)
+	assert.True(t, r.Schema["region"].Computed)
+	assert.True(t, r.Schema["region"].ForceNew)
+	assert.Nil(t, r.Create)
+
+	d := r.TestResourceData()
+	d.SetId("vpc-abc123@cn-shanghai")
+	results, err := r.Importer.State(d, nil)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "vpc-abc123", results[0].Id())
+	assert.Equal(t, "cn-shanghai", results[0].Get("region"))
+
+	ds := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"region": {Type: schema.TypeString, Required: true},
+		},
+	}
+	regionalResource(ds, true)
+	assert.True(t, ds.Schema["region"].Required)
+	assert.False(t, ds.Schema["region"].Computed)
+}
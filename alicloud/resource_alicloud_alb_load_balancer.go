@@ -19,7 +19,7 @@ func resourceAliCloudAlbLoadBalancer() *schema.Resource {
 		Update: resourceAliCloudAlbLoadBalancerUpdate,
 		Delete: resourceAliCloudAlbLoadBalancerDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByLookup("alicloud_alb_load_balancer", albLoadBalancerImportLookup),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(5 * time.Minute),
@@ -0,0 +1,386 @@
+package alicloud
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// cacheRefreshApi is the API a cache refresh resource submits its tasks to. CDN and DCDN serve the same operations
+// under different names.
+type cacheRefreshApi struct {
+	ResourceName   string
+	Product        string
+	Version        string
+	RefreshAction  string
+	PreloadAction  string
+	PreloadTaskId  string
+	QuotaAction    string
+	DescribeAction string
+}
+
+var cdnCacheRefreshApi = cacheRefreshApi{
+	ResourceName:   "alicloud_cdn_cache_refresh",
+	Product:        "Cdn",
+	Version:        "2018-05-10",
+	RefreshAction:  "RefreshObjectCaches",
+	PreloadAction:  "PushObjectCache",
+	PreloadTaskId:  "PushTaskId",
+	QuotaAction:    "DescribeRefreshQuota",
+	DescribeAction: "DescribeRefreshTaskById",
+}
+
+const (
+	// cacheRefreshBatchSize is the number of paths submitted per task, within the limits of every object type.
+	cacheRefreshBatchSize = 100
+	// cacheRefreshDescribeSize is the number of tasks described per call.
+	cacheRefreshDescribeSize = 10
+)
+
+func resourceAliCloudCdnCacheRefresh() *schema.Resource {
+	return cacheRefreshResource(cdnCacheRefreshApi)
+}
+
+func cacheRefreshResource(api cacheRefreshApi) *schema.Resource {
+	return &schema.Resource{
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return cacheRefreshCreate(api, d, meta)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return nil
+		},
+		Delete: func(d *schema.ResourceData, meta interface{}) error {
+			log.Printf("[WARN] Cannot destroy %s. Terraform will remove this resource from the state file, the caches are not affected.", api.ResourceName)
+			return nil
+		},
+		CustomizeDiff: func(d *schema.ResourceDiff, meta interface{}) error {
+			if d.Get("task_type").(string) == "Preload" && d.Get("object_type").(string) != "File" {
+				return fmt.Errorf("object_type must be File when task_type is Preload, got %s", d.Get("object_type"))
+			}
+			return nil
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"paths": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"object_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "File",
+				ValidateFunc: StringInSlice([]string{"File", "Directory", "Regex", "IgnoreParams"}, false),
+			},
+			"task_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "Refresh",
+				ValidateFunc: StringInSlice([]string{"Refresh", "Preload"}, false),
+			},
+			"area": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: StringInSlice([]string{"domestic", "overseas"}, false),
+			},
+			"force": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"task_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"failed_paths": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func cacheRefreshCreate(api cacheRefreshApi, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	taskType := d.Get("task_type").(string)
+	objectType := d.Get("object_type").(string)
+	paths := expandStringList(d.Get("paths").([]interface{}))
+
+	action := api.RefreshAction
+	taskIdPath := "$.RefreshTaskId"
+	if taskType == "Preload" {
+		action = api.PreloadAction
+		taskIdPath = "$." + api.PreloadTaskId
+	}
+
+	taskIds := make([]string, 0)
+	for start := 0; start < len(paths); {
+		remain, err := cacheRefreshQuotaLeft(client, api, taskType, objectType)
+		if err != nil {
+			if len(taskIds) > 0 {
+				err = fmt.Errorf("%s, after the tasks %s were submitted for %d of the %d paths", err, strings.Join(taskIds, ","), start, len(paths))
+			}
+			return WrapErrorf(err, DefaultErrorMsg, api.ResourceName, api.QuotaAction, AlibabaCloudSdkGoERROR)
+		}
+		batch := cacheRefreshBatches(paths[start:], cacheRefreshBatchSize)[0]
+		if remain >= 0 && remain < len(batch) {
+			batch = batch[:remain]
+		}
+		start += len(batch)
+
+		var response map[string]interface{}
+		request := map[string]interface{}{
+			"ObjectPath": strings.Join(batch, "\n"),
+		}
+		if taskType == "Preload" {
+			if v, ok := d.GetOk("area"); ok {
+				request["Area"] = v
+			}
+		} else {
+			request["ObjectType"] = objectType
+			if v, ok := d.GetOk("force"); ok {
+				request["Force"] = v
+			}
+		}
+
+		wait := incrementalWait(3*time.Second, 3*time.Second)
+		err = resource.Retry(client.GetRetryTimeout(d.Timeout(schema.TimeoutCreate)), func() *resource.RetryError {
+			response, err = client.RpcPost(api.Product, api.Version, action, nil, request, false)
+			if err != nil {
+				if IsExpectedErrors(err, []string{"ServiceBusy", "FlowControlError", "Throttling.User"}) || NeedRetry(err) {
+					wait()
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		})
+		addDebug(action, response, request)
+
+		if err != nil {
+			if len(taskIds) > 0 {
+				err = fmt.Errorf("%s, after the tasks %s were submitted", err, strings.Join(taskIds, ","))
+			}
+			return WrapErrorf(err, DefaultErrorMsg, api.ResourceName, action, AlibabaCloudSdkGoERROR)
+		}
+
+		v, err := jsonpath.Get(taskIdPath, response)
+		if err != nil {
+			return WrapErrorf(err, IdMsg, api.ResourceName)
+		}
+		for _, taskId := range strings.Split(fmt.Sprint(v), ",") {
+			if taskId = strings.TrimSpace(taskId); taskId != "" {
+				taskIds = append(taskIds, taskId)
+			}
+		}
+	}
+
+	d.SetId(strings.Join(taskIds, ","))
+	d.Set("task_ids", taskIds)
+
+	var tasks []interface{}
+	stateConf := BuildTracedStateConf(client, []string{"Pending", "Refreshing"}, []string{"Complete"}, d.Timeout(schema.TimeoutCreate), 5*time.Second, func() (interface{}, string, error) {
+		object, err := describeCacheRefreshTasks(client, api, taskIds)
+		if err != nil {
+			return nil, "", WrapError(err)
+		}
+		tasks = object
+		return object, cacheRefreshTasksStatus(object, len(taskIds)), nil
+	})
+	if _, err := stateConf.WaitForState(); err != nil {
+		return WrapErrorf(err, IdMsg, d.Id())
+	}
+
+	failed := cacheRefreshFailedPaths(tasks)
+	d.Set("failed_paths", failed)
+	if len(failed) > 0 {
+		return WrapErrorf(fmt.Errorf("%d of the %d paths failed:\n  - %s", len(failed), len(paths), strings.Join(failed, "\n  - ")), IdMsg, d.Id())
+	}
+
+	return nil
+}
+
+func describeCacheRefreshQuota(client *connectivity.AliyunClient, api cacheRefreshApi) (object map[string]interface{}, err error) {
+	var response map[string]interface{}
+	action := api.QuotaAction
+	request := map[string]interface{}{}
+
+	wait := incrementalWait(3*time.Second, 3*time.Second)
+	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+		response, err = client.RpcPost(api.Product, api.Version, action, nil, request, true)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug(action, response, request)
+
+	if err != nil {
+		return object, WrapErrorf(err, DefaultErrorMsg, api.ResourceName, action, AlibabaCloudSdkGoERROR)
+	}
+	return response, nil
+}
+
+// describeCacheRefreshTasks returns the tasks with the given IDs, described a few at a time.
+func describeCacheRefreshTasks(client *connectivity.AliyunClient, api cacheRefreshApi, taskIds []string) (object []interface{}, err error) {
+	action := api.DescribeAction
+	object = make([]interface{}, 0)
+	for _, batch := range cacheRefreshBatches(taskIds, cacheRefreshDescribeSize) {
+		var response map[string]interface{}
+		request := map[string]interface{}{
+			"TaskId": strings.Join(batch, ","),
+		}
+
+		wait := incrementalWait(3*time.Second, 3*time.Second)
+		err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+			response, err = client.RpcPost(api.Product, api.Version, action, nil, request, true)
+			if err != nil {
+				if NeedRetry(err) {
+					wait()
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		})
+		addDebug(action, response, request)
+
+		if err != nil {
+			return object, WrapErrorf(err, DefaultErrorMsg, request["TaskId"], action, AlibabaCloudSdkGoERROR)
+		}
+
+		v, err := jsonpath.Get("$.Tasks", response)
+		if err != nil {
+			return object, WrapErrorf(err, FailedGetAttributeMsg, request["TaskId"], "$.Tasks", response)
+		}
+		switch tasks := v.(type) {
+		case []interface{}:
+			object = append(object, tasks...)
+		case map[string]interface{}:
+			for _, list := range tasks {
+				if list, ok := list.([]interface{}); ok {
+					object = append(object, list...)
+				}
+			}
+		}
+	}
+	return object, nil
+}
+
+// cacheRefreshQuotaLeft returns the number of paths that can still be submitted today, or -1 when the quota does not
+// limit them. The quota is only reset the next day, so a quota that is used up fails at once instead of being waited
+// for.
+func cacheRefreshQuotaLeft(client *connectivity.AliyunClient, api cacheRefreshApi, taskType, objectType string) (remain int, err error) {
+	quota, err := describeCacheRefreshQuota(client, api)
+	if err != nil {
+		return 0, err
+	}
+	remain = cacheRefreshQuotaRemain(quota, taskType, objectType)
+	if remain == 0 {
+		return 0, fmt.Errorf("the daily quota to %s %s paths is used up, apply again once it is reset the next day", strings.ToLower(taskType), strings.ToLower(objectType))
+	}
+	return remain, nil
+}
+
+// cacheRefreshQuotaRemain returns the quota left for the day to submit paths of a type, or -1 when the quota does
+// not report it.
+func cacheRefreshQuotaRemain(quota map[string]interface{}, taskType, objectType string) int {
+	key := "PreloadRemain"
+	if taskType != "Preload" {
+		key = map[string]string{
+			"File":         "UrlRemain",
+			"Directory":    "DirRemain",
+			"Regex":        "RegexRemain",
+			"IgnoreParams": "IgnoreParamsRemain",
+		}[objectType]
+	}
+	v, ok := quota[key]
+	if !ok || v == nil {
+		return -1
+	}
+	remain, err := strconv.Atoi(fmt.Sprint(v))
+	if err != nil {
+		return -1
+	}
+	if remain < 0 {
+		return 0
+	}
+	return remain
+}
+
+// cacheRefreshBatches splits items into batches of at most size items.
+func cacheRefreshBatches(items []string, size int) [][]string {
+	batches := make([][]string, 0, (len(items)+size-1)/size)
+	for start := 0; start < len(items); start += size {
+		end := start + size
+		if end > len(items) {
+			end = len(items)
+		}
+		batches = append(batches, items[start:end])
+	}
+	return batches
+}
+
+// cacheRefreshTasksStatus returns "Complete" once every task is complete or failed.
+func cacheRefreshTasksStatus(tasks []interface{}, count int) string {
+	if len(tasks) < count {
+		return "Pending"
+	}
+	for _, v := range tasks {
+		task, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch fmt.Sprint(task["Status"]) {
+		case "Complete", "Failed":
+		default:
+			return "Refreshing"
+		}
+	}
+	return "Complete"
+}
+
+// cacheRefreshFailedPaths returns the paths of the failed tasks, with the reason of the failure when there is one.
+func cacheRefreshFailedPaths(tasks []interface{}) []string {
+	failed := make([]string, 0)
+	for _, v := range tasks {
+		task, ok := v.(map[string]interface{})
+		if !ok || fmt.Sprint(task["Status"]) != "Failed" {
+			continue
+		}
+		path := fmt.Sprint(task["ObjectPath"])
+		if description, ok := task["Description"]; ok && description != nil && fmt.Sprint(description) != "" {
+			path = fmt.Sprintf("%s (%s)", path, description)
+		}
+		failed = append(failed, path)
+	}
+	sort.Strings(failed)
+	return failed
+}
@@ -0,0 +1,71 @@
+package alicloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitCdnCacheRefresh(t *testing.T) {
+	paths := make([]string, 0)
+	for i := 0; i < 205; i++ {
+		paths = append(paths, fmt.Sprintf("https://example.com/assets/%d.js", i))
+	}
+	batches := cacheRefreshBatches(paths, cacheRefreshBatchSize)
+	assert.Len(t, batches, 3)
+	assert.Len(t, batches[0], 100)
+	assert.Len(t, batches[2], 5)
+	assert.Equal(t, "https://example.com/assets/204.js", batches[2][4])
+	assert.Len(t, cacheRefreshBatches(nil, cacheRefreshDescribeSize), 0)
+
+	quota := map[string]interface{}{"UrlRemain": "200", "DirRemain": "0", "PreloadRemain": json.Number("1000"), "RegexRemain": "-1"}
+	assert.Equal(t, 200, cacheRefreshQuotaRemain(quota, "Refresh", "File"))
+	assert.Equal(t, 0, cacheRefreshQuotaRemain(quota, "Refresh", "Directory"))
+	assert.Equal(t, 0, cacheRefreshQuotaRemain(quota, "Refresh", "Regex"))
+	assert.Equal(t, -1, cacheRefreshQuotaRemain(quota, "Refresh", "IgnoreParams"))
+	assert.Equal(t, 1000, cacheRefreshQuotaRemain(quota, "Preload", "File"))
+
+	tasks := []interface{}{
+		map[string]interface{}{"TaskId": "1", "ObjectPath": "https://example.com/b.js", "Status": "Complete"},
+		map[string]interface{}{"TaskId": "2", "ObjectPath": "https://example.com/a.js", "Status": "Refreshing"},
+	}
+	assert.Equal(t, "Pending", cacheRefreshTasksStatus(tasks, 3))
+	assert.Equal(t, "Refreshing", cacheRefreshTasksStatus(tasks, 2))
+	tasks[1].(map[string]interface{})["Status"] = "Failed"
+	tasks[1].(map[string]interface{})["Description"] = "InternalError"
+	tasks = append(tasks, map[string]interface{}{"TaskId": "3", "ObjectPath": "https://example.com/c.js", "Status": "Failed"})
+	assert.Equal(t, "Complete", cacheRefreshTasksStatus(tasks, 3))
+	assert.Equal(t, []string{"https://example.com/a.js (InternalError)", "https://example.com/c.js"}, cacheRefreshFailedPaths(tasks))
+}
+
+func TestUnitEsaCacheRefresh(t *testing.T) {
+	taskIds := []string{"1597854579687203", "1597854579687204"}
+	var response map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"RequestId":"EDBD3EB3-97DA-5465-AEF5-8DCA5DC5E395","TotalCount":3,"PageNumber":1,"PageSize":500,"Tasks":[
+		{"TaskId":"1597854579687202","Type":"file","Content":"https://example.com/old.js","Status":"failed","ErrorCode":"InternalError","CreateTime":"2024-03-05T05:30:22Z"},
+		{"TaskId":"1597854579687203","Type":"file","Content":"https://example.com/a.js","Status":"success","ErrorCode":"","CreateTime":"2024-03-05T05:31:22Z"},
+		{"TaskId":"1597854579687203","Type":"file","Content":"https://example.com/b.js","Status":"running","ErrorCode":"","CreateTime":"2024-03-05T05:31:22Z"}]}`), &response))
+	tasks := esaCacheRefreshTasks(response["Tasks"].([]interface{}), taskIds)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, "Complete", tasks[0].(map[string]interface{})["Status"])
+	assert.Equal(t, "Refreshing", tasks[1].(map[string]interface{})["Status"])
+	assert.Equal(t, "Pending", esaCacheRefreshTasksStatus(tasks, taskIds))
+
+	assert.Nil(t, json.Unmarshal([]byte(`{"Tasks":[
+		{"TaskId":"1597854579687204","Type":"file","Content":"https://example.com/c.js","Status":"failed","ErrorCode":"InternalError","CreateTime":"2024-03-05T05:31:22Z"}]}`), &response))
+	tasks = append(tasks, esaCacheRefreshTasks(response["Tasks"].([]interface{}), taskIds)...)
+	assert.Equal(t, "Refreshing", esaCacheRefreshTasksStatus(tasks, taskIds))
+	tasks[1] = esaCacheRefreshTasks([]interface{}{
+		map[string]interface{}{"TaskId": "1597854579687203", "Content": "https://example.com/b.js", "Status": "success", "ErrorCode": ""},
+	}, taskIds)[0]
+	assert.Equal(t, "Complete", esaCacheRefreshTasksStatus(tasks, taskIds))
+	assert.Equal(t, []string{"https://example.com/c.js (InternalError)"}, cacheRefreshFailedPaths(tasks))
+
+	// a status that is not known is waited for
+	tasks = esaCacheRefreshTasks([]interface{}{
+		map[string]interface{}{"TaskId": "1597854579687203", "Content": "https://example.com/a.js", "Status": "queued"},
+	}, taskIds[:1])
+	assert.Equal(t, "Refreshing", esaCacheRefreshTasksStatus(tasks, taskIds[:1]))
+}
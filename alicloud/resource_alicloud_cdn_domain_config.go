@@ -25,6 +25,7 @@ func resourceAliCloudCdnDomainConfig() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: cdnConfigFunctionCustomizeDiff("cdn"),
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(15 * time.Minute),
 			Update: schema.DefaultTimeout(5 * time.Minute),
@@ -47,23 +48,7 @@ func resourceAliCloudCdnDomainConfig() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
-			"function_args": {
-				Type:     schema.TypeSet,
-				Set:      expirationCdnDomainConfigHash,
-				Required: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"arg_name": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"arg_value": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-					},
-				},
-			},
+			"function_args": cdnConfigFunctionArgsSchema(expirationCdnDomainConfigHash),
 			"config_id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -84,15 +69,7 @@ func resourceAliCloudCdnDomainConfigCreate(d *schema.ResourceData, meta interfac
 	action := "BatchSetCdnDomainConfig"
 
 	config := make([]map[string]interface{}, 1)
-	functionArgs := d.Get("function_args").(*schema.Set).List()
-	args := make([]map[string]interface{}, len(functionArgs))
-	for key, value := range functionArgs {
-		arg := value.(map[string]interface{})
-		args[key] = map[string]interface{}{
-			"argName":  arg["arg_name"],
-			"argValue": arg["arg_value"],
-		}
-	}
+	args := cdnConfigFunctionArgs(d.Get("function_args").(*schema.Set).List())
 	config[0] = map[string]interface{}{
 		"functionArgs": args,
 		"functionName": d.Get("function_name").(string),
@@ -173,15 +150,7 @@ func resourceAliCloudCdnDomainConfigUpdate(d *schema.ResourceData, meta interfac
 	if d.HasChange("function_args") || d.HasChange("parent_id") {
 		update = true
 		config := make([]map[string]interface{}, 1)
-		functionArgs := d.Get("function_args").(*schema.Set).List()
-		args := make([]map[string]interface{}, len(functionArgs))
-		for key, value := range functionArgs {
-			arg := value.(map[string]interface{})
-			args[key] = map[string]interface{}{
-				"argName":  arg["arg_name"],
-				"argValue": arg["arg_value"],
-			}
-		}
+		args := cdnConfigFunctionArgs(d.Get("function_args").(*schema.Set).List())
 
 		config[0] = map[string]interface{}{
 			"functionArgs": args,
@@ -257,31 +226,6 @@ func resourceAliCloudCdnDomainConfigRead(d *schema.ResourceData, meta interface{
 	}
 
 	val := v.(map[string]interface{})
-	args := make([]interface{}, 0)
-	if v, ok := val["FunctionArgs"].(map[string]interface{}); ok {
-		if tmp, exist := v["FunctionArg"].([]interface{}); exist {
-			args = tmp
-		}
-	}
-
-	var funArgs []map[string]string
-	ignoreFunctionArg := []string{"aliyun_id", "scheme_origin_port", "dsl", "session_timeout",
-		"oss_pri_buckets", "private_oss_tbl", "private_oss_ram_unauthorized", "cert", "cert_name",
-		"consistent_hash", "cert_type", "https", "dkey", "pkey"}
-
-	for _, k := range args {
-
-		arg := k.(map[string]interface{})
-		// This two function args is extra, filter them to pass test check.
-		if InArray(fmt.Sprint(arg["ArgName"]), ignoreFunctionArg) {
-			continue
-		}
-
-		funArgs = append(funArgs, map[string]string{
-			"arg_name":  arg["ArgName"].(string),
-			"arg_value": arg["ArgValue"].(string),
-		})
-	}
 
 	parts := strings.Split(d.Id(), ":")
 	d.Set("domain_name", parts[0])
@@ -292,7 +236,7 @@ func resourceAliCloudCdnDomainConfigRead(d *schema.ResourceData, meta interface{
 	}
 
 	d.Set("status", val["Status"])
-	d.Set("function_args", funArgs)
+	d.Set("function_args", cdnConfigFunctionArgsFromApi("cdn", val))
 	d.Set("parent_id", val["ParentId"])
 
 	return nil
@@ -354,7 +298,7 @@ func expirationCdnDomainConfigHash(v interface{}) int {
 		buf.WriteString(fmt.Sprintf("%s-", v.(string)))
 	}
 	if v, ok := m["arg_value"]; ok {
-		buf.WriteString(fmt.Sprintf("%s-", v.(string)))
+		buf.WriteString(fmt.Sprintf("%s-", normalizeCdnConfigArgValue(fmt.Sprint(m["arg_name"]), v.(string))))
 	}
 	return helper.Hashcode(buf.String())
 }
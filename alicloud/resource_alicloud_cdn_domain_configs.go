@@ -0,0 +1,420 @@
+package alicloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/helper"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceAliCloudCdnDomainConfigs() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAliCloudCdnDomainConfigsCreate,
+		Read:   resourceAliCloudCdnDomainConfigsRead,
+		Update: resourceAliCloudCdnDomainConfigsUpdate,
+		Delete: resourceAliCloudCdnDomainConfigsDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: resourceAliCloudCdnDomainConfigsCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+			Update: schema.DefaultTimeout(15 * time.Minute),
+			Delete: schema.DefaultTimeout(15 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(5, 67),
+			},
+			"config": {
+				Type:     schema.TypeSet,
+				Set:      cdnDomainConfigsHash,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"function_name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"parent_id": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"function_args": cdnConfigFunctionArgsSchema(expirationCdnDomainConfigHash),
+						"config_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceAliCloudCdnDomainConfigsCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	cdnService := &CdnService{client: client}
+
+	domainName := d.Get("domain_name").(string)
+	configs := d.Get("config").(*schema.Set).List()
+
+	// The resource is authoritative from the start: the config functions already on the domain are updated in
+	// place when they are configured, and deleted otherwise.
+	existing, err := cdnService.DescribeCdnDomainConfigs(domainName)
+	if err != nil {
+		return WrapError(err)
+	}
+	configIds, deleted := cdnDomainConfigsAdopt(existing, configs)
+	if len(deleted) > 0 {
+		if err := deleteCdnDomainConfigs(client, domainName, deleted, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	}
+	if err := batchSetCdnDomainConfigs(client, domainName, configs, configIds, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+
+	d.SetId(domainName)
+
+	stateConf := BuildTracedStateConf(client, []string{}, []string{"success"}, d.Timeout(schema.TimeoutCreate), 30*time.Second, cdnService.CdnDomainConfigsStateRefreshFunc(d.Id(), []string{"failed"}))
+	if _, err := stateConf.WaitForState(); err != nil {
+		return WrapErrorf(err, IdMsg, d.Id())
+	}
+
+	err = cdnService.WaitForCdnDomain(d.Id(), Online, DefaultTimeoutMedium)
+	if err != nil {
+		return WrapError(err)
+	}
+
+	return resourceAliCloudCdnDomainConfigsRead(d, meta)
+}
+
+func resourceAliCloudCdnDomainConfigsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	cdnService := &CdnService{client: client}
+
+	object, err := cdnService.DescribeCdnDomainConfigs(d.Id())
+	if err != nil {
+		if !d.IsNewResource() && NotFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return WrapError(err)
+	}
+
+	// Rule conditions that a configured function applies under are left out, as Create and Update leave them
+	// alone.
+	configs := flattenCdnDomainConfigs(object, d.Get("config").(*schema.Set).List())
+
+	d.Set("domain_name", d.Id())
+	if err := d.Set("config", configs); err != nil {
+		return WrapError(err)
+	}
+
+	return nil
+}
+
+func resourceAliCloudCdnDomainConfigsUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	cdnService := &CdnService{client: client}
+
+	if d.HasChange("config") {
+		o, n := d.GetChange("config")
+		removed := o.(*schema.Set).Difference(n.(*schema.Set)).List()
+		added := n.(*schema.Set).Difference(o.(*schema.Set)).List()
+
+		// A config function whose arguments changed is updated in place, the others removed from the
+		// configuration are deleted.
+		configIds := make(map[int]string)
+		deleted := make([]string, 0)
+		parentIds := cdnDomainConfigsParentIds(n.(*schema.Set).List())
+		for _, r := range removed {
+			config := r.(map[string]interface{})
+			if fmt.Sprint(config["config_id"]) == "" || parentIds[fmt.Sprint(config["config_id"])] {
+				continue
+			}
+			replaced := false
+			for i, a := range added {
+				if _, ok := configIds[i]; ok {
+					continue
+				}
+				if cdnDomainConfigsSameFunction(config, a.(map[string]interface{})) {
+					configIds[i] = fmt.Sprint(config["config_id"])
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				deleted = append(deleted, fmt.Sprint(config["config_id"]))
+			}
+		}
+
+		if len(deleted) > 0 {
+			if err := deleteCdnDomainConfigs(client, d.Id(), deleted, d.Timeout(schema.TimeoutUpdate)); err != nil {
+				return err
+			}
+		}
+
+		if len(added) > 0 {
+			if err := batchSetCdnDomainConfigs(client, d.Id(), added, configIds, d.Timeout(schema.TimeoutUpdate)); err != nil {
+				return err
+			}
+		}
+
+		stateConf := BuildTracedStateConf(client, []string{}, []string{"success"}, d.Timeout(schema.TimeoutUpdate), 30*time.Second, cdnService.CdnDomainConfigsStateRefreshFunc(d.Id(), []string{"failed"}))
+		if _, err := stateConf.WaitForState(); err != nil {
+			return WrapErrorf(err, IdMsg, d.Id())
+		}
+
+		err := cdnService.WaitForCdnDomain(d.Id(), Online, DefaultTimeoutMedium)
+		if err != nil {
+			return WrapError(err)
+		}
+	}
+
+	return resourceAliCloudCdnDomainConfigsRead(d, meta)
+}
+
+func resourceAliCloudCdnDomainConfigsDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+
+	configIds := make([]string, 0)
+	for _, v := range d.Get("config").(*schema.Set).List() {
+		if configId := fmt.Sprint(v.(map[string]interface{})["config_id"]); configId != "" {
+			configIds = append(configIds, configId)
+		}
+	}
+	if len(configIds) == 0 {
+		return nil
+	}
+
+	err := deleteCdnDomainConfigs(client, d.Id(), configIds, d.Timeout(schema.TimeoutDelete))
+	if err != nil && NotFoundError(err) {
+		return nil
+	}
+	return err
+}
+
+func resourceAliCloudCdnDomainConfigsCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("config") {
+		return nil
+	}
+	for _, v := range d.Get("config").(*schema.Set).List() {
+		config := v.(map[string]interface{})
+		args := make([]interface{}, 0)
+		if functionArgs, ok := config["function_args"].(*schema.Set); ok {
+			args = functionArgs.List()
+		}
+		if err := validateCdnConfigFunction("cdn", fmt.Sprint(config["function_name"]), args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchSetCdnDomainConfigs sets config functions of a domain, updating in place the ones with a config ID in
+// configIds, keyed by their index in configs.
+func batchSetCdnDomainConfigs(client *connectivity.AliyunClient, domainName string, configs []interface{}, configIds map[int]string, timeout time.Duration) error {
+	var response map[string]interface{}
+	var err error
+	action := "BatchSetCdnDomainConfig"
+
+	functions := make([]map[string]interface{}, len(configs))
+	for i, v := range configs {
+		config := v.(map[string]interface{})
+		functions[i] = map[string]interface{}{
+			"functionArgs": cdnConfigFunctionArgs(config["function_args"].(*schema.Set).List()),
+			"functionName": config["function_name"],
+		}
+		if parentId := fmt.Sprint(config["parent_id"]); parentId != "" {
+			functions[i]["parentId"] = parentId
+		}
+		if configId, ok := configIds[i]; ok {
+			functions[i]["configId"] = configId
+		}
+	}
+	bytconfig, _ := json.Marshal(functions)
+
+	request := map[string]interface{}{
+		"RegionId":    client.RegionId,
+		"DomainNames": domainName,
+		"Functions":   string(bytconfig),
+	}
+
+	wait := incrementalWait(3*time.Second, 3*time.Second)
+	err = resource.Retry(client.GetRetryTimeout(timeout), func() *resource.RetryError {
+		response, err = client.RpcPost("Cdn", "2018-05-10", action, nil, request, true)
+		if err != nil {
+			if IsExpectedErrors(err, []string{"ServiceBusy"}) || NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug(action, response, request)
+
+	if err != nil {
+		return WrapErrorf(err, DefaultErrorMsg, "alicloud_cdn_domain_configs", action, AlibabaCloudSdkGoERROR)
+	}
+	return nil
+}
+
+func deleteCdnDomainConfigs(client *connectivity.AliyunClient, domainName string, configIds []string, timeout time.Duration) error {
+	var response map[string]interface{}
+	var err error
+	action := "DeleteSpecificConfig"
+
+	request := map[string]interface{}{
+		"RegionId":   client.RegionId,
+		"DomainName": domainName,
+		"ConfigId":   strings.Join(configIds, ","),
+	}
+
+	wait := incrementalWait(3*time.Second, 3*time.Second)
+	err = resource.Retry(client.GetRetryTimeout(timeout), func() *resource.RetryError {
+		response, err = client.RpcPost("Cdn", "2018-05-10", action, nil, request, true)
+		if err != nil {
+			if IsExpectedErrors(err, []string{"ServiceBusy"}) || NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug(action, response, request)
+
+	if err != nil {
+		if IsExpectedErrors(err, []string{"InvalidDomain.NotFound"}) {
+			return WrapErrorf(err, NotFoundMsg, AlibabaCloudSdkGoERROR)
+		}
+		return WrapErrorf(err, DefaultErrorMsg, domainName, action, AlibabaCloudSdkGoERROR)
+	}
+	return nil
+}
+
+// cdnDomainConfigsAdopt matches the config functions a domain already has, as returned by DescribeCdnDomainConfigs,
+// with the configured ones. It returns the config IDs to update in place, keyed by the index in configs, and the
+// config IDs of the functions that are not configured. Rule conditions that a configured function applies under are
+// kept.
+func cdnDomainConfigsAdopt(existing []interface{}, configs []interface{}) (configIds map[int]string, deleted []string) {
+	configIds = make(map[int]string)
+	deleted = make([]string, 0)
+	parentIds := cdnDomainConfigsParentIds(configs)
+	for _, v := range existing {
+		object, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		current := map[string]interface{}{
+			"function_name": fmt.Sprint(object["FunctionName"]),
+			"parent_id":     "",
+		}
+		if parentId, ok := object["ParentId"]; ok && parentId != nil {
+			current["parent_id"] = fmt.Sprint(parentId)
+		}
+		adopted := false
+		for i, c := range configs {
+			if _, ok := configIds[i]; ok {
+				continue
+			}
+			if cdnDomainConfigsSameFunction(current, c.(map[string]interface{})) {
+				configIds[i] = fmt.Sprint(object["ConfigId"])
+				adopted = true
+				break
+			}
+		}
+		if !adopted && !parentIds[fmt.Sprint(object["ConfigId"])] {
+			deleted = append(deleted, fmt.Sprint(object["ConfigId"]))
+		}
+	}
+	return configIds, deleted
+}
+
+// cdnDomainConfigsParentIds returns the config IDs of the rule conditions that the configured functions apply under.
+func cdnDomainConfigsParentIds(configs []interface{}) map[string]bool {
+	parentIds := make(map[string]bool)
+	for _, c := range configs {
+		if parentId, _ := c.(map[string]interface{})["parent_id"].(string); parentId != "" {
+			parentIds[parentId] = true
+		}
+	}
+	return parentIds
+}
+
+// flattenCdnDomainConfigs turns the config functions of a domain, as returned by DescribeCdnDomainConfigs, into
+// config blocks, leaving out the rule conditions that the configured functions apply under.
+func flattenCdnDomainConfigs(object []interface{}, configs []interface{}) []map[string]interface{} {
+	parentIds := cdnDomainConfigsParentIds(configs)
+	result := make([]map[string]interface{}, 0, len(object))
+	for _, v := range object {
+		config, ok := v.(map[string]interface{})
+		if !ok || parentIds[fmt.Sprint(config["ConfigId"])] {
+			continue
+		}
+		parentId := ""
+		if v, ok := config["ParentId"]; ok && v != nil {
+			parentId = fmt.Sprint(v)
+		}
+		result = append(result, map[string]interface{}{
+			"function_name": fmt.Sprint(config["FunctionName"]),
+			"parent_id":     parentId,
+			"function_args": cdnConfigFunctionArgsFromApi("cdn", config),
+			"config_id":     fmt.Sprint(config["ConfigId"]),
+			"status":        fmt.Sprint(config["Status"]),
+		})
+	}
+	return result
+}
+
+func cdnDomainConfigsSameFunction(a, b map[string]interface{}) bool {
+	return fmt.Sprint(a["function_name"]) == fmt.Sprint(b["function_name"]) && fmt.Sprint(a["parent_id"]) == fmt.Sprint(b["parent_id"])
+}
+
+// cdnDomainConfigsHash hashes a config function by its name, parent and normalized arguments, leaving out the
+// attributes computed by the API.
+func cdnDomainConfigsHash(v interface{}) int {
+	var buf bytes.Buffer
+	m := v.(map[string]interface{})
+	buf.WriteString(fmt.Sprintf("%s-", m["function_name"]))
+	parentId, _ := m["parent_id"].(string)
+	buf.WriteString(fmt.Sprintf("%s-", parentId))
+	args := make([]int, 0)
+	switch functionArgs := m["function_args"].(type) {
+	case *schema.Set:
+		for _, arg := range functionArgs.List() {
+			args = append(args, expirationCdnDomainConfigHash(arg))
+		}
+	case []interface{}:
+		for _, arg := range functionArgs {
+			args = append(args, expirationCdnDomainConfigHash(arg))
+		}
+	case []map[string]interface{}:
+		for _, arg := range functionArgs {
+			args = append(args, expirationCdnDomainConfigHash(arg))
+		}
+	}
+	sort.Ints(args)
+	for _, arg := range args {
+		buf.WriteString(fmt.Sprintf("%d-", arg))
+	}
+	return helper.Hashcode(buf.String())
+}
@@ -0,0 +1,154 @@
+package alicloud
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccAliCloudCDNDomainConfigs_basic0(t *testing.T) {
+	var v []interface{}
+
+	resourceId := "alicloud_cdn_domain_configs.default"
+	ra := resourceAttrInit(resourceId, AliCloudCDNDomainConfigsMap0)
+
+	serviceFunc := func() interface{} {
+		return &CdnService{testAccProvider.Meta().(*connectivity.AliyunClient)}
+	}
+	rc := resourceCheckInit(resourceId, &v, serviceFunc)
+
+	rac := resourceAttrCheckInit(rc, ra)
+
+	testAccCheck := rac.resourceAttrMapUpdateSet()
+	rand := acctest.RandIntRange(1000000, 9999999)
+	name := fmt.Sprintf("tf-testacc%s%d.alicloud-provider.cn", defaultRegionToTest, rand)
+	testAccConfig := resourceTestAccConfigFunc(resourceId, name, AliCloudCDNDomainConfigBasicDependence0)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		// module name
+		IDRefreshName: resourceId,
+		Providers:     testAccProviders,
+		CheckDestroy:  rac.checkResourceDestroy(),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConfig(map[string]interface{}{
+					"domain_name": "${alicloud_cdn_domain_new.default.domain_name}",
+					"config": []map[string]interface{}{
+						{
+							"function_name": "filetype_based_ttl_set",
+							"function_args": []map[string]interface{}{
+								{
+									"arg_name":  "ttl",
+									"arg_value": "300",
+								},
+								{
+									"arg_name":  "file_type",
+									"arg_value": "jpg",
+								},
+								{
+									"arg_name":  "weight",
+									"arg_value": "1",
+								},
+							},
+						},
+						{
+							"function_name": "https_force",
+							"function_args": []map[string]interface{}{
+								{
+									"arg_name":  "enable",
+									"arg_value": "on",
+								},
+							},
+						},
+					},
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheck(map[string]string{
+						"domain_name": name,
+						"config.#":    "2",
+					}),
+				),
+			},
+			{
+				Config: testAccConfig(map[string]interface{}{
+					"config": []map[string]interface{}{
+						{
+							"function_name": "filetype_based_ttl_set",
+							"function_args": []map[string]interface{}{
+								{
+									"arg_name":  "ttl",
+									"arg_value": "200",
+								},
+								{
+									"arg_name":  "file_type",
+									"arg_value": "jpg",
+								},
+								{
+									"arg_name":  "weight",
+									"arg_value": "1",
+								},
+							},
+						},
+						{
+							"function_name": "gzip",
+							"function_args": []map[string]interface{}{
+								{
+									"arg_name":  "enable",
+									"arg_value": "ON",
+								},
+							},
+						},
+					},
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheck(map[string]string{
+						"config.#": "2",
+					}),
+				),
+			},
+			{
+				ResourceName:      resourceId,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+var AliCloudCDNDomainConfigsMap0 = map[string]string{}
+
+func TestUnitAliCloudCDNDomainConfigsAdopt(t *testing.T) {
+	existing := []interface{}{
+		map[string]interface{}{"FunctionName": "condition", "ConfigId": "1"},
+		map[string]interface{}{"FunctionName": "referer_white_list_set", "ConfigId": "2", "ParentId": "1"},
+		map[string]interface{}{"FunctionName": "filetype_based_ttl_set", "ConfigId": "3"},
+		map[string]interface{}{"FunctionName": "filetype_based_ttl_set", "ConfigId": "4"},
+		map[string]interface{}{"FunctionName": "https_force", "ConfigId": "5"},
+	}
+	configs := []interface{}{
+		map[string]interface{}{"function_name": "filetype_based_ttl_set", "parent_id": ""},
+		map[string]interface{}{"function_name": "referer_white_list_set", "parent_id": "1"},
+		map[string]interface{}{"function_name": "ip_allow_list_set", "parent_id": ""},
+	}
+	configIds, deleted := cdnDomainConfigsAdopt(existing, configs)
+	assert.Equal(t, map[int]string{0: "3", 1: "2"}, configIds)
+	assert.Equal(t, []string{"4", "5"}, deleted)
+
+	configIds, deleted = cdnDomainConfigsAdopt(nil, configs)
+	assert.Empty(t, configIds)
+	assert.Empty(t, deleted)
+
+	// the rule condition a configured function applies under is left out of the state as well
+	flattened := flattenCdnDomainConfigs(existing, configs)
+	assert.Len(t, flattened, 4)
+	for _, config := range flattened {
+		assert.NotEqual(t, "1", config["config_id"])
+	}
+	assert.Len(t, flattenCdnDomainConfigs(existing, nil), 5)
+}
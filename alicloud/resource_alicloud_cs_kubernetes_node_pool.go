@@ -754,9 +754,21 @@ func resourceAliCloudAckNodepool() *schema.Resource {
 							Type:     schema.TypeInt,
 							Optional: true,
 						},
+						"pause_on_failure": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
 					},
 				},
 			},
+			"last_task_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"last_task_result": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"runtime_name": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -3131,10 +3143,8 @@ func resourceAliCloudAckNodepoolUpdate(d *schema.ResourceData, meta interface{})
 		if err != nil {
 			return WrapErrorf(err, DefaultErrorMsg, d.Id(), action, AlibabaCloudSdkGoERROR)
 		}
-		ackServiceV2 := AckServiceV2{client}
-		stateConf := BuildStateConf([]string{}, []string{"success"}, d.Timeout(schema.TimeoutUpdate), 5*time.Second, ackServiceV2.DescribeAsyncAckNodepoolStateRefreshFunc(d, response, "$.state", []string{"fail", "failed"}))
-		if jobDetail, err := stateConf.WaitForState(); err != nil {
-			return WrapErrorf(err, IdMsg, d.Id(), jobDetail)
+		if err := waitAckNodepoolRollingTask(d, client, response); err != nil {
+			return err
 		}
 	}
 	update = false
@@ -3356,10 +3366,8 @@ func resourceAliCloudAckNodepoolUpdate(d *schema.ResourceData, meta interface{})
 		if err != nil {
 			return WrapErrorf(err, DefaultErrorMsg, d.Id(), action, AlibabaCloudSdkGoERROR)
 		}
-		ackServiceV2 := AckServiceV2{client}
-		stateConf := BuildStateConf([]string{}, []string{"success"}, d.Timeout(schema.TimeoutUpdate), 5*time.Second, ackServiceV2.DescribeAsyncAckNodepoolStateRefreshFunc(d, response, "$.state", []string{"fail", "failed"}))
-		if jobDetail, err := stateConf.WaitForState(); err != nil {
-			return WrapErrorf(err, IdMsg, d.Id(), jobDetail)
+		if err := waitAckNodepoolRollingTask(d, client, response); err != nil {
+			return err
 		}
 	}
 	update = false
@@ -3463,10 +3471,8 @@ func resourceAliCloudAckNodepoolUpdate(d *schema.ResourceData, meta interface{})
 		if err != nil {
 			return WrapErrorf(err, DefaultErrorMsg, d.Id(), action, AlibabaCloudSdkGoERROR)
 		}
-		ackServiceV2 := AckServiceV2{client}
-		stateConf := BuildStateConf([]string{}, []string{"success"}, d.Timeout(schema.TimeoutUpdate), 5*time.Second, ackServiceV2.DescribeAsyncAckNodepoolStateRefreshFunc(d, response, "$.state", []string{"fail", "failed"}))
-		if jobDetail, err := stateConf.WaitForState(); err != nil {
-			return WrapErrorf(err, IdMsg, d.Id(), jobDetail)
+		if err := waitAckNodepoolRollingTask(d, client, response); err != nil {
+			return err
 		}
 	}
 
@@ -3497,6 +3503,41 @@ func resourceAliCloudAckNodepoolUpdate(d *schema.ResourceData, meta interface{})
 	return resourceAliCloudAckNodepoolRead(d, meta)
 }
 
+// ackNodepoolPauseOnFailurePollInterval is how often a rolling update with pause_on_failure is polled for failed nodes.
+const ackNodepoolPauseOnFailurePollInterval = 2 * time.Second
+
+// waitAckNodepoolRollingTask waits for the task started by an update and records its ID and result, which are kept
+// in the state even when the task fails.
+func waitAckNodepoolRollingTask(d *schema.ResourceData, client *connectivity.AliyunClient, response map[string]interface{}) error {
+	ackServiceV2 := AckServiceV2{client}
+	pauseOnFailure := false
+	if v, err := jsonpath.Get("$[0].pause_on_failure", d.Get("rolling_policy")); err == nil {
+		pauseOnFailure, _ = v.(bool)
+	}
+	stateConf := BuildTracedStateConf(client, []string{}, []string{"success"}, d.Timeout(schema.TimeoutUpdate), 5*time.Second, ackServiceV2.DescribeAsyncAckNodepoolRollingStateRefreshFunc(d, response, []string{"fail", "failed"}, pauseOnFailure))
+	if pauseOnFailure {
+		// a failed node is only seen by polling the task, so poll often to pause it before more nodes are touched
+		stateConf.PollInterval = ackNodepoolPauseOnFailurePollInterval
+	}
+	jobDetail, err := stateConf.WaitForState()
+
+	d.Set("last_task_id", response["task_id"])
+	result := "success"
+	if err != nil {
+		result = "failed"
+		if object, ok := jobDetail.(map[string]interface{}); ok && object["state"] == "paused" {
+			result = "paused"
+		}
+	}
+	d.Set("last_task_result", result)
+	d.SetPartial("last_task_id")
+	d.SetPartial("last_task_result")
+	if err != nil {
+		return WrapErrorf(err, IdMsg, d.Id(), jobDetail)
+	}
+	return nil
+}
+
 func resourceAliCloudAckNodepoolDelete(d *schema.ResourceData, meta interface{}) error {
 
 	client := meta.(*connectivity.AliyunClient)
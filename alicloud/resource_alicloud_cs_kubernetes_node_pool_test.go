@@ -8597,3 +8597,36 @@ func TestUnitExpandContainerdConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestUnitAckNodepoolTaskProgress(t *testing.T) {
+	object := map[string]interface{}{
+		"task_id":       "T-1",
+		"state":         "running",
+		"current_stage": "UpgradeNodes",
+		"stages": []interface{}{
+			map[string]interface{}{"state": "success"},
+			map[string]interface{}{"state": "running"},
+			map[string]interface{}{"state": "pending"},
+		},
+		"task_result": []interface{}{
+			map[string]interface{}{"data": "i-1", "status": "success"},
+			map[string]interface{}{"data": "i-2", "status": "success"},
+			map[string]interface{}{"data": "i-3", "status": "failed"},
+			map[string]interface{}{"data": "i-4", "status": "failed", "message": "disk attach timeout"},
+		},
+		"events": []interface{}{
+			map[string]interface{}{"message": "node i-3 drain timeout"},
+			map[string]interface{}{"message": "node i-3 kubelet did not become ready"},
+		},
+		"error": map[string]interface{}{"code": "NodeUpgradeFailed", "message": "2 nodes failed"},
+	}
+	assert.Equal(t, "state running, batch 1/3 (UpgradeNodes), 2 nodes succeeded, 2 nodes failed", ackNodepoolTaskProgress(object))
+
+	failedNodes := ackNodepoolTaskFailedNodes(object)
+	assert.Equal(t, [][2]string{{"i-3", "node i-3 kubelet did not become ready"}, {"i-4", "disk attach timeout"}}, failedNodes)
+	assert.Equal(t, "i-3 (node i-3 kubelet did not become ready), i-4 (disk attach timeout); NodeUpgradeFailed: 2 nodes failed",
+		ackNodepoolTaskFailureDetail(object, failedNodes))
+
+	assert.Equal(t, "state success, 0 nodes succeeded, 0 nodes failed", ackNodepoolTaskProgress(map[string]interface{}{"state": "success"}))
+	assert.Equal(t, "none reported", ackNodepoolTaskFailureDetail(map[string]interface{}{}, nil))
+}
@@ -23,7 +23,7 @@ func resourceAliCloudDBInstance() *schema.Resource {
 		Update: resourceAliCloudDBInstanceUpdate,
 		Delete: resourceAliCloudDBInstanceDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByLookup("alicloud_db_instance", dbInstanceImportLookup),
 		},
 
 		Timeouts: &schema.ResourceTimeout{
@@ -0,0 +1,20 @@
+package alicloud
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+var dcdnCacheRefreshApi = cacheRefreshApi{
+	ResourceName:   "alicloud_dcdn_cache_refresh",
+	Product:        "dcdn",
+	Version:        "2018-01-15",
+	RefreshAction:  "RefreshDcdnObjectCaches",
+	PreloadAction:  "PreloadDcdnObjectCaches",
+	PreloadTaskId:  "PreloadTaskId",
+	QuotaAction:    "DescribeDcdnRefreshQuota",
+	DescribeAction: "DescribeDcdnRefreshTaskById",
+}
+
+func resourceAliCloudDcdnCacheRefresh() *schema.Resource {
+	return cacheRefreshResource(dcdnCacheRefreshApi)
+}
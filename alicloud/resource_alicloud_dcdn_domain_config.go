@@ -20,6 +20,7 @@ func resourceAliCloudDcdnDomainConfig() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: cdnConfigFunctionCustomizeDiff("dcdn"),
 		Schema: map[string]*schema.Schema{
 			"domain_name": {
 				Type:         schema.TypeString,
@@ -37,22 +38,7 @@ func resourceAliCloudDcdnDomainConfig() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
-			"function_args": {
-				Type:     schema.TypeSet,
-				Required: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"arg_name": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"arg_value": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-					},
-				},
-			},
+			"function_args": cdnConfigFunctionArgsSchema(expirationCdnDomainConfigHash),
 			"config_id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -76,15 +62,7 @@ func resourceAliCloudDcdnDomainConfigCreate(d *schema.ResourceData, meta interfa
 	request["DomainNames"] = d.Get("domain_name").(string)
 
 	config := make([]map[string]interface{}, 1)
-	functionArgs := d.Get("function_args").(*schema.Set).List()
-	args := make([]map[string]interface{}, len(functionArgs))
-	for key, value := range functionArgs {
-		arg := value.(map[string]interface{})
-		args[key] = map[string]interface{}{
-			"argName":  arg["arg_name"],
-			"argValue": arg["arg_value"],
-		}
-	}
+	args := cdnConfigFunctionArgs(d.Get("function_args").(*schema.Set).List())
 
 	config[0] = map[string]interface{}{
 		"functionArgs": args,
@@ -170,39 +148,7 @@ func resourceAliCloudDcdnDomainConfigRead(d *schema.ResourceData, meta interface
 	d.Set("status", object["Status"])
 	d.Set("parent_id", object["ParentId"])
 
-	ignoreFunctionArg := []string{"dsl", "disable_l2_log", "dynamic_batch_route", "dynamic_enable_cpool_chash",
-		"dynamic_mux_ecn_enable", "dynamic_mux_keepalive_enable", "dynamic_mux_share_enable", "dynamic_pk",
-		"dynamic_retry_status", "dynamic_route_cdn_v2", "dynamic_route_cpool", "dynamic_route_magic",
-		"dynamic_route_round_robin", "dynamic_route_session", "dynamic_route_tunnel", "dynamie_route_alllink_log",
-		"keepalive_sni", "l7tol4", "partition_back_to_origin", "dynamic_route_adapt_cache",
-		"dynamic_route_http_methods", "dynamic_mux_tls_enable", "dynamic_route_cdn_v2"}
-
-	if functionArgs, ok := object["FunctionArgs"]; ok {
-		if functionArgList, ok := functionArgs.(map[string]interface{})["FunctionArg"]; ok {
-			functionArgMaps := make([]map[string]interface{}, 0)
-			for _, functionArg := range functionArgList.([]interface{}) {
-				functionArgItem := functionArg.(map[string]interface{})
-				functionArgMap := map[string]interface{}{}
-
-				// This function args is extra, filter them to pass test check.
-				if InArray(fmt.Sprint(functionArgItem["ArgName"]), ignoreFunctionArg) {
-					continue
-				}
-
-				if argName, ok := functionArgItem["ArgName"]; ok {
-					functionArgMap["arg_name"] = argName
-				}
-
-				if argValue, ok := functionArgItem["ArgValue"]; ok {
-					functionArgMap["arg_value"] = argValue
-				}
-
-				functionArgMaps = append(functionArgMaps, functionArgMap)
-			}
-
-			d.Set("function_args", functionArgMaps)
-		}
-	}
+	d.Set("function_args", cdnConfigFunctionArgsFromApi("dcdn", object))
 
 	return nil
 }
@@ -224,16 +170,7 @@ func resourceAliCloudDcdnDomainConfigUpdate(d *schema.ResourceData, meta interfa
 		request["DomainNames"] = parts[0]
 
 		config := make([]map[string]interface{}, 1)
-		functionArgs := d.Get("function_args").(*schema.Set).List()
-
-		args := make([]map[string]interface{}, len(functionArgs))
-		for key, value := range functionArgs {
-			arg := value.(map[string]interface{})
-			args[key] = map[string]interface{}{
-				"argName":  arg["arg_name"],
-				"argValue": arg["arg_value"],
-			}
-		}
+		args := cdnConfigFunctionArgs(d.Get("function_args").(*schema.Set).List())
 
 		config[0] = map[string]interface{}{
 			"functionArgs": args,
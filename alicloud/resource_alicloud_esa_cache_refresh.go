@@ -0,0 +1,272 @@
+package alicloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// esaCacheRefreshTypes maps the object types of alicloud_esa_cache_refresh to the purge type of PurgeCaches and the
+// key of its content.
+var esaCacheRefreshTypes = map[string][2]string{
+	"File":         {"file", "Files"},
+	"Directory":    {"directory", "Directories"},
+	"IgnoreParams": {"ignoreParams", "IgnoreParams"},
+	"CacheTag":     {"cachetag", "CacheTags"},
+	"Hostname":     {"hostname", "Hostnames"},
+}
+
+func resourceAliCloudEsaCacheRefresh() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAliCloudEsaCacheRefreshCreate,
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return nil
+		},
+		Delete: func(d *schema.ResourceData, meta interface{}) error {
+			log.Printf("[WARN] Cannot destroy alicloud_esa_cache_refresh. Terraform will remove this resource from the state file, the caches are not affected.")
+			return nil
+		},
+		CustomizeDiff: func(d *schema.ResourceDiff, meta interface{}) error {
+			if d.Get("task_type").(string) == "Preload" && d.Get("object_type").(string) != "File" {
+				return fmt.Errorf("object_type must be File when task_type is Preload, got %s", d.Get("object_type"))
+			}
+			return nil
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"site_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"paths": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"object_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "File",
+				ValidateFunc: StringInSlice([]string{"File", "Directory", "IgnoreParams", "CacheTag", "Hostname"}, false),
+			},
+			"task_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "Refresh",
+				ValidateFunc: StringInSlice([]string{"Refresh", "Preload"}, false),
+			},
+			"force": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"task_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"failed_paths": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceAliCloudEsaCacheRefreshCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	taskType := d.Get("task_type").(string)
+	objectType := d.Get("object_type").(string)
+	paths := expandStringList(d.Get("paths").([]interface{}))
+	// the tasks are listed by creation time, with some slack for the clock of the ESA service
+	submitted := time.Now().UTC().Add(-5 * time.Minute)
+
+	action := "PurgeCaches"
+	if taskType == "Preload" {
+		action = "PreloadCaches"
+	}
+
+	taskIds := make([]string, 0)
+	for _, batch := range cacheRefreshBatches(paths, cacheRefreshBatchSize) {
+		var response map[string]interface{}
+		request := map[string]interface{}{
+			"SiteId": d.Get("site_id"),
+		}
+		content := map[string]interface{}{
+			"PreloadObjects": batch,
+		}
+		if taskType != "Preload" {
+			request["Type"] = esaCacheRefreshTypes[objectType][0]
+			content = map[string]interface{}{
+				esaCacheRefreshTypes[objectType][1]: batch,
+			}
+			if v, ok := d.GetOk("force"); ok {
+				request["Force"] = v
+			}
+		}
+		contentJson, err := json.Marshal(content)
+		if err != nil {
+			return WrapError(err)
+		}
+		request["Content"] = string(contentJson)
+
+		wait := incrementalWait(3*time.Second, 3*time.Second)
+		err = resource.Retry(client.GetRetryTimeout(d.Timeout(schema.TimeoutCreate)), func() *resource.RetryError {
+			response, err = client.RpcPost("ESA", "2024-09-10", action, nil, request, true)
+			if err != nil {
+				if IsExpectedErrors(err, []string{"ServiceBusy", "Throttling.User"}) || NeedRetry(err) {
+					wait()
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		})
+		addDebug(action, response, request)
+
+		if err != nil {
+			if len(taskIds) > 0 {
+				err = fmt.Errorf("%s, after the tasks %s were submitted", err, strings.Join(taskIds, ","))
+			}
+			return WrapErrorf(err, DefaultErrorMsg, "alicloud_esa_cache_refresh", action, AlibabaCloudSdkGoERROR)
+		}
+		taskIds = append(taskIds, fmt.Sprint(response["TaskId"]))
+	}
+
+	d.SetId(strings.Join(taskIds, ","))
+	d.Set("task_ids", taskIds)
+
+	var tasks []interface{}
+	stateConf := BuildTracedStateConf(client, []string{"Pending", "Refreshing"}, []string{"Complete"}, d.Timeout(schema.TimeoutCreate), 5*time.Second, func() (interface{}, string, error) {
+		object, err := describeEsaCacheRefreshTasks(client, fmt.Sprint(d.Get("site_id")), taskType, taskIds, submitted)
+		if err != nil {
+			return nil, "", WrapError(err)
+		}
+		tasks = object
+		return object, esaCacheRefreshTasksStatus(object, taskIds), nil
+	})
+	if _, err := stateConf.WaitForState(); err != nil {
+		return WrapErrorf(err, IdMsg, d.Id())
+	}
+
+	failed := cacheRefreshFailedPaths(tasks)
+	d.Set("failed_paths", failed)
+	if len(failed) > 0 {
+		return WrapErrorf(fmt.Errorf("%d of the %d paths failed:\n  - %s", len(failed), len(paths), strings.Join(failed, "\n  - ")), IdMsg, d.Id())
+	}
+
+	return nil
+}
+
+// describeEsaCacheRefreshTasks returns the purge or preload tasks of a site created since a time that belong to one
+// of the given tasks, in the shape of the CDN refresh tasks.
+func describeEsaCacheRefreshTasks(client *connectivity.AliyunClient, siteId, taskType string, taskIds []string, since time.Time) (object []interface{}, err error) {
+	action := "DescribePurgeTasks"
+	if taskType == "Preload" {
+		action = "DescribePreloadTasks"
+	}
+	object = make([]interface{}, 0)
+	query := map[string]interface{}{
+		"SiteId":    siteId,
+		"StartTime": since.Format("2006-01-02T15:04:05Z"),
+		"PageSize":  strconv.Itoa(PageSizeXLarge),
+	}
+	for pageNumber := 1; ; pageNumber++ {
+		query["PageNumber"] = strconv.Itoa(pageNumber)
+		var response map[string]interface{}
+		wait := incrementalWait(3*time.Second, 3*time.Second)
+		err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+			response, err = client.RpcGet("ESA", "2024-09-10", action, query, nil)
+			if err != nil {
+				if NeedRetry(err) {
+					wait()
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		})
+		addDebug(action, response, query)
+
+		if err != nil {
+			return object, WrapErrorf(err, DefaultErrorMsg, siteId, action, AlibabaCloudSdkGoERROR)
+		}
+
+		result, _ := response["Tasks"].([]interface{})
+		object = append(object, esaCacheRefreshTasks(result, taskIds)...)
+		if len(result) < PageSizeXLarge {
+			break
+		}
+	}
+	return object, nil
+}
+
+// esaCacheRefreshStatuses maps the status of an ESA purge or preload task to the one of a CDN refresh task.
+var esaCacheRefreshStatuses = map[string]string{
+	"running": "Refreshing",
+	"success": "Complete",
+	"failed":  "Failed",
+}
+
+// esaCacheRefreshTasks keeps the tasks that belong to one of the given tasks, with their content as ObjectPath, their
+// status mapped by esaCacheRefreshStatuses and their error code as Description. A status that is not mapped is kept
+// waiting for.
+func esaCacheRefreshTasks(tasks []interface{}, taskIds []string) []interface{} {
+	result := make([]interface{}, 0)
+	for _, v := range tasks {
+		task, ok := v.(map[string]interface{})
+		if !ok || !InArray(fmt.Sprint(task["TaskId"]), taskIds) {
+			continue
+		}
+		status, ok := esaCacheRefreshStatuses[strings.ToLower(fmt.Sprint(task["Status"]))]
+		if !ok {
+			log.Printf("[WARN] the task %v of alicloud_esa_cache_refresh has the unknown status %v, it is waited for", task["TaskId"], task["Status"])
+			status = "Refreshing"
+		}
+		item := map[string]interface{}{
+			"TaskId":     task["TaskId"],
+			"ObjectPath": task["Content"],
+			"Status":     status,
+		}
+		if errorCode, ok := task["ErrorCode"]; ok && errorCode != nil {
+			item["Description"] = errorCode
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// esaCacheRefreshTasksStatus returns "Pending" until every task is listed, and then the status of the tasks.
+func esaCacheRefreshTasksStatus(tasks []interface{}, taskIds []string) string {
+	listed := make(map[string]bool)
+	for _, v := range tasks {
+		if task, ok := v.(map[string]interface{}); ok {
+			listed[fmt.Sprint(task["TaskId"])] = true
+		}
+	}
+	if len(listed) < len(taskIds) {
+		return "Pending"
+	}
+	return cacheRefreshTasksStatus(tasks, 0)
+}
@@ -0,0 +1,457 @@
+package alicloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+const (
+	// esaKvBatchSize is the number of keys written or deleted per call.
+	esaKvBatchSize = 100
+	// esaKvBatchBytes bounds the size of the keys and values written per call.
+	esaKvBatchBytes = 1 << 20
+	// esaKvPageSize is the number of keys listed per call.
+	esaKvPageSize = 100
+)
+
+func resourceAliCloudEsaKvEntries() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAliCloudEsaKvEntriesCreate,
+		Read:   resourceAliCloudEsaKvEntriesRead,
+		Update: resourceAliCloudEsaKvEntriesUpdate,
+		Delete: resourceAliCloudEsaKvEntriesDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: resourceAliCloudEsaKvEntriesCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"entries": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"expiration_ttls": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"entries_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"entries", "expiration_ttls"},
+			},
+		},
+	}
+}
+
+func resourceAliCloudEsaKvEntriesCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	namespace := d.Get("namespace").(string)
+	prefix := d.Get("prefix").(string)
+
+	existing, err := listEsaKvs(client, namespace, prefix)
+	if err != nil {
+		return WrapError(err)
+	}
+
+	entries := expandEsaKvEntries(d.Get("entries").(map[string]interface{}))
+	ttls := expandEsaKvExpirationTtls(d.Get("expiration_ttls").(map[string]interface{}))
+	puts, deletes := esaKvChanges(expandEsaKvEntries(existing), entries, nil, ttls)
+
+	if prefix != "" {
+		d.SetId(fmt.Sprintf("%s:%s", namespace, prefix))
+	} else {
+		d.SetId(namespace)
+	}
+
+	if err := writeEsaKvs(client, namespace, puts, deletes, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+
+	return resourceAliCloudEsaKvEntriesRead(d, meta)
+}
+
+func resourceAliCloudEsaKvEntriesRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	parts := strings.SplitN(d.Id(), ":", 2)
+	prefix := ""
+	if len(parts) == 2 {
+		prefix = parts[1]
+	}
+
+	entries, err := listEsaKvs(client, parts[0], prefix)
+	if err != nil {
+		if !d.IsNewResource() && NotFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return WrapError(err)
+	}
+
+	d.Set("namespace", parts[0])
+	d.Set("prefix", prefix)
+	d.Set("entries", entries)
+
+	// Expiration TTLs are not returned by the listing, keep the ones of the keys still present.
+	ttls := make(map[string]interface{})
+	for key, ttl := range d.Get("expiration_ttls").(map[string]interface{}) {
+		if _, ok := entries[key]; ok {
+			ttls[key] = ttl
+		}
+	}
+	d.Set("expiration_ttls", ttls)
+
+	return nil
+}
+
+func resourceAliCloudEsaKvEntriesUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+
+	if d.HasChange("entries") || d.HasChange("expiration_ttls") {
+		o, n := d.GetChange("entries")
+		oldTtls, newTtls := d.GetChange("expiration_ttls")
+		puts, deletes := esaKvChanges(
+			expandEsaKvEntries(o.(map[string]interface{})),
+			expandEsaKvEntries(n.(map[string]interface{})),
+			expandEsaKvExpirationTtls(oldTtls.(map[string]interface{})),
+			expandEsaKvExpirationTtls(newTtls.(map[string]interface{})))
+
+		if err := writeEsaKvs(client, d.Get("namespace").(string), puts, deletes, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
+	return resourceAliCloudEsaKvEntriesRead(d, meta)
+}
+
+func resourceAliCloudEsaKvEntriesDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+
+	entries := expandEsaKvEntries(d.Get("entries").(map[string]interface{}))
+	deletes := make([]string, 0, len(entries))
+	for key := range entries {
+		deletes = append(deletes, key)
+	}
+	sort.Strings(deletes)
+
+	err := writeEsaKvs(client, d.Get("namespace").(string), nil, deletes, d.Timeout(schema.TimeoutDelete))
+	if err != nil && NotFoundError(err) {
+		return nil
+	}
+	return err
+}
+
+// resourceAliCloudEsaKvEntriesCustomizeDiff plans the entries of entries_file, and checks every key is under the
+// prefix owned by the resource.
+func resourceAliCloudEsaKvEntriesCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("entries") || !d.NewValueKnown("expiration_ttls") || !d.NewValueKnown("entries_file") {
+		return nil
+	}
+
+	entries := d.Get("entries").(map[string]interface{})
+	ttls := d.Get("expiration_ttls").(map[string]interface{})
+	if file := d.Get("entries_file").(string); file != "" {
+		content, err := loadFileContent(file)
+		if err != nil {
+			return WrapError(err)
+		}
+		fileEntries, fileTtls, err := parseEsaKvEntriesFile(content)
+		if err != nil {
+			return fmt.Errorf("invalid entries_file %s: %s", file, err)
+		}
+		entries = make(map[string]interface{}, len(fileEntries))
+		for key, value := range fileEntries {
+			entries[key] = value
+		}
+		ttls = make(map[string]interface{}, len(fileTtls))
+		for key, ttl := range fileTtls {
+			ttls[key] = ttl
+		}
+		if err := d.SetNew("entries", entries); err != nil {
+			return WrapError(err)
+		}
+		if err := d.SetNew("expiration_ttls", ttls); err != nil {
+			return WrapError(err)
+		}
+	}
+
+	prefix := d.Get("prefix").(string)
+	for key := range entries {
+		if key == "" || !strings.HasPrefix(key, prefix) {
+			return fmt.Errorf("the key %q of the entries is not under the prefix %q", key, prefix)
+		}
+	}
+	return nil
+}
+
+// parseEsaKvEntriesFile parses a JSON object mapping keys either to their value, or to an object with the value and
+// its expiration_ttl.
+func parseEsaKvEntriesFile(content []byte) (map[string]string, map[string]int, error) {
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, nil, err
+	}
+	entries := make(map[string]string, len(raw))
+	ttls := make(map[string]int)
+	for key, message := range raw {
+		var value string
+		if err := json.Unmarshal(message, &value); err == nil {
+			entries[key] = value
+			continue
+		}
+		var entry struct {
+			Value         *string `json:"value"`
+			ExpirationTtl int     `json:"expiration_ttl"`
+		}
+		if err := json.Unmarshal(message, &entry); err != nil || entry.Value == nil {
+			return nil, nil, fmt.Errorf("the key %q must map to a string or to an object with a string value", key)
+		}
+		entries[key] = *entry.Value
+		if entry.ExpirationTtl > 0 {
+			ttls[key] = entry.ExpirationTtl
+		}
+	}
+	return entries, ttls, nil
+}
+
+func expandEsaKvEntries(m map[string]interface{}) map[string]string {
+	entries := make(map[string]string, len(m))
+	for key, value := range m {
+		entries[key] = fmt.Sprint(value)
+	}
+	return entries
+}
+
+func expandEsaKvExpirationTtls(m map[string]interface{}) map[string]int {
+	ttls := make(map[string]int, len(m))
+	for key, value := range m {
+		if ttl, err := strconv.Atoi(fmt.Sprint(value)); err == nil {
+			ttls[key] = ttl
+		}
+	}
+	return ttls
+}
+
+// esaKvChanges returns the keys to write, with their value and expiration TTL, and the keys to delete, to go from
+// the old entries to the new ones.
+func esaKvChanges(oldEntries, newEntries map[string]string, oldTtls, newTtls map[string]int) ([]map[string]interface{}, []string) {
+	keys := make([]string, 0, len(newEntries))
+	for key := range newEntries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	puts := make([]map[string]interface{}, 0)
+	for _, key := range keys {
+		value := newEntries[key]
+		old, ok := oldEntries[key]
+		if ok && old == value && oldTtls[key] == newTtls[key] {
+			continue
+		}
+		put := map[string]interface{}{
+			"Key":   key,
+			"Value": value,
+		}
+		if ttl := newTtls[key]; ttl > 0 {
+			put["ExpirationTtl"] = ttl
+		}
+		puts = append(puts, put)
+	}
+
+	deletes := make([]string, 0)
+	for key := range oldEntries {
+		if _, ok := newEntries[key]; !ok {
+			deletes = append(deletes, key)
+		}
+	}
+	sort.Strings(deletes)
+	return puts, deletes
+}
+
+// esaKvPutBatches splits the keys to write into batches within the number of keys and bytes accepted per call.
+func esaKvPutBatches(puts []map[string]interface{}) [][]map[string]interface{} {
+	batches := make([][]map[string]interface{}, 0)
+	batch := make([]map[string]interface{}, 0)
+	size := 0
+	for _, put := range puts {
+		bytes := len(fmt.Sprint(put["Key"])) + len(fmt.Sprint(put["Value"]))
+		if len(batch) > 0 && (len(batch) >= esaKvBatchSize || size+bytes > esaKvBatchBytes) {
+			batches = append(batches, batch)
+			batch, size = make([]map[string]interface{}, 0), 0
+		}
+		batch = append(batch, put)
+		size += bytes
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// writeEsaKvs deletes and writes keys of a namespace in batches, failing with the keys the API did not accept.
+func writeEsaKvs(client *connectivity.AliyunClient, namespace string, puts []map[string]interface{}, deletes []string, timeout time.Duration) error {
+	for start := 0; start < len(deletes); start += esaKvBatchSize {
+		end := start + esaKvBatchSize
+		if end > len(deletes) {
+			end = len(deletes)
+		}
+		keys, _ := json.Marshal(deletes[start:end])
+		request := map[string]interface{}{
+			"RegionId":  client.RegionId,
+			"Namespace": namespace,
+			"Keys":      string(keys),
+		}
+		if err := batchEsaKvs(client, "BatchDeleteKv", request, timeout); err != nil {
+			return err
+		}
+	}
+
+	for _, batch := range esaKvPutBatches(puts) {
+		kvs, _ := json.Marshal(batch)
+		request := map[string]interface{}{
+			"RegionId":  client.RegionId,
+			"Namespace": namespace,
+			"KvList":    string(kvs),
+		}
+		if err := batchEsaKvs(client, "BatchPutKv", request, timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func batchEsaKvs(client *connectivity.AliyunClient, action string, request map[string]interface{}, timeout time.Duration) error {
+	var response map[string]interface{}
+	var err error
+	wait := incrementalWait(3*time.Second, 5*time.Second)
+	err = resource.Retry(timeout, func() *resource.RetryError {
+		response, err = client.RpcPost("ESA", "2024-09-10", action, nil, request, true)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug(action, response, request)
+
+	if err != nil {
+		return WrapErrorf(err, DefaultErrorMsg, request["Namespace"], action, AlibabaCloudSdkGoERROR)
+	}
+
+	if failed, ok := response["FailKeys"].([]interface{}); ok && len(failed) > 0 {
+		keys := make([]string, 0, len(failed))
+		for _, key := range failed {
+			keys = append(keys, fmt.Sprint(key))
+		}
+		return WrapErrorf(fmt.Errorf("%d keys failed: %s", len(keys), strings.Join(keys, ", ")), DefaultErrorMsg, request["Namespace"], action, AlibabaCloudSdkGoERROR)
+	}
+	return nil
+}
+
+// listEsaKvs returns the keys of a namespace under a prefix with their value, listed page by page. Values the listing
+// leaves out are read concurrently, within the concurrency of the data sources.
+func listEsaKvs(client *connectivity.AliyunClient, namespace, prefix string) (map[string]interface{}, error) {
+	esaServiceV2 := EsaServiceV2{client}
+	action := "ListKvs"
+	entries := make(map[string]interface{})
+	// The listing may leave the values out, they are read afterwards with one call per key.
+	valueless := make([]string, 0)
+	query := map[string]interface{}{
+		"RegionId":  client.RegionId,
+		"Namespace": namespace,
+		"PageSize":  esaKvPageSize,
+	}
+	if prefix != "" {
+		query["Prefix"] = prefix
+	}
+
+	for pageNumber := 1; ; pageNumber++ {
+		var response map[string]interface{}
+		var err error
+		query["PageNumber"] = pageNumber
+
+		wait := incrementalWait(3*time.Second, 5*time.Second)
+		err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+			response, err = client.RpcGet("ESA", "2024-09-10", action, query, nil)
+			if err != nil {
+				if NeedRetry(err) {
+					wait()
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		})
+		addDebug(action, response, query)
+
+		if err != nil {
+			if IsExpectedErrors(err, []string{"InvalidNameSpace.NotFound", "KvNamespace.NotFound"}) {
+				return nil, WrapErrorf(err, NotFoundMsg, AlibabaCloudSdkGoERROR)
+			}
+			return nil, WrapErrorf(err, DefaultErrorMsg, namespace, action, AlibabaCloudSdkGoERROR)
+		}
+
+		keys, _ := response["Keys"].([]interface{})
+		for _, v := range keys {
+			kv := v.(map[string]interface{})
+			key := fmt.Sprint(kv["Name"])
+			if value, ok := kv["Value"]; ok && value != nil {
+				entries[key] = fmt.Sprint(value)
+				continue
+			}
+			valueless = append(valueless, key)
+		}
+
+		if len(keys) < esaKvPageSize {
+			break
+		}
+		if total, err := strconv.Atoi(fmt.Sprint(response["TotalCount"])); err == nil && pageNumber*esaKvPageSize >= total {
+			break
+		}
+	}
+
+	values := make([]string, len(valueless))
+	err := fetchParallel(dataSourceFetchLimit(client), valueless, func(i int) error {
+		object, err := esaServiceV2.DescribeEsaKv(fmt.Sprintf("%s:%s", namespace, valueless[i]))
+		if err != nil {
+			return err
+		}
+		values[i] = fmt.Sprint(object["Value"])
+		return nil
+	})
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	for i, key := range valueless {
+		entries[key] = values[i]
+	}
+	return entries, nil
+}
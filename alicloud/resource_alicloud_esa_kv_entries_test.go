@@ -0,0 +1,49 @@
+package alicloud
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitEsaKvEntries(t *testing.T) {
+	entries, ttls, err := parseEsaKvEntriesFile([]byte(`{
+		"redirect/a": "/b",
+		"flag/beta": {"value": "on", "expiration_ttl": 3600},
+		"flag/old": {"value": ""}
+	}`))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"redirect/a": "/b", "flag/beta": "on", "flag/old": ""}, entries)
+	assert.Equal(t, map[string]int{"flag/beta": 3600}, ttls)
+	_, _, err = parseEsaKvEntriesFile([]byte(`{"flag/beta": {"expiration_ttl": 3600}}`))
+	assert.NotNil(t, err)
+	_, _, err = parseEsaKvEntriesFile([]byte(`{"flag/beta": 1}`))
+	assert.NotNil(t, err)
+
+	puts, deletes := esaKvChanges(
+		map[string]string{"a": "1", "b": "2", "c": "3", "d": "4"},
+		map[string]string{"a": "1", "b": "20", "d": "4", "e": "5"},
+		map[string]int{"d": 60},
+		map[string]int{"d": 120, "e": 60})
+	assert.Equal(t, []map[string]interface{}{
+		{"Key": "b", "Value": "20"},
+		{"Key": "d", "Value": "4", "ExpirationTtl": 120},
+		{"Key": "e", "Value": "5", "ExpirationTtl": 60},
+	}, puts)
+	assert.Equal(t, []string{"c"}, deletes)
+
+	many := make([]map[string]interface{}, 0)
+	for i := 0; i < 250; i++ {
+		many = append(many, map[string]interface{}{"Key": fmt.Sprintf("k%d", i), "Value": "v"})
+	}
+	batches := esaKvPutBatches(many)
+	assert.Len(t, batches, 3)
+	assert.Len(t, batches[2], 50)
+	large := strings.Repeat("x", esaKvBatchBytes/2)
+	batches = esaKvPutBatches([]map[string]interface{}{{"Key": "a", "Value": large}, {"Key": "b", "Value": large}, {"Key": "c", "Value": "v"}})
+	assert.Len(t, batches, 2)
+	assert.Len(t, batches[1], 2)
+	assert.Len(t, esaKvPutBatches(nil), 0)
+}
@@ -21,6 +21,9 @@ func resourceAlicloudEssInstanceRefresh() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"scaling_group_id": {
 				Type:     schema.TypeString,
@@ -156,6 +159,24 @@ func resourceAlicloudEssInstanceRefresh() *schema.Resource {
 				Optional: true,
 				ForceNew: true,
 			},
+			"wait_for_completion": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"rollback_on_failure": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"total_need_update_capacity": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"finished_update_capacity": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
 		},
 	}
 }
@@ -186,9 +207,94 @@ func resourceAliyunEssInstanceRefreshCreate(d *schema.ResourceData, meta interfa
 	}
 	addDebug("StartInstanceRefresh", raw, request, request)
 	d.SetId(fmt.Sprint(request["ScalingGroupId"], ":", raw["InstanceRefreshTaskId"]))
+	if d.Get("wait_for_completion").(bool) {
+		if err := essInstanceRefreshWaitForCompletion(d, client); err != nil {
+			return err
+		}
+	}
 	return resourceAliyunEssInstanceRefreshRead(d, meta)
 }
 
+// essInstanceRefreshWaitForCompletion blocks until the refresh task succeeds. When the task fails,
+// or the healthy share of the group drops below min_healthy_percentage, the task is rolled back
+// unless rollback_on_failure is disabled, and the failure reason is returned.
+func essInstanceRefreshWaitForCompletion(d *schema.ResourceData, client *connectivity.AliyunClient) error {
+	essService := EssService{client}
+	scalingGroupId := d.Get("scaling_group_id").(string)
+	minHealthyPercentage, checkHealth := d.GetOkExists("min_healthy_percentage")
+	failStates := []string{"Failed", "Cancelled", "RollbackInProgress", "RollbackSuccessful", "RollbackFailed"}
+	refresh := essService.EssInstanceRefreshStateRefreshFunc(d.Id(), failStates)
+	var reason string
+	stateConf := BuildTracedStateConf(client, []string{}, []string{"Successful"}, d.Timeout(schema.TimeoutCreate), 10*time.Second, func() (interface{}, string, error) {
+		object, status, err := refresh()
+		if err != nil || !checkHealth || status != "InProgress" {
+			return object, status, err
+		}
+		healthyPercentage, err := essService.DescribeEssScalingGroupHealthyPercentage(scalingGroupId)
+		if err != nil {
+			return object, status, WrapError(err)
+		}
+		if healthyPercentage < minHealthyPercentage.(int) {
+			reason = fmt.Sprintf("only %d%% of the in service instances of %s are healthy, below min_healthy_percentage %d%%", healthyPercentage, scalingGroupId, minHealthyPercentage.(int))
+			return object, "Unhealthy", WrapError(Error(FailedToReachTargetStatus, "Unhealthy"))
+		}
+		return object, status, nil
+	})
+	if _, err := stateConf.WaitForState(); err == nil {
+		return nil
+	} else if _, ok := err.(*resource.TimeoutError); ok {
+		return WrapErrorf(err, IdMsg, d.Id())
+	}
+
+	object, err := essService.DescribeEssInstanceRefresh(d.Id())
+	if err != nil {
+		return WrapError(err)
+	}
+	status := fmt.Sprint(object["Status"])
+	if reason == "" {
+		reason = fmt.Sprintf("the task is %s", status)
+		if detail, ok := object["Detail"].(string); ok && detail != "" {
+			reason = fmt.Sprintf("%s: %s", reason, detail)
+		}
+	}
+	if !d.Get("rollback_on_failure").(bool) || (status != "Failed" && status != "InProgress" && status != "Paused") {
+		return WrapError(fmt.Errorf("instance refresh %s did not succeed, %s", d.Id(), reason))
+	}
+
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return WrapError(err)
+	}
+	action := "RollbackInstanceRefresh"
+	request := map[string]interface{}{
+		"ScalingGroupId":        parts[0],
+		"RegionId":              client.RegionId,
+		"InstanceRefreshTaskId": parts[1],
+	}
+	wait := incrementalWait(1*time.Second, 2*time.Second)
+	var response map[string]interface{}
+	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+		response, err = client.RpcPost("Ess", "2014-08-28", action, nil, request, true)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug(action, response, request)
+	if err != nil {
+		return WrapErrorf(err, DefaultErrorMsg, d.Id(), action, AlibabaCloudSdkGoERROR)
+	}
+	rollbackConf := BuildTracedStateConf(client, []string{}, []string{"RollbackSuccessful"}, d.Timeout(schema.TimeoutCreate), 10*time.Second, essService.EssInstanceRefreshStateRefreshFunc(d.Id(), []string{"RollbackFailed"}))
+	if _, err := rollbackConf.WaitForState(); err != nil {
+		return WrapError(fmt.Errorf("instance refresh %s did not succeed, %s, and rolling it back failed: %v", d.Id(), reason, err))
+	}
+	return WrapError(fmt.Errorf("instance refresh %s did not succeed and has been rolled back, %s", d.Id(), reason))
+}
+
 func resourceAliyunEssInstanceRefreshRead(d *schema.ResourceData, meta interface{}) error {
 
 	client := meta.(*connectivity.AliyunClient)
@@ -301,6 +407,12 @@ func resourceAliyunEssInstanceRefreshRead(d *schema.ResourceData, meta interface
 	if object["CheckpointPauseTime"] != nil {
 		d.Set("checkpoint_pause_time", object["CheckpointPauseTime"])
 	}
+	if object["TotalNeedUpdateCapacity"] != nil {
+		d.Set("total_need_update_capacity", formatInt(object["TotalNeedUpdateCapacity"]))
+	}
+	if object["FinishedUpdateCapacity"] != nil {
+		d.Set("finished_update_capacity", formatInt(object["FinishedUpdateCapacity"]))
+	}
 
 	if v := object["Checkpoints"]; v != nil {
 		result := make([]map[string]interface{}, 0)
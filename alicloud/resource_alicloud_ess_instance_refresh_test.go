@@ -1,6 +1,7 @@
 package alicloud
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccAliCloudEssInstanceRefresh_image(t *testing.T) {
@@ -78,7 +80,7 @@ func TestAccAliCloudEssInstanceRefresh_image(t *testing.T) {
 				ResourceName:            resourceId,
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{},
+				ImportStateVerifyIgnore: []string{"wait_for_completion", "rollback_on_failure"},
 			},
 		},
 	})
@@ -151,7 +153,7 @@ func TestAccAliCloudEssInstanceRefresh_template(t *testing.T) {
 				ResourceName:            resourceId,
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{},
+				ImportStateVerifyIgnore: []string{"wait_for_completion", "rollback_on_failure"},
 			},
 		},
 	})
@@ -244,12 +246,84 @@ func TestAccAliCloudEssInstanceRefresh_container(t *testing.T) {
 				ResourceName:            resourceId,
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{},
+				ImportStateVerifyIgnore: []string{"wait_for_completion", "rollback_on_failure"},
 			},
 		},
 	})
 }
 
+func TestAccAliCloudEssInstanceRefresh_waitForCompletion(t *testing.T) {
+	rand := acctest.RandIntRange(1000, 999999)
+	resourceId := "alicloud_ess_instance_refresh.default"
+	checkoutSupportedRegions(t, true, connectivity.MetaTagSupportRegions)
+	var v map[string]interface{}
+	basicMap := map[string]string{
+		"scaling_group_id": CHECKSET,
+	}
+	ra := resourceAttrInit(resourceId, basicMap)
+	rc := resourceCheckInit(resourceId, &v, func() interface{} {
+		return &EssService{testAccProvider.Meta().(*connectivity.AliyunClient)}
+	})
+	rac := resourceAttrCheckInit(rc, ra)
+
+	testAccCheck := rac.resourceAttrMapUpdateSet()
+	name := fmt.Sprintf("tf-testAccEssInstanceRefresh-%d", rand)
+	testAccConfig := resourceTestAccConfigFunc(resourceId, name, testAccEssInstanceRefreshImage)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		IDRefreshName: resourceId,
+
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckEssInstanceRefreshDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConfig(map[string]interface{}{
+					"depends_on":                     []string{"alicloud_ess_scaling_configuration.default"},
+					"scaling_group_id":               "${alicloud_ess_scaling_group.default.id}",
+					"desired_configuration_image_id": "${data.alicloud_images.default2.images[0].id}",
+					"min_healthy_percentage":         "50",
+					"max_healthy_percentage":         "150",
+					"skip_matching":                  "false",
+					"wait_for_completion":            "true",
+					"rollback_on_failure":            "true",
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheck(map[string]string{
+						"id":                         CHECKSET,
+						"status":                     "Successful",
+						"wait_for_completion":        "true",
+						"rollback_on_failure":        "true",
+						"total_need_update_capacity": CHECKSET,
+						"finished_update_capacity":   CHECKSET,
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitAliCloudEssInstanceRefreshProgress(t *testing.T) {
+	object := map[string]interface{}{
+		"TotalNeedUpdateCapacity": json.Number("10"),
+		"FinishedUpdateCapacity":  json.Number("6"),
+		"Checkpoints": map[string]interface{}{
+			"Checkpoint": []interface{}{
+				map[string]interface{}{"Percentage": json.Number("50")},
+				map[string]interface{}{"Percentage": json.Number("70")},
+				map[string]interface{}{"Percentage": json.Number("100")},
+			},
+		},
+	}
+	assert.Equal(t, "6/10 instances refreshed, next checkpoint at 70%", essInstanceRefreshProgress(object))
+
+	object["FinishedUpdateCapacity"] = json.Number("10")
+	assert.Equal(t, "10/10 instances refreshed", essInstanceRefreshProgress(object))
+
+	assert.Equal(t, "0/0 instances refreshed", essInstanceRefreshProgress(map[string]interface{}{}))
+}
+
 func testAccCheckEssInstanceRefreshDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*connectivity.AliyunClient)
 	essService := EssService{client}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"time"
 
@@ -23,6 +24,11 @@ func resourceAlicloudEssScalingGroup() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: resourceAliyunEssScalingGroupCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"min_size": {
@@ -278,10 +284,61 @@ func resourceAlicloudEssScalingGroup() *schema.Resource {
 					},
 				},
 			},
+			"warm_pool": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"scaling_policy"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"size": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: IntBetween(0, 2000),
+						},
+						"min_size": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      0,
+							ValidateFunc: IntBetween(0, 2000),
+						},
+						"instance_state": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "Stopped",
+							ValidateFunc: StringInSlice([]string{"Stopped", "Running"}, false),
+						},
+						"reuse_on_scale_in": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"current_size": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
+// resourceAliyunEssScalingGroupCustomizeDiff rejects a warm pool on a new scaling group that is
+// not enabled by its launch template, as the pool can only be filled once the group is active and
+// its scaling configuration is only enabled after the group is created.
+func resourceAliyunEssScalingGroupCustomizeDiff(diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() != "" || !diff.NewValueKnown("warm_pool") || !diff.NewValueKnown("launch_template_id") {
+		return nil
+	}
+	if v, ok := diff.GetOk("warm_pool"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		if v.([]interface{})[0].(map[string]interface{})["size"].(int) > 0 && diff.Get("launch_template_id").(string) == "" {
+			return fmt.Errorf("warm_pool can only be filled while the scaling group is active: set launch_template_id, or add warm_pool once the scaling configuration of the group is enabled")
+		}
+	}
+	return nil
+}
+
 func resourceAliyunEssScalingGroupCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*connectivity.AliyunClient)
 	essService := EssService{client}
@@ -332,6 +389,12 @@ func resourceAliyunEssScalingGroupCreate(d *schema.ResourceData, meta interface{
 		if err != nil {
 			return WrapErrorf(err, DefaultErrorMsg, "alicloud_ess_scaling_group", enableGroupRequest.GetActionName(), AlibabaCloudSdkGoERROR)
 		}
+		// the warm pool can only be filled once the group is active
+		if v, ok := d.GetOk("warm_pool"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+			if err := essService.WaitForEssScalingGroup(d.Id(), Active, DefaultTimeout); err != nil {
+				return WrapError(err)
+			}
+		}
 	}
 
 	return resourceAliyunEssScalingGroupUpdate(d, meta)
@@ -509,6 +572,12 @@ func resourceAliyunEssScalingGroupRead(d *schema.ResourceData, meta interface{})
 	}
 	d.Set("protected_instances", protectedInstances)
 
+	pool, err := essService.DescribeEssWarmPoolInstances(d.Id())
+	if err != nil {
+		return WrapError(err)
+	}
+	d.Set("warm_pool", flattenEssWarmPool(d.Get("warm_pool").([]interface{}), pool, fmt.Sprint(object["ScalingPolicy"])))
+
 	return nil
 }
 
@@ -547,6 +616,18 @@ func resourceAliyunEssScalingGroupUpdate(d *schema.ResourceData, meta interface{
 		request["ScalingPolicy"] = d.Get("scaling_policy").(string)
 	}
 
+	if d.HasChange("warm_pool") {
+		if v, ok := d.GetOk("warm_pool"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+			request["ScalingPolicy"] = "release"
+			if v.([]interface{})[0].(map[string]interface{})["reuse_on_scale_in"].(bool) {
+				request["ScalingPolicy"] = "recycle"
+			}
+		} else if !d.HasChange("scaling_policy") {
+			// without a warm pool, instances removed by scale-in are released again
+			request["ScalingPolicy"] = "release"
+		}
+	}
+
 	if d.HasChange("min_size") {
 		request["MinSize"] = requests.NewInteger(d.Get("min_size").(int))
 	}
@@ -734,6 +815,12 @@ func resourceAliyunEssScalingGroupUpdate(d *schema.ResourceData, meta interface{
 		}
 	}
 
+	if d.HasChange("warm_pool") {
+		if err := reconcileEssWarmPool(d, client); err != nil {
+			return WrapError(err)
+		}
+	}
+
 	d.Partial(false)
 	return resourceAliyunEssScalingGroupRead(d, meta)
 }
@@ -1166,3 +1253,141 @@ func partition(instanceIds *schema.Set, batchSize int) [][]string {
 	}
 	return res
 }
+
+// flattenEssWarmPool builds the warm_pool block from the standby instances of a scaling group. The
+// block is left out when the group has no standby instances and no warm pool is configured. min_size
+// is kept from the configuration, and instance_state falls back to the state of the pool instances
+// when the group was imported.
+func flattenEssWarmPool(configured []interface{}, pool map[string]string, scalingPolicy string) []map[string]interface{} {
+	minSize, instanceState := 0, essWarmPoolInstanceState(pool)
+	if len(configured) > 0 && configured[0] != nil {
+		warmPool := configured[0].(map[string]interface{})
+		minSize = warmPool["min_size"].(int)
+		instanceState = warmPool["instance_state"].(string)
+	} else if len(pool) == 0 {
+		return nil
+	}
+	if instanceState == "" {
+		instanceState = "Stopped"
+	}
+	return []map[string]interface{}{
+		{
+			"size":              len(pool),
+			"min_size":          minSize,
+			"instance_state":    instanceState,
+			"reuse_on_scale_in": scalingPolicy == "recycle",
+			"current_size":      len(pool),
+		},
+	}
+}
+
+// reconcileEssWarmPool brings the standby instances of a scaling group to the configured warm pool.
+// Missing instances are launched by scaling out and moved into standby, surplus instances are removed,
+// and every pool instance is started or stopped to match instance_state. It then waits for the group
+// to settle and fails when fewer than min_size instances are in the pool.
+func reconcileEssWarmPool(d *schema.ResourceData, client *connectivity.AliyunClient) error {
+	essService := EssService{client}
+	timeout := d.Timeout(schema.TimeoutUpdate)
+	if d.IsNewResource() {
+		timeout = d.Timeout(schema.TimeoutCreate)
+	}
+	size, minSize, instanceState := 0, 0, "Stopped"
+	if v, ok := d.GetOk("warm_pool"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		warmPool := v.([]interface{})[0].(map[string]interface{})
+		size = warmPool["size"].(int)
+		minSize = warmPool["min_size"].(int)
+		instanceState = warmPool["instance_state"].(string)
+	}
+
+	pool, err := essService.DescribeEssWarmPoolInstances(d.Id())
+	if err != nil {
+		return WrapError(err)
+	}
+
+	if len(pool) > size {
+		ids := make([]string, 0, len(pool))
+		for id := range pool {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		if err := essService.EssRemoveInstances(client, d, d.Id(), ids[:len(pool)-size]); err != nil {
+			return WrapError(err)
+		}
+	}
+
+	if len(pool) < size {
+		object, err := essService.DescribeEssScalingGroupById(d.Id())
+		if err != nil {
+			return WrapError(err)
+		}
+		if fmt.Sprint(object["LifecycleState"]) != string(Active) {
+			return WrapError(fmt.Errorf("the warm pool of scaling group %s can not be filled while the group is %s, it has %d of %d instances", d.Id(), object["LifecycleState"], len(pool), size))
+		}
+		launched, err := essService.EssScaleWithAdjustment(d.Id(), size-len(pool), timeout)
+		if err != nil {
+			return WrapError(err)
+		}
+		if err := essService.EssEnterStandby(d.Id(), launched, timeout); err != nil {
+			return WrapError(err)
+		}
+		// Scaling out raised the desired capacity, but the new instances serve the pool and not the group.
+		if object["DesiredCapacity"] != nil {
+			current, err := essService.DescribeEssScalingGroupById(d.Id())
+			if err != nil {
+				return WrapError(err)
+			}
+			if formatInt(current["DesiredCapacity"]) != formatInt(object["DesiredCapacity"]) {
+				action := "ModifyScalingGroup"
+				request := map[string]interface{}{
+					"ScalingGroupId":  d.Id(),
+					"DesiredCapacity": formatInt(object["DesiredCapacity"]),
+				}
+				var response map[string]interface{}
+				wait := incrementalWait(3*time.Second, 5*time.Second)
+				err = resource.Retry(timeout, func() *resource.RetryError {
+					response, err = client.RpcPost("Ess", "2014-08-28", action, nil, request, false)
+					if err != nil {
+						if NeedRetry(err) || IsExpectedErrors(err, []string{"ScalingActivityInProgress", "IncorrectScalingGroupStatus"}) {
+							wait()
+							return resource.RetryableError(err)
+						}
+						return resource.NonRetryableError(err)
+					}
+					return nil
+				})
+				addDebug(action, response, request)
+				if err != nil {
+					return WrapErrorf(err, DefaultErrorMsg, d.Id(), action, AlibabaCloudSdkGoERROR)
+				}
+			}
+		}
+	}
+
+	pool, err = essService.DescribeEssWarmPoolInstances(d.Id())
+	if err != nil {
+		return WrapError(err)
+	}
+	var mismatched []string
+	for id, status := range pool {
+		if status != instanceState {
+			mismatched = append(mismatched, id)
+		}
+	}
+	sort.Strings(mismatched)
+	if err := essService.EssSetWarmPoolInstanceState(d.Id(), mismatched, instanceState, timeout); err != nil {
+		return WrapError(err)
+	}
+
+	stateConf := BuildTracedStateConf(client, []string{"Scaling"}, []string{"Settled"}, timeout, 5*time.Second, essService.EssScalingGroupCapacityStateRefreshFunc(d.Id(), len(pool)))
+	if _, err := stateConf.WaitForState(); err != nil {
+		return WrapErrorf(err, IdMsg, d.Id())
+	}
+	pool, err = essService.DescribeEssWarmPoolInstances(d.Id())
+	if err != nil {
+		return WrapError(err)
+	}
+	if len(pool) < minSize {
+		return WrapError(fmt.Errorf("the warm pool of scaling group %s has %d instances, fewer than its min_size %d", d.Id(), len(pool), minSize))
+	}
+	return nil
+}
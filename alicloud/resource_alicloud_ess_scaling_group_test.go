@@ -1,6 +1,7 @@
 package alicloud
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func init() {
@@ -524,6 +526,156 @@ func TestAccAliClouddEssScalingGroup_withLaunchTemplateId(t *testing.T) {
 
 }
 
+func TestAccAliCloudEssScalingGroup_warmPool(t *testing.T) {
+	rand := acctest.RandIntRange(10000, 999999)
+	var v ess.ScalingGroup
+	resourceId := "alicloud_ess_scaling_group.default"
+
+	basicMap := map[string]string{
+		"min_size":           "0",
+		"max_size":           "4",
+		"default_cooldown":   "20",
+		"scaling_group_name": fmt.Sprintf("tf-testAccEssScalingGroup-%d", rand),
+		"vswitch_ids.#":      "1",
+	}
+
+	ra := resourceAttrInit(resourceId, basicMap)
+	rc := resourceCheckInit(resourceId, &v, func() interface{} {
+		return &EssService{testAccProvider.Meta().(*connectivity.AliyunClient)}
+	})
+	rac := resourceAttrCheckInit(rc, ra)
+
+	testAccCheck := rac.resourceAttrMapUpdateSet()
+	name := fmt.Sprintf("tf-testAccEssScalingGroup-%d", rand)
+	testAccConfig := resourceTestAccConfigFunc(resourceId, name, resourceEssScalingGroupTemplate)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+
+		// module name
+		IDRefreshName: resourceId,
+
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckEssScalingGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConfig(map[string]interface{}{
+					"min_size":                "0",
+					"max_size":                "4",
+					"scaling_group_name":      "${var.name}",
+					"default_cooldown":        "20",
+					"vswitch_ids":             []string{"${alicloud_vswitch.tmpVs.id}"},
+					"launch_template_id":      "${alicloud_ecs_launch_template.default3.id}",
+					"launch_template_version": "Default",
+					"warm_pool": []map[string]interface{}{
+						{
+							"size":     "2",
+							"min_size": "1",
+						},
+					},
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheck(map[string]string{
+						"warm_pool.#":                   "1",
+						"warm_pool.0.size":              "2",
+						"warm_pool.0.min_size":          "1",
+						"warm_pool.0.instance_state":    "Stopped",
+						"warm_pool.0.reuse_on_scale_in": "false",
+						"warm_pool.0.current_size":      CHECKSET,
+						"scaling_policy":                "release",
+					}),
+				),
+			},
+			{
+				Config: testAccConfig(map[string]interface{}{
+					"min_size":                "0",
+					"max_size":                "4",
+					"scaling_group_name":      "${var.name}",
+					"default_cooldown":        "20",
+					"vswitch_ids":             []string{"${alicloud_vswitch.tmpVs.id}"},
+					"launch_template_id":      "${alicloud_ecs_launch_template.default3.id}",
+					"launch_template_version": "Default",
+					"warm_pool": []map[string]interface{}{
+						{
+							"size":              "1",
+							"min_size":          "1",
+							"instance_state":    "Running",
+							"reuse_on_scale_in": "true",
+						},
+					},
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheck(map[string]string{
+						"warm_pool.0.size":              "1",
+						"warm_pool.0.instance_state":    "Running",
+						"warm_pool.0.reuse_on_scale_in": "true",
+						"warm_pool.0.current_size":      "1",
+						"scaling_policy":                "recycle",
+					}),
+				),
+			},
+			{
+				Config: testAccConfig(map[string]interface{}{
+					"min_size":                "0",
+					"max_size":                "4",
+					"scaling_group_name":      "${var.name}",
+					"default_cooldown":        "20",
+					"vswitch_ids":             []string{"${alicloud_vswitch.tmpVs.id}"},
+					"launch_template_id":      "${alicloud_ecs_launch_template.default3.id}",
+					"launch_template_version": "Default",
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheck(map[string]string{
+						"warm_pool.#":    "0",
+						"scaling_policy": "release",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitAliCloudEssWarmPoolHelpers(t *testing.T) {
+	activity := ess.ScalingActivity{ScalingActivityId: "asa-1"}
+	activity.CreatedInstances.CreatedInstance = []string{"i-3", "i-4"}
+	assert.Equal(t, []string{"i-3", "i-4"}, essCreatedInstanceIds(activity))
+	assert.Equal(t, []string{}, essCreatedInstanceIds(nil))
+
+	group := map[string]interface{}{
+		"PendingCapacity":  json.Number("0"),
+		"RemovingCapacity": json.Number("0"),
+		"TotalCapacity":    json.Number("5"),
+		"StandbyCapacity":  json.Number("2"),
+		"DesiredCapacity":  json.Number("3"),
+	}
+	assert.Equal(t, "Settled", essScalingGroupCapacityState(group, 2))
+	assert.Equal(t, "Scaling", essScalingGroupCapacityState(group, 3))
+	group["DesiredCapacity"] = json.Number("5")
+	assert.Equal(t, "Scaling", essScalingGroupCapacityState(group, 2))
+	delete(group, "DesiredCapacity")
+	group["PendingCapacity"] = json.Number("1")
+	assert.Equal(t, "Scaling", essScalingGroupCapacityState(group, 2))
+	group["PendingCapacity"] = json.Number("0")
+	assert.Equal(t, "Settled", essScalingGroupCapacityState(group, 2))
+
+	assert.Equal(t, "", essWarmPoolInstanceState(map[string]string{}))
+	assert.Equal(t, "Stopped", essWarmPoolInstanceState(map[string]string{"i-1": "Stopped", "i-2": "Stopped"}))
+	assert.Equal(t, "", essWarmPoolInstanceState(map[string]string{"i-1": "Stopped", "i-2": "Running"}))
+
+	assert.Nil(t, flattenEssWarmPool(nil, map[string]string{}, "release"))
+	assert.Equal(t, []map[string]interface{}{
+		{"size": 2, "min_size": 0, "instance_state": "Running", "reuse_on_scale_in": true, "current_size": 2},
+	}, flattenEssWarmPool(nil, map[string]string{"i-1": "Running", "i-2": "Running"}, "recycle"))
+	assert.Equal(t, []map[string]interface{}{
+		{"size": 2, "min_size": 0, "instance_state": "Stopped", "reuse_on_scale_in": false, "current_size": 2},
+	}, flattenEssWarmPool(nil, map[string]string{"i-1": "Stopped", "i-2": "Running"}, "release"))
+	configured := []interface{}{map[string]interface{}{"size": 3, "min_size": 1, "instance_state": "Running", "reuse_on_scale_in": false}}
+	assert.Equal(t, []map[string]interface{}{
+		{"size": 0, "min_size": 1, "instance_state": "Running", "reuse_on_scale_in": false, "current_size": 0},
+	}, flattenEssWarmPool(configured, map[string]string{}, "release"))
+}
+
 func TestAccAliClouddEssScalingGroup_withLaunchTemplateOverride(t *testing.T) {
 	rand := acctest.RandIntRange(10000, 999999)
 	var v ess.ScalingGroup
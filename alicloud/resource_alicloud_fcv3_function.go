@@ -14,10 +14,11 @@ import (
 
 func resourceAliCloudFcv3Function() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceAliCloudFcv3FunctionCreate,
-		Read:   resourceAliCloudFcv3FunctionRead,
-		Update: resourceAliCloudFcv3FunctionUpdate,
-		Delete: resourceAliCloudFcv3FunctionDelete,
+		Create:        resourceAliCloudFcv3FunctionCreate,
+		Read:          resourceAliCloudFcv3FunctionRead,
+		Update:        resourceAliCloudFcv3FunctionUpdate,
+		Delete:        resourceAliCloudFcv3FunctionDelete,
+		CustomizeDiff: resourceAliCloudFcv3FunctionCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -50,9 +51,23 @@ func resourceAliCloudFcv3Function() *schema.Resource {
 							Type:     schema.TypeString,
 							Optional: true,
 						},
+						"source_dir": {
+							Type:          schema.TypeString,
+							Optional:      true,
+							ConflictsWith: []string{"code.0.zip_file", "code.0.oss_object_name", "code.0.checksum"},
+						},
+						"excludes": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
 					},
 				},
 			},
+			"code_checksum": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"code_size": {
 				Type:     schema.TypeInt,
 				Computed: true,
@@ -908,6 +923,16 @@ func resourceAliCloudFcv3FunctionCreate(d *schema.ResourceData, meta interface{}
 		if checksum1 != nil && checksum1 != "" {
 			code["checksum"] = checksum1
 		}
+		sourceDir1, _ := jsonpath.Get("$[0].source_dir", v)
+		if sourceDir1 != nil && sourceDir1 != "" {
+			fcv3ServiceV2 := Fcv3ServiceV2{client}
+			sourceCode, cleanup, err := fcv3ServiceV2.ExpandFcv3SourceCode(v)
+			if err != nil {
+				return WrapError(err)
+			}
+			defer cleanup()
+			code = sourceCode
+		}
 
 		request["code"] = code
 	}
@@ -1008,6 +1033,7 @@ func resourceAliCloudFcv3FunctionRead(d *schema.ResourceData, meta interface{})
 		return WrapError(err)
 	}
 
+	d.Set("code_checksum", objectRaw["codeChecksum"])
 	d.Set("code_size", objectRaw["codeSize"])
 	d.Set("cpu", objectRaw["cpu"])
 	d.Set("create_time", objectRaw["createdTime"])
@@ -1790,7 +1816,7 @@ func resourceAliCloudFcv3FunctionUpdate(d *schema.ResourceData, meta interface{}
 		request["environmentVariables"] = d.Get("environment_variables")
 	}
 
-	if !d.IsNewResource() && d.HasChange("code") {
+	if !d.IsNewResource() && (d.HasChange("code") || d.HasChange("code_checksum")) {
 		update = true
 		code := make(map[string]interface{})
 
@@ -1811,6 +1837,16 @@ func resourceAliCloudFcv3FunctionUpdate(d *schema.ResourceData, meta interface{}
 			if checksum1 != nil && checksum1 != "" {
 				code["checksum"] = checksum1
 			}
+			sourceDir1, _ := jsonpath.Get("$[0].source_dir", v)
+			if sourceDir1 != nil && sourceDir1 != "" {
+				fcv3ServiceV2 := Fcv3ServiceV2{client}
+				sourceCode, cleanup, err := fcv3ServiceV2.ExpandFcv3SourceCode(v)
+				if err != nil {
+					return WrapError(err)
+				}
+				defer cleanup()
+				code = sourceCode
+			}
 
 			request["code"] = code
 		}
@@ -1958,6 +1994,28 @@ func resourceAliCloudFcv3FunctionUpdate(d *schema.ResourceData, meta interface{}
 	return resourceAliCloudFcv3FunctionRead(d, meta)
 }
 
+// resourceAliCloudFcv3FunctionCustomizeDiff plans a code update when the package built from code.source_dir
+// differs from the deployed code, which is only visible through code_checksum.
+func resourceAliCloudFcv3FunctionCustomizeDiff(diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown("code.0.source_dir") || !diff.NewValueKnown("code.0.excludes") {
+		return diff.SetNewComputed("code_checksum")
+	}
+	checksum, err := Fcv3CodeChecksum(diff.Get("code"))
+	if err != nil {
+		return WrapError(err)
+	}
+	if checksum == "" {
+		if diff.Id() != "" && diff.HasChange("code") {
+			return diff.SetNewComputed("code_checksum")
+		}
+		return nil
+	}
+	if oldChecksum, _ := diff.GetChange("code_checksum"); fmt.Sprint(oldChecksum) != checksum {
+		return diff.SetNew("code_checksum", checksum)
+	}
+	return nil
+}
+
 func resourceAliCloudFcv3FunctionDelete(d *schema.ResourceData, meta interface{}) error {
 
 	client := meta.(*connectivity.AliyunClient)
@@ -1,12 +1,21 @@
 package alicloud
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"hash/crc64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/assert"
 )
 
 var AliCloudFc3FunctionMap6916 = map[string]string{
@@ -2064,3 +2073,136 @@ data "alicloud_resource_manager_resource_groups" "default" {
 }
 
 // Test Fcv3 Function. <<< Resource test cases, automatically generated.
+
+func TestAccAliCloudFcv3Function_sourceDir(t *testing.T) {
+	sourceDir, err := ioutil.TempDir("", "tf-fcv3-source")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(sourceDir)
+	writeOssObjectsSyncTestFiles(t, sourceDir, map[string]string{
+		"index.py":      "def handler(event, context):\n    return 'v1'\n",
+		"tests/test.py": "",
+	})
+
+	var v map[string]interface{}
+	resourceId := "alicloud_fcv3_function.default"
+	ra := resourceAttrInit(resourceId, AliCloudFcv3FunctionMap6895)
+	rc := resourceCheckInitWithDescribeMethod(resourceId, &v, func() interface{} {
+		return &Fcv3ServiceV2{testAccProvider.Meta().(*connectivity.AliyunClient)}
+	}, "DescribeFcv3Function")
+	rac := resourceAttrCheckInit(rc, ra)
+	testAccCheck := rac.resourceAttrMapUpdateSet()
+	rand := acctest.RandIntRange(0, 9999999)
+	name := fmt.Sprintf("tf-testacc%sfc3function%d", defaultRegionToTest, rand)
+	testAccConfig := resourceTestAccConfigFunc(resourceId, name, AliCloudFcv3FunctionBasicDependence6895)
+	_, checksum, err := buildFcv3CodePackage(sourceDir, []string{"tests/**"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		IDRefreshName: resourceId,
+		Providers:     testAccProviders,
+		CheckDestroy:  rac.checkResourceDestroy(),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConfig(map[string]interface{}{
+					"function_name": name,
+					"memory_size":   "512",
+					"runtime":       "python3.9",
+					"handler":       "index.handler",
+					"code": []map[string]interface{}{
+						{
+							"source_dir": sourceDir,
+							"excludes":   []string{"tests/**"},
+						},
+					},
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheck(map[string]string{
+						"function_name": name,
+						"code_checksum": checksum,
+					}),
+				),
+			},
+			{
+				PreConfig: func() {
+					writeOssObjectsSyncTestFiles(t, sourceDir, map[string]string{
+						"index.py": "def handler(event, context):\n    return 'v2'\n",
+					})
+				},
+				Config: testAccConfig(map[string]interface{}{
+					"description": "source_dir",
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheck(map[string]string{
+						"description":   "source_dir",
+						"code_checksum": CHECKSET,
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitAliCloudFcv3CodePackage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tf-fcv3-source")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	writeOssObjectsSyncTestFiles(t, dir, map[string]string{
+		"index.py":        "print('hello')",
+		"bootstrap":       "#!/bin/sh",
+		"lib/util.py":     "pass",
+		"tests/test.py":   "pass",
+		"lib/cache/a.pyc": "",
+	})
+	assert.Nil(t, os.Chmod(filepath.Join(dir, "bootstrap"), 0700))
+
+	pkg, checksum, err := buildFcv3CodePackage(dir, []string{"tests/**", "**/*.pyc"})
+	assert.Nil(t, err)
+	assert.Equal(t, fmt.Sprintf("%d", crc64.Checksum(pkg, crc64.MakeTable(crc64.ECMA))), checksum)
+
+	// FC reported the codeChecksum 7715138221701825864 for this package in TestAccAliCloudFcv2Function_basic3393
+	fcPackage, err := base64.StdEncoding.DecodeString("UEsDBAoAAAAAAOt8v1YAAAAAAAAAAAAAAAAFABwAY29kZS9VVAkAA6r5dmTE+XZkdXgLAAEE9gEAAAQUAAAAUEsDBBQAAAAIAOt8v1Y14c8e4QAAAH8BAAANABwAY29kZS9pbmRleC5qc1VUCQADqvl2ZKv5dmR1eAsAAQT2AQAABBQAAAB1kFFOwzAMht9zCr+lnapGPDAQ1XYK3lGamiXCjavGYQO0i3AW7sQVSDU0oU28Wf4/+7Osc0JIMgcnulNmpR4ZMNqeEMQjhBgkWArvOMMzWskzQuVFpvRgjEea2hK+5dg6Hs3ALo8Y5WlAsYHMze36/m7dehmpVhOhLaowlmKBrtfn6CRwBJugR+L999enwsPEs6T2L7iBynEUPEgDzhL11r3UsNnChwIoSWLClnhX6fNUiDtdd0v8y1cxEzWgl+6xUytzNnkbBzpZ8LXc2cD/sgtd+Qcx7Hmm4WS79l0gxx9QSwECHgMKAAAAAADrfL9WAAAAAAAAAAAAAAAABQAYAAAAAAAAABAA7UEAAAAAY29kZS9VVAUAA6r5dmR1eAsAAQT2AQAABBQAAABQSwECHgMUAAAACADrfL9WNeHPHuEAAAB/AQAADQAYAAAAAAABAAAApIE/AAAAY29kZS9pbmRleC5qc1VUBQADqvl2ZHV4CwABBPYBAAAEFAAAAFBLBQYAAAAAAgACAJ4AAABnAQAAAAA=")
+	assert.Nil(t, err)
+	assert.Equal(t, "7715138221701825864", fcv3CodeChecksum(fcPackage))
+
+	reader, err := zip.NewReader(bytes.NewReader(pkg), int64(len(pkg)))
+	assert.Nil(t, err)
+	names := make([]string, 0)
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+		if file.Name == "bootstrap" {
+			assert.Equal(t, os.FileMode(0755), file.Mode())
+		} else {
+			assert.Equal(t, os.FileMode(0644), file.Mode())
+		}
+	}
+	assert.Equal(t, []string{"bootstrap", "index.py", "lib/util.py"}, names)
+
+	// touching the files does not change the package, changing their content does
+	later := time.Now().Add(time.Hour)
+	assert.Nil(t, os.Chtimes(filepath.Join(dir, "index.py"), later, later))
+	_, again, err := buildFcv3CodePackage(dir, []string{"tests/**", "**/*.pyc"})
+	assert.Nil(t, err)
+	assert.Equal(t, checksum, again)
+	writeOssObjectsSyncTestFiles(t, dir, map[string]string{"index.py": "print('bye')"})
+	_, changed, err := buildFcv3CodePackage(dir, []string{"tests/**", "**/*.pyc"})
+	assert.Nil(t, err)
+	assert.NotEqual(t, checksum, changed)
+
+	checksum, err = Fcv3CodeChecksum([]interface{}{map[string]interface{}{"source_dir": dir, "excludes": []interface{}{"tests/**", "**/*.pyc"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, changed, checksum)
+	checksum, err = Fcv3CodeChecksum([]interface{}{map[string]interface{}{"zip_file": "UEsDBA=="}})
+	assert.Nil(t, err)
+	assert.Equal(t, "", checksum)
+
+	_, _, err = buildFcv3CodePackage(filepath.Join(dir, "tests", "missing"), nil)
+	assert.NotNil(t, err)
+	_, _, err = buildFcv3CodePackage(dir, []string{"**"})
+	assert.NotNil(t, err)
+}
@@ -15,10 +15,11 @@ import (
 
 func resourceAliCloudFcv3LayerVersion() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceAliCloudFcv3LayerVersionCreate,
-		Read:   resourceAliCloudFcv3LayerVersionRead,
-		Update: resourceAliCloudFcv3LayerVersionUpdate,
-		Delete: resourceAliCloudFcv3LayerVersionDelete,
+		Create:        resourceAliCloudFcv3LayerVersionCreate,
+		Read:          resourceAliCloudFcv3LayerVersionRead,
+		Update:        resourceAliCloudFcv3LayerVersionUpdate,
+		Delete:        resourceAliCloudFcv3LayerVersionDelete,
+		CustomizeDiff: resourceAliCloudFcv3LayerVersionCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -60,9 +61,25 @@ func resourceAliCloudFcv3LayerVersion() *schema.Resource {
 							Optional: true,
 							ForceNew: true,
 						},
+						"source_dir": {
+							Type:          schema.TypeString,
+							Optional:      true,
+							ForceNew:      true,
+							ConflictsWith: []string{"code.0.zip_file", "code.0.oss_object_name", "code.0.checksum"},
+						},
+						"excludes": {
+							Type:     schema.TypeList,
+							Optional: true,
+							ForceNew: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
 					},
 				},
 			},
+			"code_checksum": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"code_size": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -142,6 +159,16 @@ func resourceAliCloudFcv3LayerVersionCreate(d *schema.ResourceData, meta interfa
 		if zipFile1 != nil && zipFile1 != "" {
 			objectDataLocalMap["zipFile"] = zipFile1
 		}
+		sourceDir1, _ := jsonpath.Get("$[0].source_dir", d.Get("code"))
+		if sourceDir1 != nil && sourceDir1 != "" {
+			fcv3ServiceV2 := Fcv3ServiceV2{client}
+			sourceCode, cleanup, err := fcv3ServiceV2.ExpandFcv3SourceCode(v)
+			if err != nil {
+				return WrapError(err)
+			}
+			defer cleanup()
+			objectDataLocalMap = sourceCode
+		}
 
 		request["code"] = objectDataLocalMap
 	}
@@ -198,6 +225,9 @@ func resourceAliCloudFcv3LayerVersionRead(d *schema.ResourceData, meta interface
 	if objectRaw["acl"] != nil {
 		d.Set("acl", objectRaw["acl"])
 	}
+	if objectRaw["codeChecksum"] != nil {
+		d.Set("code_checksum", objectRaw["codeChecksum"])
+	}
 	if objectRaw["codeSize"] != nil {
 		d.Set("code_size", objectRaw["codeSize"])
 	}
@@ -282,6 +312,30 @@ func resourceAliCloudFcv3LayerVersionUpdate(d *schema.ResourceData, meta interfa
 	return resourceAliCloudFcv3LayerVersionRead(d, meta)
 }
 
+// resourceAliCloudFcv3LayerVersionCustomizeDiff publishes a new layer version when the package built from
+// code.source_dir differs from the code of the current one.
+func resourceAliCloudFcv3LayerVersionCustomizeDiff(diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown("code.0.source_dir") || !diff.NewValueKnown("code.0.excludes") {
+		return diff.SetNewComputed("code_checksum")
+	}
+	checksum, err := Fcv3CodeChecksum(diff.Get("code"))
+	if err != nil {
+		return WrapError(err)
+	}
+	if checksum == "" {
+		return nil
+	}
+	if oldChecksum, _ := diff.GetChange("code_checksum"); fmt.Sprint(oldChecksum) != checksum {
+		if err := diff.SetNew("code_checksum", checksum); err != nil {
+			return WrapError(err)
+		}
+		if diff.Id() != "" {
+			return diff.ForceNew("code_checksum")
+		}
+	}
+	return nil
+}
+
 func resourceAliCloudFcv3LayerVersionDelete(d *schema.ResourceData, meta interface{}) error {
 
 	client := meta.(*connectivity.AliyunClient)
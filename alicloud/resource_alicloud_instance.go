@@ -24,7 +24,7 @@ func resourceAliCloudInstance() *schema.Resource {
 		Update: resourceAliCloudInstanceUpdate,
 		Delete: resourceAliCloudInstanceDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByLookup("alicloud_instance", instanceImportLookup),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
@@ -0,0 +1,271 @@
+package alicloud
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"hash"
+	"log"
+	"time"
+
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func resourceAliCloudKmsKeyMaterial() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAliCloudKmsKeyMaterialCreate,
+		Read:   resourceAliCloudKmsKeyMaterialRead,
+		Delete: resourceAliCloudKmsKeyMaterialDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"key_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"key_material": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Sensitive:    true,
+				ExactlyOneOf: []string{"key_material", "key_material_pem", "encrypted_key_material"},
+			},
+			"key_material_pem": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Sensitive:    true,
+				ExactlyOneOf: []string{"key_material", "key_material_pem", "encrypted_key_material"},
+			},
+			"encrypted_key_material": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Sensitive:    true,
+				ExactlyOneOf: []string{"key_material", "key_material_pem", "encrypted_key_material"},
+				RequiredWith: []string{"import_token"},
+			},
+			"import_token": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Sensitive:    true,
+				RequiredWith: []string{"encrypted_key_material"},
+			},
+			"wrapping_algorithm": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "RSAES_OAEP_SHA_256",
+				ValidateFunc: StringInSlice([]string{"RSAES_OAEP_SHA_256", "RSAES_OAEP_SHA_1"}, false),
+			},
+			"wrapping_key_spec": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "RSA_2048",
+				ValidateFunc: StringInSlice([]string{"RSA_2048"}, false),
+			},
+			"expire_time": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: ValidateRFC3339TimeString(true),
+			},
+			"material_expire_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"key_state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceAliCloudKmsKeyMaterialCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	kmsServiceV2 := KmsServiceV2{client}
+	keyId := d.Get("key_id").(string)
+
+	var encryptedKeyMaterial, importToken string
+	if v, ok := d.GetOk("encrypted_key_material"); ok {
+		encryptedKeyMaterial = v.(string)
+		importToken = d.Get("import_token").(string)
+	} else {
+		material, err := kmsKeyMaterialBytes(d)
+		if err != nil {
+			return WrapError(err)
+		}
+		parameters, err := kmsServiceV2.GetKmsParametersForImport(keyId, d.Get("wrapping_algorithm").(string), d.Get("wrapping_key_spec").(string), d.Timeout(schema.TimeoutCreate))
+		if err != nil {
+			return WrapError(err)
+		}
+		encryptedKeyMaterial, err = kmsWrapKeyMaterial(fmt.Sprint(parameters["PublicKey"]), d.Get("wrapping_algorithm").(string), material)
+		if err != nil {
+			return WrapError(err)
+		}
+		importToken = fmt.Sprint(parameters["ImportToken"])
+	}
+
+	action := "ImportKeyMaterial"
+	var response map[string]interface{}
+	request := make(map[string]interface{})
+	query := make(map[string]interface{})
+	request["KeyId"] = keyId
+	request["EncryptedKeyMaterial"] = encryptedKeyMaterial
+	request["ImportToken"] = importToken
+	request["KeyMaterialExpireUnixTime"] = 0
+	if v, ok := d.GetOk("expire_time"); ok {
+		expireTime, err := time.Parse(time.RFC3339, v.(string))
+		if err != nil {
+			return WrapError(err)
+		}
+		request["KeyMaterialExpireUnixTime"] = expireTime.Unix()
+	}
+	var err error
+	wait := incrementalWait(3*time.Second, 5*time.Second)
+	err = resource.Retry(client.GetRetryTimeout(d.Timeout(schema.TimeoutCreate)), func() *resource.RetryError {
+		response, err = client.RpcPost("Kms", "2016-01-20", action, query, request, true)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	// The request carries the wrapped material and the import token, so only the response is logged.
+	addDebug(action, response, map[string]interface{}{"KeyId": keyId})
+	if err != nil {
+		return WrapErrorf(err, DefaultErrorMsg, "alicloud_kms_key_material", action, AlibabaCloudSdkGoERROR)
+	}
+
+	d.SetId(keyId)
+
+	stateConf := BuildTracedStateConf(client, []string{}, []string{"Enabled"}, d.Timeout(schema.TimeoutCreate), 5*time.Second, kmsServiceV2.KmsKeyStateRefreshFunc(d.Id(), "KeyState", []string{}))
+	if _, err := stateConf.WaitForState(); err != nil {
+		return WrapErrorf(err, IdMsg, d.Id())
+	}
+
+	return resourceAliCloudKmsKeyMaterialRead(d, meta)
+}
+
+func resourceAliCloudKmsKeyMaterialRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	kmsServiceV2 := KmsServiceV2{client}
+
+	objectRaw, err := kmsServiceV2.DescribeKmsKeyMaterial(d.Id())
+	if err != nil {
+		if !d.IsNewResource() && NotFoundError(err) {
+			log.Printf("[DEBUG] Resource alicloud_kms_key_material DescribeKmsKeyMaterial Failed!!! %s", err)
+			d.SetId("")
+			return nil
+		}
+		return WrapError(err)
+	}
+
+	d.Set("key_id", objectRaw["KeyId"])
+	d.Set("key_state", objectRaw["KeyState"])
+	d.Set("material_expire_time", objectRaw["MaterialExpireTime"])
+
+	return nil
+}
+
+func resourceAliCloudKmsKeyMaterialDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	kmsServiceV2 := KmsServiceV2{client}
+	action := "DeleteKeyMaterial"
+	var request map[string]interface{}
+	var response map[string]interface{}
+	query := make(map[string]interface{})
+	var err error
+	request = make(map[string]interface{})
+	request["KeyId"] = d.Id()
+
+	wait := incrementalWait(3*time.Second, 5*time.Second)
+	err = resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		response, err = client.RpcPost("Kms", "2016-01-20", action, query, request, true)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug(action, response, request)
+	if err != nil {
+		if IsExpectedErrors(err, []string{"Forbidden.KeyNotFound"}) || NotFoundError(err) {
+			return nil
+		}
+		return WrapErrorf(err, DefaultErrorMsg, d.Id(), action, AlibabaCloudSdkGoERROR)
+	}
+
+	stateConf := BuildTracedStateConf(client, []string{}, []string{"PendingImport", ""}, d.Timeout(schema.TimeoutDelete), 5*time.Second, kmsServiceV2.KmsKeyStateRefreshFunc(d.Id(), "KeyState", []string{}))
+	if _, err := stateConf.WaitForState(); err != nil {
+		return WrapErrorf(err, IdMsg, d.Id())
+	}
+	return nil
+}
+
+// kmsKeyMaterialBytes returns the plaintext key material of the resource, either base64 encoded
+// in key_material or the DER body of the PEM block in key_material_pem.
+func kmsKeyMaterialBytes(d *schema.ResourceData) ([]byte, error) {
+	if v, ok := d.GetOk("key_material_pem"); ok {
+		block, _ := pem.Decode([]byte(v.(string)))
+		if block == nil {
+			return nil, fmt.Errorf("key_material_pem does not contain a PEM block")
+		}
+		return block.Bytes, nil
+	}
+	material, err := base64.StdEncoding.DecodeString(d.Get("key_material").(string))
+	if err != nil {
+		return nil, fmt.Errorf("key_material is not valid base64: %v", err)
+	}
+	return material, nil
+}
+
+// kmsWrapKeyMaterial encrypts the key material with the public key returned by
+// GetParametersForImport, as RSAES-OAEP with the hash named by the wrapping algorithm.
+func kmsWrapKeyMaterial(publicKeyBase64, wrappingAlgorithm string, material []byte) (string, error) {
+	der, err := base64.StdEncoding.DecodeString(publicKeyBase64)
+	if err != nil {
+		return "", fmt.Errorf("decoding the wrapping public key failed: %v", err)
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return "", fmt.Errorf("parsing the wrapping public key failed: %v", err)
+	}
+	rsaPublicKey, ok := publicKey.(*rsa.PublicKey)
+	if !ok {
+		return "", fmt.Errorf("the wrapping public key is a %T, expected an RSA public key", publicKey)
+	}
+	var h hash.Hash
+	switch wrappingAlgorithm {
+	case "RSAES_OAEP_SHA_256":
+		h = sha256.New()
+	case "RSAES_OAEP_SHA_1":
+		h = sha1.New()
+	default:
+		return "", fmt.Errorf("unsupported wrapping algorithm %s", wrappingAlgorithm)
+	}
+	encrypted, err := rsa.EncryptOAEP(h, rand.Reader, rsaPublicKey, material, nil)
+	if err != nil {
+		return "", fmt.Errorf("wrapping the key material failed: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
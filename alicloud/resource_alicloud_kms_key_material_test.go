@@ -0,0 +1,105 @@
+package alicloud
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccAliCloudKmsKeyMaterial_basic(t *testing.T) {
+	var v map[string]interface{}
+	resourceId := "alicloud_kms_key_material.default"
+	ra := resourceAttrInit(resourceId, AliCloudKmsKeyMaterialMap)
+	rc := resourceCheckInitWithDescribeMethod(resourceId, &v, func() interface{} {
+		return &KmsServiceV2{testAccProvider.Meta().(*connectivity.AliyunClient)}
+	}, "DescribeKmsKeyMaterial")
+	rac := resourceAttrCheckInit(rc, ra)
+	testAccCheck := rac.resourceAttrMapUpdateSet()
+	rand := acctest.RandIntRange(10000, 99999)
+	name := fmt.Sprintf("tf-testacc%skmskeymaterial%d", defaultRegionToTest, rand)
+	testAccConfig := resourceTestAccConfigFunc(resourceId, name, AliCloudKmsKeyMaterialBasicDependence)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		IDRefreshName: resourceId,
+		Providers:     testAccProviders,
+		CheckDestroy:  rac.checkResourceDestroy(),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConfig(map[string]interface{}{
+					"key_id":       "${alicloud_kms_key.default.id}",
+					"key_material": "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+					"expire_time":  "2099-01-01T00:00:00Z",
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheck(map[string]string{
+						"key_id":               CHECKSET,
+						"key_state":            "Enabled",
+						"material_expire_time": CHECKSET,
+					}),
+				),
+			},
+		},
+	})
+}
+
+var AliCloudKmsKeyMaterialMap = map[string]string{
+	"key_state":          CHECKSET,
+	"wrapping_algorithm": "RSAES_OAEP_SHA_256",
+	"wrapping_key_spec":  "RSA_2048",
+}
+
+func AliCloudKmsKeyMaterialBasicDependence(name string) string {
+	return fmt.Sprintf(`
+variable "name" {
+  default = "%s"
+}
+
+resource "alicloud_kms_key" "default" {
+  description            = var.name
+  origin                 = "EXTERNAL"
+  pending_window_in_days = 7
+}
+`, name)
+}
+
+func TestUnitAliCloudKmsKeyMaterialWrap(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	assert.Nil(t, err)
+	publicKey := base64.StdEncoding.EncodeToString(der)
+	material := []byte("0123456789abcdef0123456789abcdef")
+
+	wrapped, err := kmsWrapKeyMaterial(publicKey, "RSAES_OAEP_SHA_256", material)
+	assert.Nil(t, err)
+	ciphertext, err := base64.StdEncoding.DecodeString(wrapped)
+	assert.Nil(t, err)
+	plaintext, err := rsa.DecryptOAEP(sha256.New(), nil, privateKey, ciphertext, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, material, plaintext)
+
+	wrapped, err = kmsWrapKeyMaterial(publicKey, "RSAES_OAEP_SHA_1", material)
+	assert.Nil(t, err)
+	ciphertext, err = base64.StdEncoding.DecodeString(wrapped)
+	assert.Nil(t, err)
+	plaintext, err = rsa.DecryptOAEP(sha1.New(), nil, privateKey, ciphertext, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, material, plaintext)
+
+	_, err = kmsWrapKeyMaterial(publicKey, "RSAES_PKCS1_V1_5", material)
+	assert.NotNil(t, err)
+	_, err = kmsWrapKeyMaterial("not base64", "RSAES_OAEP_SHA_256", material)
+	assert.NotNil(t, err)
+}
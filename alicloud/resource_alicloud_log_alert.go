@@ -20,6 +20,7 @@ func resourceAlicloudLogAlert() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: resourceAlicloudLogAlertCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"version": {
@@ -999,3 +1000,38 @@ func createAlert2Config(d *schema.ResourceData) *sls.AlertConfiguration {
 	}
 	return config
 }
+
+// resourceAlicloudLogAlertCustomizeDiff checks the syntax of log queries at plan time, and the fields they reference
+// against the index of their logstore when it already exists. Queries on metric and meta stores are not checked.
+func resourceAlicloudLogAlertCustomizeDiff(diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() != "" && !diff.HasChange("query_list") {
+		return nil
+	}
+	client, _ := meta.(*connectivity.AliyunClient)
+	for i := range diff.Get("query_list").([]interface{}) {
+		prefix := fmt.Sprintf("query_list.%d", i)
+		if !diff.NewValueKnown(prefix + ".query") {
+			continue
+		}
+		if storeType := diff.Get(prefix + ".store_type").(string); storeType != "" && storeType != "log" {
+			continue
+		}
+		project, store := "", ""
+		if diff.NewValueKnown(prefix+".project") && diff.NewValueKnown("project_name") {
+			project = diff.Get(prefix + ".project").(string)
+			if project == "" {
+				project = diff.Get("project_name").(string)
+			}
+		}
+		if diff.NewValueKnown(prefix+".store") && diff.NewValueKnown(prefix+".logstore") {
+			store = diff.Get(prefix + ".store").(string)
+			if store == "" {
+				store = diff.Get(prefix + ".logstore").(string)
+			}
+		}
+		if err := checkSlsQuery(client, prefix+".query", diff.Get(prefix+".query").(string), false, project, store); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,7 +1,6 @@
 package alicloud
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -42,8 +41,15 @@ func resourceAlicloudLogDashboard() *schema.Resource {
 			"char_list": {
 				Type:             schema.TypeString,
 				Required:         true,
-				ValidateFunc:     validation.StringIsJSON,
+				ValidateFunc:     validateLogDashboardChartList,
 				DiffSuppressFunc: chartListDiffSuppress,
+				StateFunc: func(v interface{}) string {
+					normalized, err := normalizeLogDashboardChartList(v.(string))
+					if err != nil {
+						return v.(string)
+					}
+					return normalized
+				},
 			},
 			"attribute": {
 				Type:         schema.TypeString,
@@ -142,14 +148,14 @@ func resourceAlicloudLogDashboardRead(d *schema.ResourceData, meta interface{})
 		}
 	}
 
-	for k, v := range dashboard["charts"].([]interface{}) {
-		if action, actionOK := v.(map[string]interface{})["action"]; actionOK {
-			if action == nil {
-				delete((dashboard["charts"].([]interface{})[k]).(map[string]interface{}), "action")
-			}
-		}
+	charts := dashboard["charts"]
+	// the service fills in defaults for every chart, keep only the keys that were configured so that they do not show up
+	// as a difference. An imported dashboard has nothing configured yet and keeps all of them.
+	configured := make([]interface{}, 0)
+	if err := json.Unmarshal([]byte(d.Get("char_list").(string)), &configured); err == nil && len(configured) > 0 {
+		charts = projectLogDashboardCharts(charts, configured)
 	}
-	charlist, err := json.Marshal(dashboard["charts"])
+	charlist, err := json.Marshal(stripLogDashboardNulls(charts))
 	if err != nil {
 		return WrapError(err)
 	}
@@ -248,24 +254,187 @@ func chartListDiffSuppress(k, old, new string, d *schema.ResourceData) bool {
 		return true
 	}
 
-	obj1 := []map[string]interface{}{}
-	err := json.Unmarshal([]byte(old), &obj1)
+	canonicalJson1, err := normalizeLogDashboardChartList(old)
 	if err != nil {
 		return false
 	}
-	canonicalJson1, _ := json.Marshal(obj1)
-
-	obj2 := []map[string]interface{}{}
-	err = json.Unmarshal([]byte(new), &obj2)
+	canonicalJson2, err := normalizeLogDashboardChartList(new)
 	if err != nil {
 		return false
 	}
-	canonicalJson2, _ := json.Marshal(obj2)
 
-	equal := bytes.Equal(canonicalJson1, canonicalJson2)
+	equal := canonicalJson1 == canonicalJson2
 	if !equal {
 		log.Printf("[DEBUG] Canonical template are not equal.\nFirst: %s\nSecond: %s\n",
 			canonicalJson1, canonicalJson2)
 	}
 	return equal
 }
+
+// normalizeLogDashboardChartList renders a chart list with sorted keys and without null values, which is the form that
+// is kept in the state.
+func normalizeLogDashboardChartList(chartList string) (string, error) {
+	charts := make([]interface{}, 0)
+	if err := json.Unmarshal([]byte(chartList), &charts); err != nil {
+		return "", err
+	}
+	normalized, err := json.Marshal(stripLogDashboardNulls(charts))
+	if err != nil {
+		return "", err
+	}
+	return string(normalized), nil
+}
+
+func stripLogDashboardNulls(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			if item != nil {
+				result[key] = stripLogDashboardNulls(item)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			result = append(result, stripLogDashboardNulls(item))
+		}
+		return result
+	}
+	return v
+}
+
+// projectLogDashboardCharts reshapes the charts returned by the service after the configured ones. Charts are matched by
+// title and put in the configured order, charts that are not configured are appended, and inside a chart only the
+// configured keys are kept.
+func projectLogDashboardCharts(charts interface{}, configured []interface{}) interface{} {
+	remote, ok := charts.([]interface{})
+	if !ok {
+		return charts
+	}
+	byTitle := make(map[string]interface{}, len(remote))
+	for _, chart := range remote {
+		if c, ok := chart.(map[string]interface{}); ok {
+			if title, ok := c["title"].(string); ok {
+				byTitle[title] = chart
+			}
+		}
+	}
+	result := make([]interface{}, 0, len(remote))
+	matched := make(map[string]bool)
+	for _, reference := range configured {
+		ref, _ := reference.(map[string]interface{})
+		title, _ := ref["title"].(string)
+		if chart, ok := byTitle[title]; ok && !matched[title] {
+			result = append(result, projectLogDashboardValue(chart, reference))
+			matched[title] = true
+		}
+	}
+	for _, chart := range remote {
+		if c, ok := chart.(map[string]interface{}); ok {
+			if title, ok := c["title"].(string); ok && matched[title] {
+				continue
+			}
+		}
+		result = append(result, chart)
+	}
+	return result
+}
+
+func projectLogDashboardValue(remote interface{}, reference interface{}) interface{} {
+	switch ref := reference.(type) {
+	case map[string]interface{}:
+		value, ok := remote.(map[string]interface{})
+		if !ok {
+			return remote
+		}
+		result := make(map[string]interface{}, len(ref))
+		for key, item := range ref {
+			if v, ok := value[key]; ok {
+				result[key] = projectLogDashboardValue(v, item)
+			}
+		}
+		return result
+	case []interface{}:
+		value, ok := remote.([]interface{})
+		if !ok {
+			return remote
+		}
+		result := make([]interface{}, 0, len(value))
+		for i, item := range value {
+			if i < len(ref) {
+				item = projectLogDashboardValue(item, ref[i])
+			}
+			result = append(result, item)
+		}
+		return result
+	}
+	return remote
+}
+
+// logDashboardStaticCharts are the chart types that show static content and run no query, so they need no search.
+var logDashboardStaticCharts = map[string]bool{
+	"markdown": true,
+	"text":     true,
+}
+
+// validateLogDashboardChartList checks the structure that the service requires for every chart, so that a malformed
+// chart fails at plan time instead of at apply time.
+func validateLogDashboardChartList(v interface{}, k string) (ws []string, errors []error) {
+	charts := make([]interface{}, 0)
+	if err := json.Unmarshal([]byte(v.(string)), &charts); err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a JSON array of charts: %s", k, err))
+		return
+	}
+	titles := make(map[string]int)
+	for i, item := range charts {
+		chart, ok := item.(map[string]interface{})
+		if !ok {
+			errors = append(errors, fmt.Errorf("%q: chart %d must be a JSON object", k, i))
+			continue
+		}
+		title, _ := chart["title"].(string)
+		if title == "" {
+			errors = append(errors, fmt.Errorf("%q: chart %d must have a non-empty \"title\"", k, i))
+		} else if first, ok := titles[title]; ok {
+			errors = append(errors, fmt.Errorf("%q: charts %d and %d have the same title %q, titles must be unique", k, first, i, title))
+		} else {
+			titles[title] = i
+		}
+		chartType, _ := chart["type"].(string)
+		if chartType == "" {
+			errors = append(errors, fmt.Errorf("%q: chart %d must have a non-empty \"type\"", k, i))
+		}
+		search, ok := chart["search"].(map[string]interface{})
+		if logDashboardStaticCharts[chartType] {
+			if raw, set := chart["search"]; set && raw != nil && !ok {
+				errors = append(errors, fmt.Errorf("%q: \"search\" of chart %d must be a JSON object", k, i))
+			}
+		} else if !ok {
+			errors = append(errors, fmt.Errorf("%q: chart %d must have a \"search\" object", k, i))
+		} else {
+			if logstore, _ := search["logstore"].(string); logstore == "" {
+				errors = append(errors, fmt.Errorf("%q: chart %d must set \"search.logstore\"", k, i))
+			}
+			if _, ok := search["query"].(string); !ok {
+				errors = append(errors, fmt.Errorf("%q: chart %d must set \"search.query\" to a string", k, i))
+			}
+		}
+		if raw, ok := chart["display"]; ok {
+			display, ok := raw.(map[string]interface{})
+			if !ok {
+				errors = append(errors, fmt.Errorf("%q: \"display\" of chart %d must be a JSON object", k, i))
+				continue
+			}
+			for _, key := range []string{"xPos", "yPos", "width", "height"} {
+				if value, ok := display[key]; ok {
+					if number, ok := value.(float64); !ok || number < 0 {
+						errors = append(errors, fmt.Errorf("%q: \"display.%s\" of chart %d must be a non-negative number", k, key, i))
+					}
+				}
+			}
+		}
+	}
+	return
+}
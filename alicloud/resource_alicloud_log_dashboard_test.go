@@ -1,6 +1,7 @@
 package alicloud
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccAliCloudLogDashboard_basic(t *testing.T) {
@@ -132,3 +134,51 @@ func resourceLogDashboardDependence(name string) string {
 	}
 `, name)
 }
+
+func TestUnitAliCloudLogDashboardChartList(t *testing.T) {
+	configured := `[
+  {"title": "pv", "type": "line", "search": {"logstore": "access", "query": "* | select count(1) as pv", "start": "-86400s", "end": "now"},
+   "display": {"xAxis": ["t"], "yAxis": ["pv"], "xPos": 0, "yPos": 0, "width": 10, "height": 12}},
+  {"title": "uv", "type": "number", "search": {"logstore": "access", "query": "* | select approx_distinct(ip)"}}
+]`
+	_, errs := validateLogDashboardChartList(configured, "char_list")
+	assert.Empty(t, errs)
+
+	// the service reorders charts, sorts keys and adds defaults
+	remote := []interface{}{}
+	assert.Nil(t, json.Unmarshal([]byte(`[
+  {"action": null, "title": "uv", "type": "number", "search": {"logstore": "access", "query": "* | select approx_distinct(ip)", "topic": "", "timeSpanType": "custom"}},
+  {"action": {}, "display": {"height": 12, "width": 10, "xAxis": ["t"], "xPos": 0, "yAxis": ["pv"], "yPos": 0, "displayName": "pv"},
+   "search": {"end": "now", "logstore": "access", "query": "* | select count(1) as pv", "start": "-86400s", "topic": ""}, "title": "pv", "type": "line"},
+  {"title": "added in console", "type": "table", "search": {"logstore": "access", "query": "*"}}
+]`), &remote))
+	reference := []interface{}{}
+	assert.Nil(t, json.Unmarshal([]byte(configured), &reference))
+	projected, err := json.Marshal(stripLogDashboardNulls(projectLogDashboardCharts(remote, reference)))
+	assert.Nil(t, err)
+
+	normalized, err := normalizeLogDashboardChartList(configured)
+	assert.Nil(t, err)
+	expected := normalized[:len(normalized)-1] + `,{"search":{"logstore":"access","query":"*"},"title":"added in console","type":"table"}]`
+	assert.Equal(t, expected, string(projected))
+	assert.True(t, chartListDiffSuppress("char_list", normalized, configured, nil))
+	assert.False(t, chartListDiffSuppress("char_list", string(projected), configured, nil))
+
+	_, errs = validateLogDashboardChartList(`[
+  {"title": "notes", "type": "markdown", "display": {"content": "# Access"}},
+  {"title": "hint", "type": "text", "search": {}},
+  {"title": "pv", "type": "line", "search": {"logstore": "access", "query": "*"}}
+]`, "char_list")
+	assert.Empty(t, errs)
+	_, errs = validateLogDashboardChartList(`[{"title": "notes", "type": "markdown", "search": "*"}, {"title": "pv", "type": "line"}]`, "char_list")
+	assert.Len(t, errs, 2)
+
+	_, errs = validateLogDashboardChartList(`{"title": "pv"}`, "char_list")
+	assert.Len(t, errs, 1)
+	_, errs = validateLogDashboardChartList(`[
+  {"title": "pv", "type": "line", "search": {"logstore": "access", "query": "*"}, "display": {"width": "10"}},
+  {"title": "pv", "search": {"query": 1}},
+  "chart"
+]`, "char_list")
+	assert.Len(t, errs, 6)
+}
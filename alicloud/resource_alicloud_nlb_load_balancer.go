@@ -19,7 +19,7 @@ func resourceAliCloudNlbLoadBalancer() *schema.Resource {
 		Update: resourceAliCloudNlbLoadBalancerUpdate,
 		Delete: resourceAliCloudNlbLoadBalancerDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByLookup("alicloud_nlb_load_balancer", nlbLoadBalancerImportLookup),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(5 * time.Minute),
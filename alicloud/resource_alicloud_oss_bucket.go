@@ -21,7 +21,10 @@ func resourceAlicloudOssBucket() *schema.Resource {
 		Update: resourceAlicloudOssBucketUpdate,
 		Delete: resourceAlicloudOssBucketDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByLookup("alicloud_oss_bucket", ossBucketImportLookup),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -1749,45 +1752,18 @@ func resourceAlicloudOssBucketDelete(d *schema.ResourceData, meta interface{}) e
 		return nil
 	}
 
-	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutDelete))
+	err = resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		raw, err = client.WithOssClient(func(ossClient *oss.Client) (interface{}, error) {
 			return nil, ossClient.DeleteBucket(d.Id())
 		})
 		if err != nil {
-			if IsExpectedErrors(err, []string{"BucketNotEmpty"}) {
-				if d.Get("force_destroy").(bool) {
-					raw, er := client.WithOssClient(func(ossClient *oss.Client) (interface{}, error) {
-						bucket, _ := ossClient.Bucket(d.Id())
-						lor, err := bucket.ListObjectVersions()
-						if err != nil {
-							return nil, WrapErrorf(err, DefaultErrorMsg, d.Id(), "ListObjectVersions", AliyunOssGoSdk)
-						}
-						addDebug("ListObjectVersions", lor, requestInfo)
-						objectsToDelete := make([]oss.DeleteObject, 0)
-						for _, object := range lor.ObjectDeleteMarkers {
-							objectsToDelete = append(objectsToDelete, oss.DeleteObject{
-								Key:       object.Key,
-								VersionId: object.VersionId,
-							})
-						}
-
-						for _, object := range lor.ObjectVersions {
-							objectsToDelete = append(objectsToDelete, oss.DeleteObject{
-								Key:       object.Key,
-								VersionId: object.VersionId,
-							})
-						}
-						if len(objectsToDelete) == 0 {
-							return nil, nil
-						}
-						return bucket.DeleteObjectVersions(objectsToDelete)
-					})
-					if er != nil {
-						return resource.NonRetryableError(er)
-					}
-					addDebug("DeleteObjectVersions", raw, requestInfo, map[string]string{"bucketName": d.Id()})
-					return resource.RetryableError(err)
+			if IsExpectedErrors(err, []string{"BucketNotEmpty"}) && d.Get("force_destroy").(bool) {
+				log.Printf("[INFO] OSS bucket %s is not empty, force_destroy deletes its contents.", d.Id())
+				if er := ossService.EmptyOssBucket(d.Id(), time.Until(deadline)); er != nil {
+					return resource.NonRetryableError(er)
 				}
+				return resource.RetryableError(err)
 			}
 			return resource.NonRetryableError(err)
 		}
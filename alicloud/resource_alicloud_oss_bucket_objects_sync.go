@@ -0,0 +1,500 @@
+package alicloud
+
+import (
+	"fmt"
+	"hash/crc64"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/mitchellh/go-homedir"
+)
+
+func resourceAliCloudOssBucketObjectsSync() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceAliCloudOssBucketObjectsSyncCreate,
+		Read:          resourceAliCloudOssBucketObjectsSyncRead,
+		Update:        resourceAliCloudOssBucketObjectsSyncUpdate,
+		Delete:        resourceAliCloudOssBucketObjectsSyncDelete,
+		CustomizeDiff: resourceAliCloudOssBucketObjectsSyncCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"bucket": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"source_dir": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"include": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"exclude": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"extension_rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"extensions": {
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"content_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"cache_control": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"acl": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: StringInSlice([]string{"default", "private", "public-read", "public-read-write"}, false),
+			},
+			"concurrency": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      8,
+				ValidateFunc: IntBetween(1, 64),
+			},
+			"manifest": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"etags": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// ossObjectsSyncFile is a local file that is published to the bucket.
+type ossObjectsSyncFile struct {
+	Path string
+	Hash string
+}
+
+func resourceAliCloudOssBucketObjectsSyncCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(fmt.Sprintf("%s:%s", d.Get("bucket"), d.Get("prefix")))
+	if err := resourceAliCloudOssBucketObjectsSyncApply(d, meta, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return WrapError(err)
+	}
+	return resourceAliCloudOssBucketObjectsSyncRead(d, meta)
+}
+
+func resourceAliCloudOssBucketObjectsSyncUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceAliCloudOssBucketObjectsSyncApply(d, meta, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return WrapError(err)
+	}
+	return resourceAliCloudOssBucketObjectsSyncRead(d, meta)
+}
+
+// resourceAliCloudOssBucketObjectsSyncRead checks the published objects against the state. An object
+// whose ETag is unchanged is trusted, otherwise its CRC64 is compared with the manifest. Objects that
+// are gone or whose content differs are dropped from the manifest, so the next plan uploads them again.
+func resourceAliCloudOssBucketObjectsSyncRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	manifest := expandOssObjectsSyncMap(d.Get("manifest"))
+	etags := expandOssObjectsSyncMap(d.Get("etags"))
+	if len(manifest) == 0 {
+		return nil
+	}
+	bucketName := d.Get("bucket").(string)
+
+	remote := make(map[string]string)
+	token := ""
+	for {
+		raw, err := client.WithOssBucketByName(bucketName, func(bucket *oss.Bucket) (interface{}, error) {
+			return bucket.ListObjectsV2(oss.Prefix(d.Get("prefix").(string)), oss.ContinuationToken(token), oss.MaxKeys(1000))
+		})
+		if err != nil {
+			if IsExpectedErrors(err, []string{"NoSuchBucket"}) {
+				log.Printf("[DEBUG] Resource alicloud_oss_bucket_objects_sync ListObjectsV2 Failed!!! %s", err)
+				d.SetId("")
+				return nil
+			}
+			return WrapErrorf(err, DefaultErrorMsg, d.Id(), "ListObjectsV2", AliyunOssGoSdk)
+		}
+		result := raw.(oss.ListObjectsResultV2)
+		for _, object := range result.Objects {
+			remote[object.Key] = strings.Trim(object.ETag, `"`)
+		}
+		if !result.IsTruncated {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+
+	for key, hash := range manifest {
+		etag, ok := remote[key]
+		if ok && etag == etags[key] {
+			continue
+		}
+		if ok {
+			raw, err := client.WithOssBucketByName(bucketName, func(bucket *oss.Bucket) (interface{}, error) {
+				return bucket.GetObjectDetailedMeta(key)
+			})
+			if err != nil && !IsExpectedErrors(err, []string{"404 Not Found", "NoSuchKey"}) {
+				return WrapErrorf(err, DefaultErrorMsg, d.Id(), "GetObjectDetailedMeta", AliyunOssGoSdk)
+			}
+			if err == nil && raw.(http.Header).Get(oss.HTTPHeaderOssCRC64) == hash {
+				etags[key] = etag
+				continue
+			}
+		}
+		log.Printf("[INFO] Object %s in OSS bucket %s was changed or removed outside of Terraform.", key, bucketName)
+		delete(manifest, key)
+		delete(etags, key)
+	}
+
+	d.Set("manifest", manifest)
+	d.Set("etags", etags)
+	return nil
+}
+
+func resourceAliCloudOssBucketObjectsSyncDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	manifest := expandOssObjectsSyncMap(d.Get("manifest"))
+	if len(manifest) == 0 {
+		return nil
+	}
+	bucketName := d.Get("bucket").(string)
+	keys := make([]string, 0, len(manifest))
+	for key := range manifest {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutDelete))
+	err := runOssObjectsSyncTasks(d.Get("concurrency").(int), ossObjectsSyncBatches(keys, 1000), func(batch []string) error {
+		return ossObjectsSyncDelete(client, bucketName, batch, deadline)
+	})
+	if err != nil && !IsExpectedErrors(err, []string{"NoSuchBucket"}) {
+		return WrapError(err)
+	}
+	return nil
+}
+
+func resourceAliCloudOssBucketObjectsSyncCustomizeDiff(diff *schema.ResourceDiff, meta interface{}) error {
+	for _, key := range []string{"source_dir", "prefix", "include", "exclude"} {
+		if !diff.NewValueKnown(key) {
+			return diff.SetNewComputed("manifest")
+		}
+	}
+	local, err := buildOssObjectsSyncManifest(diff.Get("source_dir").(string), diff.Get("prefix").(string),
+		expandStringList(diff.Get("include").([]interface{})), expandStringList(diff.Get("exclude").([]interface{})))
+	if err != nil {
+		return WrapError(err)
+	}
+	planned := make(map[string]interface{}, len(local))
+	for key, file := range local {
+		planned[key] = file.Hash
+	}
+	current := diff.Get("manifest").(map[string]interface{})
+	changed := len(current) != len(planned)
+	for key, hash := range planned {
+		if current[key] != hash {
+			changed = true
+			break
+		}
+	}
+	if changed || diff.HasChange("extension_rule") || diff.HasChange("acl") {
+		if err := diff.SetNew("manifest", planned); err != nil {
+			return WrapError(err)
+		}
+		return diff.SetNewComputed("etags")
+	}
+	return nil
+}
+
+// resourceAliCloudOssBucketObjectsSyncApply uploads the local files whose content hash differs from
+// the manifest in state and deletes the objects whose file is gone. Every change is recorded as it
+// succeeds, so a failed apply keeps what was already published in state.
+func resourceAliCloudOssBucketObjectsSyncApply(d *schema.ResourceData, meta interface{}, timeout time.Duration) error {
+	client := meta.(*connectivity.AliyunClient)
+	deadline := time.Now().Add(timeout)
+	bucketName := d.Get("bucket").(string)
+	local, err := buildOssObjectsSyncManifest(d.Get("source_dir").(string), d.Get("prefix").(string),
+		expandStringList(d.Get("include").([]interface{})), expandStringList(d.Get("exclude").([]interface{})))
+	if err != nil {
+		return WrapError(err)
+	}
+
+	oldManifest, oldEtags := map[string]string{}, map[string]string{}
+	if !d.IsNewResource() {
+		o, _ := d.GetChange("manifest")
+		oldManifest = expandOssObjectsSyncMap(o)
+		o, _ = d.GetChange("etags")
+		oldEtags = expandOssObjectsSyncMap(o)
+	}
+	reuploadAll := !d.IsNewResource() && (d.HasChange("extension_rule") || d.HasChange("acl"))
+
+	uploads := make([]string, 0)
+	for key, file := range local {
+		if reuploadAll || oldManifest[key] != file.Hash {
+			uploads = append(uploads, key)
+		}
+	}
+	deletes := make([]string, 0)
+	for key := range oldManifest {
+		if _, ok := local[key]; !ok {
+			deletes = append(deletes, key)
+		}
+	}
+	sort.Strings(uploads)
+	sort.Strings(deletes)
+	log.Printf("[INFO] Syncing %s to OSS bucket %s: %d objects to upload, %d objects to delete.", d.Get("source_dir"), bucketName, len(uploads), len(deletes))
+
+	var mutex sync.Mutex
+	manifest, etags := oldManifest, oldEtags
+	defer func() {
+		d.Set("manifest", manifest)
+		d.Set("etags", etags)
+	}()
+
+	rules := d.Get("extension_rule").([]interface{})
+	acl := d.Get("acl").(string)
+	uploadBatches := ossObjectsSyncBatches(uploads, 1)
+	if err := runOssObjectsSyncTasks(d.Get("concurrency").(int), uploadBatches, func(batch []string) error {
+		key := batch[0]
+		etag, err := ossObjectsSyncUpload(client, bucketName, key, local[key], rules, acl, deadline)
+		if err != nil {
+			return err
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		manifest[key] = local[key].Hash
+		etags[key] = etag
+		return nil
+	}); err != nil {
+		return WrapError(err)
+	}
+
+	return runOssObjectsSyncTasks(d.Get("concurrency").(int), ossObjectsSyncBatches(deletes, 1000), func(batch []string) error {
+		if err := ossObjectsSyncDelete(client, bucketName, batch, deadline); err != nil {
+			return err
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, key := range batch {
+			delete(manifest, key)
+			delete(etags, key)
+		}
+		return nil
+	})
+}
+
+func ossObjectsSyncUpload(client *connectivity.AliyunClient, bucketName, key string, file ossObjectsSyncFile, rules []interface{}, acl string, deadline time.Time) (string, error) {
+	var header http.Header
+	options := []oss.Option{oss.GetResponseHeader(&header)}
+	contentType, cacheControl := ossObjectsSyncRule(rules, key)
+	if contentType != "" {
+		options = append(options, oss.ContentType(contentType))
+	}
+	if cacheControl != "" {
+		options = append(options, oss.CacheControl(cacheControl))
+	}
+	if acl != "" {
+		options = append(options, oss.ObjectACL(oss.ACLType(acl)))
+	}
+	err := resource.Retry(time.Until(deadline), func() *resource.RetryError {
+		_, err := client.WithOssBucketByName(bucketName, func(bucket *oss.Bucket) (interface{}, error) {
+			return nil, bucket.PutObjectFromFile(key, file.Path, options...)
+		})
+		if err != nil {
+			if NeedRetry(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", WrapErrorf(err, DefaultErrorMsg, key, "PutObject", AliyunOssGoSdk)
+	}
+	if crc := header.Get(oss.HTTPHeaderOssCRC64); crc != "" && crc != file.Hash {
+		return "", WrapError(fmt.Errorf("the CRC64 %s of object %s does not match the CRC64 %s of %s, the file changed while it was uploaded", crc, key, file.Hash, file.Path))
+	}
+	return strings.Trim(header.Get(oss.HTTPHeaderEtag), `"`), nil
+}
+
+func ossObjectsSyncDelete(client *connectivity.AliyunClient, bucketName string, keys []string, deadline time.Time) error {
+	err := resource.Retry(time.Until(deadline), func() *resource.RetryError {
+		_, err := client.WithOssBucketByName(bucketName, func(bucket *oss.Bucket) (interface{}, error) {
+			return bucket.DeleteObjects(keys, oss.DeleteObjectsQuiet(true))
+		})
+		if err != nil {
+			if NeedRetry(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return WrapErrorf(err, DefaultErrorMsg, bucketName, "DeleteObjects", AliyunOssGoSdk)
+	}
+	return nil
+}
+
+// buildOssObjectsSyncManifest walks sourceDir and returns the files to publish keyed by object key,
+// which is prefix followed by the slash separated path relative to sourceDir. A file is published
+// when it matches one of the include patterns, or there are none, and none of the exclude patterns.
+// The hash of a file is its CRC64 in the format of alicloud_file_crc64_checksum, which is also the
+// x-oss-hash-crc64ecma OSS reports for the object.
+func buildOssObjectsSyncManifest(sourceDir, prefix string, include, exclude []string) (map[string]ossObjectsSyncFile, error) {
+	root, err := homedir.Expand(sourceDir)
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	table := crc64.MakeTable(crc64.ECMA)
+	manifest := make(map[string]ossObjectsSyncFile)
+	err = filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if len(include) > 0 && !globMatchAny(include, rel) {
+			return nil
+		}
+		if globMatchAny(exclude, rel) {
+			return nil
+		}
+		file, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		h := crc64.New(table)
+		if _, err := io.Copy(h, file); err != nil {
+			return err
+		}
+		manifest[prefix+rel] = ossObjectsSyncFile{Path: filePath, Hash: fmt.Sprintf("%d", h.Sum64())}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading source_dir %s failed: %v", sourceDir, err)
+	}
+	return manifest, nil
+}
+
+// ossObjectsSyncRule returns the content type and cache control of the first extension rule that
+// lists the extension of key. Extensions are compared case insensitively, with or without the dot.
+func ossObjectsSyncRule(rules []interface{}, key string) (string, string) {
+	ext := strings.TrimPrefix(strings.ToLower(path.Ext(key)), ".")
+	for _, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, e := range rule["extensions"].([]interface{}) {
+			if strings.TrimPrefix(strings.ToLower(fmt.Sprint(e)), ".") == ext {
+				return rule["content_type"].(string), rule["cache_control"].(string)
+			}
+		}
+	}
+	return "", ""
+}
+
+func ossObjectsSyncBatches(keys []string, size int) [][]string {
+	batches := make([][]string, 0, (len(keys)+size-1)/size)
+	for start := 0; start < len(keys); start += size {
+		end := start + size
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batches = append(batches, keys[start:end])
+	}
+	return batches
+}
+
+// runOssObjectsSyncTasks runs task for every batch with at most concurrency tasks at once. Once a task
+// fails no further batches are started, and the first error is returned after the running ones end.
+func runOssObjectsSyncTasks(concurrency int, batches [][]string, task func(batch []string) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	work := make(chan []string)
+	var once sync.Once
+	var failure error
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range work {
+				if err := task(batch); err != nil {
+					once.Do(func() {
+						failure = err
+						close(stop)
+					})
+				}
+			}
+		}()
+	}
+feed:
+	for _, batch := range batches {
+		select {
+		case work <- batch:
+		case <-stop:
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+	return failure
+}
+
+func expandOssObjectsSyncMap(v interface{}) map[string]string {
+	result := make(map[string]string)
+	if m, ok := v.(map[string]interface{}); ok {
+		for key, value := range m {
+			result[key] = fmt.Sprint(value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,176 @@
+package alicloud
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccAliCloudOssBucketObjectsSync_basic(t *testing.T) {
+	sourceDir, err := ioutil.TempDir("", "tf-oss-objects-sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(sourceDir)
+	writeOssObjectsSyncTestFiles(t, sourceDir, map[string]string{
+		"index.html":     "<html>index</html>",
+		"assets/app.js":  "console.log('app')",
+		"assets/app.map": "{}",
+	})
+
+	resourceId := "alicloud_oss_bucket_objects_sync.default"
+	ra := resourceAttrInit(resourceId, AliCloudOssBucketObjectsSyncMap)
+	testAccCheck := ra.resourceAttrMapUpdateSet()
+	rand := acctest.RandIntRange(1000000, 9999999)
+	name := fmt.Sprintf("tf-testacc-objects-sync-%d", rand)
+	testAccConfig := resourceTestAccConfigFunc(resourceId, name, resourceOssBucketObjectConfigDependence)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		IDRefreshName: resourceId,
+		Providers:     testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConfig(map[string]interface{}{
+					"bucket":     "${alicloud_oss_bucket_public_access_block.default.bucket}",
+					"source_dir": sourceDir,
+					"prefix":     "site/",
+					"exclude":    []string{"**/*.map"},
+					"extension_rule": []map[string]interface{}{
+						{
+							"extensions":    []string{".html"},
+							"content_type":  "text/html; charset=utf-8",
+							"cache_control": "no-cache",
+						},
+					},
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheck(map[string]string{
+						"manifest.%":               "2",
+						"manifest.site/index.html": CHECKSET,
+						"etags.%":                  "2",
+					}),
+				),
+			},
+			{
+				PreConfig: func() {
+					writeOssObjectsSyncTestFiles(t, sourceDir, map[string]string{
+						"index.html": "<html>index v2</html>",
+					})
+					os.Remove(filepath.Join(sourceDir, "assets", "app.js"))
+				},
+				Config: testAccConfig(map[string]interface{}{
+					"concurrency": "2",
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheck(map[string]string{
+						"manifest.%":  "1",
+						"etags.%":     "1",
+						"concurrency": "2",
+					}),
+				),
+			},
+		},
+	})
+}
+
+var AliCloudOssBucketObjectsSyncMap = map[string]string{
+	"concurrency": "8",
+	"manifest.%":  CHECKSET,
+}
+
+func writeOssObjectsSyncTestFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestUnitAliCloudOssBucketObjectsSyncManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tf-oss-objects-sync")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	writeOssObjectsSyncTestFiles(t, dir, map[string]string{
+		"index.html":          "123456789",
+		"assets/app.js":       "js",
+		"assets/app.js.map":   "{}",
+		"assets/img/logo.png": "png",
+		"drafts/post.html":    "draft",
+	})
+
+	manifest, err := buildOssObjectsSyncManifest(dir, "site/", nil, []string{"**/*.map", "drafts/**"})
+	assert.Nil(t, err)
+	keys := make([]string, 0)
+	for key := range manifest {
+		keys = append(keys, key)
+	}
+	assert.ElementsMatch(t, []string{"site/index.html", "site/assets/app.js", "site/assets/img/logo.png"}, keys)
+	// the CRC-64/XZ check value of "123456789", as alicloud_file_crc64_checksum reports it
+	assert.Equal(t, "11051210869376104954", manifest["site/index.html"].Hash)
+	assert.Equal(t, filepath.Join(dir, "index.html"), manifest["site/index.html"].Path)
+
+	manifest, err = buildOssObjectsSyncManifest(dir, "", []string{"assets/**"}, []string{"**/*.png"})
+	assert.Nil(t, err)
+	assert.Len(t, manifest, 2)
+	assert.Contains(t, manifest, "assets/app.js")
+	assert.Contains(t, manifest, "assets/app.js.map")
+
+	_, err = buildOssObjectsSyncManifest(filepath.Join(dir, "missing"), "", nil, nil)
+	assert.NotNil(t, err)
+}
+
+func TestUnitAliCloudOssBucketObjectsSyncRule(t *testing.T) {
+	rules := []interface{}{
+		map[string]interface{}{"extensions": []interface{}{".html", "HTM"}, "content_type": "text/html", "cache_control": "no-cache"},
+		map[string]interface{}{"extensions": []interface{}{"js"}, "content_type": "", "cache_control": "max-age=31536000"},
+	}
+	contentType, cacheControl := ossObjectsSyncRule(rules, "site/index.HTML")
+	assert.Equal(t, "text/html", contentType)
+	assert.Equal(t, "no-cache", cacheControl)
+	contentType, cacheControl = ossObjectsSyncRule(rules, "site/page.htm")
+	assert.Equal(t, "text/html", contentType)
+	contentType, cacheControl = ossObjectsSyncRule(rules, "app.js")
+	assert.Equal(t, "", contentType)
+	assert.Equal(t, "max-age=31536000", cacheControl)
+	contentType, cacheControl = ossObjectsSyncRule(rules, "README")
+	assert.Equal(t, "", contentType)
+	assert.Equal(t, "", cacheControl)
+}
+
+func TestUnitAliCloudOssBucketObjectsSyncTasks(t *testing.T) {
+	keys := []string{"a", "b", "c", "d", "e"}
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, ossObjectsSyncBatches(keys, 2))
+	assert.Empty(t, ossObjectsSyncBatches(nil, 1000))
+
+	var mutex sync.Mutex
+	done := map[string]bool{}
+	err := runOssObjectsSyncTasks(3, ossObjectsSyncBatches(keys, 1), func(batch []string) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		done[batch[0]] = true
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Len(t, done, 5)
+
+	err = runOssObjectsSyncTasks(1, ossObjectsSyncBatches(keys, 1), func(batch []string) error {
+		if batch[0] == "b" {
+			return fmt.Errorf("upload of %s failed", batch[0])
+		}
+		return nil
+	})
+	assert.EqualError(t, err, "upload of b failed")
+}
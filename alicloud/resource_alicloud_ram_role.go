@@ -20,7 +20,7 @@ func resourceAliCloudRamRole() *schema.Resource {
 		Update: resourceAliCloudRamRoleUpdate,
 		Delete: resourceAliCloudRamRoleDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByLookup("alicloud_ram_role", ramRoleImportLookup),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(5 * time.Minute),
@@ -18,7 +18,7 @@ func resourceAlicloudRamUser() *schema.Resource {
 		Update: resourceAlicloudRamUserUpdate,
 		Delete: resourceAlicloudRamUserDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByLookup("alicloud_ram_user", ramUserImportLookup),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(3 * time.Minute),
@@ -3,6 +3,8 @@ package alicloud
 import (
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/PaesslerAG/jsonpath"
@@ -18,7 +20,13 @@ func resourceAliCloudVpcRouteTable() *schema.Resource {
 		Update: resourceAliCloudVpcRouteTableUpdate,
 		Delete: resourceAliCloudVpcRouteTableDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceAliCloudVpcRouteTableImport,
+		},
+		CustomizeDiff: func(d *schema.ResourceDiff, meta interface{}) error {
+			if !d.NewValueKnown("route") {
+				return nil
+			}
+			return checkVpcRouteTableRoutes(d.Get("route").(*schema.Set).List())
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
@@ -45,6 +53,34 @@ func resourceAliCloudVpcRouteTable() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"route": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"destination_cidr_block": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"destination_prefix_list_id": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"nexthop_type": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"nexthop_id": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
 			"route_propagation_enable": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -163,10 +199,31 @@ func resourceAliCloudVpcRouteTableRead(d *schema.ResourceData, meta interface{})
 	tagsMaps, _ := jsonpath.Get("$.Tags.Tag", objectRaw)
 	d.Set("tags", tagsToMap(tagsMaps))
 
+	if _, ok := d.GetOk("route"); ok {
+		entries, err := vpcServiceV2.DescribeVpcRouteTableRouteEntries(d.Id(), "")
+		if err != nil {
+			return WrapError(err)
+		}
+		d.Set("route", expandVpcRouteTableRouteBlocks(flattenVpcRouteTableRoutes(entries)))
+	}
+
 	d.Set("name", d.Get("route_table_name"))
 	return nil
 }
 
+// resourceAliCloudVpcRouteTableImport reads the custom routes of an imported table into route, so they show up in
+// the state and are kept up to date by Read like the routes of a table that declares them.
+func resourceAliCloudVpcRouteTableImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*connectivity.AliyunClient)
+	vpcServiceV2 := VpcServiceV2{client}
+	entries, err := vpcServiceV2.DescribeVpcRouteTableRouteEntries(d.Id(), "")
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	d.Set("route", expandVpcRouteTableRouteBlocks(flattenVpcRouteTableRoutes(entries)))
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceAliCloudVpcRouteTableUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*connectivity.AliyunClient)
 	var request map[string]interface{}
@@ -232,9 +289,341 @@ func resourceAliCloudVpcRouteTableUpdate(d *schema.ResourceData, meta interface{
 			return WrapError(err)
 		}
 	}
+
+	// An empty route set means the routes are not managed here, so removing the last route block
+	// leaves the routes of the table alone instead of deleting all of them.
+	if v, ok := d.GetOk("route"); ok && d.HasChange("route") {
+		timeout := d.Timeout(schema.TimeoutUpdate)
+		if d.IsNewResource() {
+			timeout = d.Timeout(schema.TimeoutCreate)
+		}
+		if err := reconcileVpcRouteTableRoutes(client, d.Id(), v.(*schema.Set).List(), timeout); err != nil {
+			return WrapError(err)
+		}
+	}
 	return resourceAliCloudVpcRouteTableRead(d, meta)
 }
 
+// vpcRouteTableOwnsRoute reports whether a route entry is one the route block manages. System routes
+// and the routes propagated by CEN, ECR, VPN or BGP are left to whoever created them.
+func vpcRouteTableOwnsRoute(entry map[string]interface{}) bool {
+	if fmt.Sprint(entry["Type"]) != "Custom" {
+		return false
+	}
+	switch fmt.Sprint(entry["Origin"]) {
+	case "CEN", "ECR", "VPN", "BGP":
+		return false
+	}
+	return true
+}
+
+// vpcRouteTableRouteDestination returns the destination of a route block, its CIDR block or the prefix
+// list it routes. Both are sent as the DestinationCidrBlock of the route entry operations.
+func vpcRouteTableRouteDestination(route map[string]interface{}) string {
+	if v, ok := route["destination_prefix_list_id"].(string); ok && v != "" {
+		return v
+	}
+	if v, ok := route["destination_cidr_block"].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// vpcRouteTableNextHops returns the next hops of a route as a sorted list of "type:id", so the next hops of
+// two routes can be compared.
+func vpcRouteTableNextHops(route map[string]interface{}) []string {
+	nextHops := make([]string, 0)
+	for _, raw := range route["next_hops"].([]map[string]interface{}) {
+		nextHops = append(nextHops, fmt.Sprintf("%v:%v", raw["nexthop_type"], raw["nexthop_id"]))
+	}
+	sort.Strings(nextHops)
+	return nextHops
+}
+
+// flattenVpcRouteTableRoutes turns the custom route entries of a route table into routes keyed by
+// destination, with their next hops. A route with several next hops is an ECMP route.
+func flattenVpcRouteTableRoutes(entries []map[string]interface{}) map[string]map[string]interface{} {
+	routes := make(map[string]map[string]interface{})
+	for _, entry := range entries {
+		if !vpcRouteTableOwnsRoute(entry) {
+			continue
+		}
+		destination := fmt.Sprint(entry["DestinationCidrBlock"])
+		route := map[string]interface{}{
+			"destination_cidr_block":     destination,
+			"destination_prefix_list_id": "",
+			"description":                "",
+			"route_entry_id":             fmt.Sprint(entry["RouteEntryId"]),
+			"next_hops":                  []map[string]interface{}{},
+		}
+		if strings.HasPrefix(destination, "pl-") {
+			route["destination_cidr_block"] = ""
+			route["destination_prefix_list_id"] = destination
+		}
+		if entry["Description"] != nil {
+			route["description"] = fmt.Sprint(entry["Description"])
+		}
+		nextHops, _ := jsonpath.Get("$.NextHops.NextHop", entry)
+		nextHopList, _ := nextHops.([]interface{})
+		for _, raw := range nextHopList {
+			nextHop, _ := raw.(map[string]interface{})
+			route["next_hops"] = append(route["next_hops"].([]map[string]interface{}), map[string]interface{}{
+				"nexthop_type": fmt.Sprint(nextHop["NextHopType"]),
+				"nexthop_id":   fmt.Sprint(nextHop["NextHopId"]),
+			})
+		}
+		routes[destination] = route
+	}
+	return routes
+}
+
+// expandVpcRouteTableRouteBlocks turns routes into route blocks, one per next hop, so an ECMP route is written as
+// several blocks with the same destination.
+func expandVpcRouteTableRouteBlocks(routes map[string]map[string]interface{}) []interface{} {
+	blocks := make([]interface{}, 0)
+	for _, route := range routes {
+		for _, nextHop := range route["next_hops"].([]map[string]interface{}) {
+			blocks = append(blocks, map[string]interface{}{
+				"destination_cidr_block":     route["destination_cidr_block"],
+				"destination_prefix_list_id": route["destination_prefix_list_id"],
+				"nexthop_type":               nextHop["nexthop_type"],
+				"nexthop_id":                 nextHop["nexthop_id"],
+				"description":                route["description"],
+			})
+		}
+	}
+	return blocks
+}
+
+// checkVpcRouteTableRoutes checks at plan time that every route block has exactly one destination, and that the
+// blocks of an ECMP route agree on its description. Values that are not known yet are left to the apply.
+func checkVpcRouteTableRoutes(configured []interface{}) error {
+	descriptions := make(map[string]string)
+	for _, raw := range configured {
+		route := raw.(map[string]interface{})
+		cidrBlock, _ := route["destination_cidr_block"].(string)
+		prefixListId, _ := route["destination_prefix_list_id"].(string)
+		if cidrBlock == cdnConfigUnknownValue || prefixListId == cdnConfigUnknownValue {
+			continue
+		}
+		if (cidrBlock == "") == (prefixListId == "") {
+			return fmt.Errorf("every route block needs exactly one of destination_cidr_block and destination_prefix_list_id, got %q and %q", cidrBlock, prefixListId)
+		}
+		destination := vpcRouteTableRouteDestination(route)
+		description, _ := route["description"].(string)
+		if description == cdnConfigUnknownValue {
+			continue
+		}
+		if other, ok := descriptions[destination]; ok && other != description {
+			return fmt.Errorf("the route blocks of destination %s have the different descriptions %q and %q, the next hops of a destination share one route and its description", destination, other, description)
+		}
+		descriptions[destination] = description
+	}
+	return nil
+}
+
+// groupVpcRouteTableRoutes turns route blocks into routes keyed by destination. The blocks of the same destination
+// are the next hops of an ECMP route.
+func groupVpcRouteTableRoutes(configured []interface{}) (map[string]map[string]interface{}, error) {
+	if err := checkVpcRouteTableRoutes(configured); err != nil {
+		return nil, err
+	}
+	routes := make(map[string]map[string]interface{})
+	for _, raw := range configured {
+		block := raw.(map[string]interface{})
+		destination := vpcRouteTableRouteDestination(block)
+		route, ok := routes[destination]
+		if !ok {
+			route = map[string]interface{}{
+				"destination_cidr_block":     block["destination_cidr_block"],
+				"destination_prefix_list_id": block["destination_prefix_list_id"],
+				"description":                block["description"],
+				"next_hops":                  []map[string]interface{}{},
+			}
+			routes[destination] = route
+		}
+		route["next_hops"] = append(route["next_hops"].([]map[string]interface{}), map[string]interface{}{
+			"nexthop_type": block["nexthop_type"],
+			"nexthop_id":   block["nexthop_id"],
+		})
+	}
+	return routes, nil
+}
+
+// diffVpcRouteTableRoutes compares the custom routes of a table with the configured ones, both keyed by
+// destination. A route whose description differs is modified in place, and so is a route with one next hop
+// that moves to another one. A route whose next hops change otherwise is deleted and created again, as the
+// next hops of an ECMP route can not be modified. Modified and deleted routes carry the route_entry_id of the
+// existing route.
+func diffVpcRouteTableRoutes(current, desired map[string]map[string]interface{}) (creates, modifies, deletes []map[string]interface{}) {
+	for destination, route := range desired {
+		existing, ok := current[destination]
+		if !ok {
+			creates = append(creates, route)
+			continue
+		}
+		sameNextHops := strings.Join(vpcRouteTableNextHops(existing), ",") == strings.Join(vpcRouteTableNextHops(route), ",")
+		if !sameNextHops && (len(existing["next_hops"].([]map[string]interface{})) > 1 || len(route["next_hops"].([]map[string]interface{})) > 1) {
+			deletes = append(deletes, existing)
+			creates = append(creates, route)
+			continue
+		}
+		if !sameNextHops || existing["description"] != route["description"] {
+			modified := make(map[string]interface{}, len(route)+1)
+			for key, value := range route {
+				modified[key] = value
+			}
+			modified["route_entry_id"] = existing["route_entry_id"]
+			modifies = append(modifies, modified)
+		}
+	}
+	for destination, route := range current {
+		if _, ok := desired[destination]; !ok {
+			deletes = append(deletes, route)
+		}
+	}
+	for _, routes := range [][]map[string]interface{}{creates, modifies, deletes} {
+		sort.Slice(routes, func(i, j int) bool {
+			return vpcRouteTableRouteDestination(routes[i]) < vpcRouteTableRouteDestination(routes[j])
+		})
+	}
+	return creates, modifies, deletes
+}
+
+// vpcRouteTableNextHopList returns the next hops of an ECMP route as the NextHopList of the route entry operations.
+func vpcRouteTableNextHopList(route map[string]interface{}) []interface{} {
+	nextHopList := make([]interface{}, 0)
+	for _, nextHop := range route["next_hops"].([]map[string]interface{}) {
+		nextHopList = append(nextHopList, map[string]interface{}{
+			"NextHopType": nextHop["nexthop_type"],
+			"NextHopId":   nextHop["nexthop_id"],
+		})
+	}
+	return nextHopList
+}
+
+// reconcileVpcRouteTableRoutes makes the custom routes of a route table match the route blocks, and deletes
+// all of them when configured is empty. Extra routes are deleted first, so a destination can move between
+// blocks, and every change waits for the route to settle because the VPC rejects route operations while
+// another one is in progress.
+func reconcileVpcRouteTableRoutes(client *connectivity.AliyunClient, routeTableId string, configured []interface{}, timeout time.Duration) error {
+	vpcServiceV2 := VpcServiceV2{client}
+	entries, err := vpcServiceV2.DescribeVpcRouteTableRouteEntries(routeTableId, "")
+	if err != nil {
+		return WrapError(err)
+	}
+	desired, err := groupVpcRouteTableRoutes(configured)
+	if err != nil {
+		return WrapError(err)
+	}
+	creates, modifies, deletes := diffVpcRouteTableRoutes(flattenVpcRouteTableRoutes(entries), desired)
+
+	for _, route := range deletes {
+		request := map[string]interface{}{
+			"RegionId":             client.RegionId,
+			"RouteTableId":         routeTableId,
+			"DestinationCidrBlock": vpcRouteTableRouteDestination(route),
+		}
+		if nextHops := route["next_hops"].([]map[string]interface{}); len(nextHops) > 1 {
+			request["NextHopList"] = vpcRouteTableNextHopList(route)
+		} else {
+			request["RouteEntryId"] = route["route_entry_id"]
+		}
+		if err := vpcRouteTableRouteAction(client, "DeleteRouteEntry", request, timeout); err != nil {
+			if NotFoundError(err) {
+				continue
+			}
+			return err
+		}
+		stateConf := BuildTracedStateConf(client, []string{}, []string{}, timeout, 5*time.Second, vpcServiceV2.VpcRouteTableRouteEntryStateRefreshFunc(routeTableId, vpcRouteTableRouteDestination(route), "Status", []string{}))
+		if _, err := stateConf.WaitForState(); err != nil {
+			return WrapErrorf(err, IdMsg, routeTableId)
+		}
+	}
+	for _, route := range modifies {
+		request := map[string]interface{}{
+			"RegionId":             client.RegionId,
+			"RouteTableId":         routeTableId,
+			"DestinationCidrBlock": vpcRouteTableRouteDestination(route),
+			"Description":          route["description"],
+		}
+		if nextHops := route["next_hops"].([]map[string]interface{}); len(nextHops) == 1 {
+			request["NewNextHopType"] = nextHops[0]["nexthop_type"]
+			request["NewNextHopId"] = nextHops[0]["nexthop_id"]
+		} else {
+			request["RouteEntryId"] = route["route_entry_id"]
+		}
+		if err := vpcRouteTableRouteAction(client, "ModifyRouteEntry", request, timeout); err != nil {
+			return err
+		}
+		if err := waitVpcRouteTableRouteAvailable(vpcServiceV2, routeTableId, route, timeout); err != nil {
+			return err
+		}
+	}
+	for _, route := range creates {
+		request := map[string]interface{}{
+			"RegionId":             client.RegionId,
+			"RouteTableId":         routeTableId,
+			"DestinationCidrBlock": vpcRouteTableRouteDestination(route),
+			"ClientToken":          buildClientToken("CreateRouteEntry"),
+		}
+		if nextHops := route["next_hops"].([]map[string]interface{}); len(nextHops) == 1 {
+			request["NextHopType"] = nextHops[0]["nexthop_type"]
+			request["NextHopId"] = nextHops[0]["nexthop_id"]
+		} else {
+			request["NextHopList"] = vpcRouteTableNextHopList(route)
+		}
+		if v := route["description"]; v != nil && v != "" {
+			request["Description"] = v
+		}
+		if err := vpcRouteTableRouteAction(client, "CreateRouteEntry", request, timeout); err != nil {
+			return err
+		}
+		if err := waitVpcRouteTableRouteAvailable(vpcServiceV2, routeTableId, route, timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func waitVpcRouteTableRouteAvailable(vpcServiceV2 VpcServiceV2, routeTableId string, route map[string]interface{}, timeout time.Duration) error {
+	stateConf := BuildTracedStateConf(vpcServiceV2.client, []string{}, []string{"Available"}, timeout, 5*time.Second, vpcServiceV2.VpcRouteTableRouteEntryStateRefreshFunc(routeTableId, vpcRouteTableRouteDestination(route), "Status", []string{}))
+	if _, err := stateConf.WaitForState(); err != nil {
+		return WrapErrorf(err, IdMsg, routeTableId)
+	}
+	return nil
+}
+
+// vpcRouteTableRouteAction sends a route entry operation, serialized with the alicloud_route_entry
+// operations of this process through routeEntryTaskLock.
+func vpcRouteTableRouteAction(client *connectivity.AliyunClient, action string, request map[string]interface{}, timeout time.Duration) error {
+	routeEntryTaskLock.Lock()
+	defer routeEntryTaskLock.Unlock()
+
+	var response map[string]interface{}
+	var err error
+	wait := incrementalWait(3*time.Second, 5*time.Second)
+	err = resource.Retry(timeout, func() *resource.RetryError {
+		response, err = client.RpcPost("Vpc", "2016-04-28", action, nil, request, true)
+		if err != nil {
+			if IsExpectedErrors(err, []string{"IncorrectInstanceStatus", "IncorrectRouteEntryStatus", "SystemBusy", "InvalidVBRStatus", "LastTokenProcessing", "IncorrectStatus.Ipv6Address", "IncorrectStatus", "OperationFailed.DistibuteLock", "ServiceUnavailable", "IncorrectStatus.RouteTableStatus", "IncorrectStatus.MultiScopeRiRouteEntry", "IncorrectVpcStatus", "IncorrectHaVipStatus", "OperationConflict", "TaskConflict", "IncorrectStatus.Ipv4Gateway", "IncorrectStatus.VpcPeer", "IncorrectVSwitchStatus", "IncorrectStatus.RouterInterface", "IncorrectStatus.PrefixList"}) || NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug(action, response, request)
+	if err != nil {
+		if IsExpectedErrors(err, []string{"InvalidRouteEntry.NotFound"}) {
+			return WrapErrorf(NotFoundErr("RouteEntry", fmt.Sprint(request["DestinationCidrBlock"])), NotFoundMsg, response)
+		}
+		return WrapErrorf(err, DefaultErrorMsg, fmt.Sprint(request["RouteTableId"]), action, AlibabaCloudSdkGoERROR)
+	}
+	return nil
+}
+
 func resourceAliCloudVpcRouteTableDelete(d *schema.ResourceData, meta interface{}) error {
 
 	client := meta.(*connectivity.AliyunClient)
@@ -247,6 +636,16 @@ func resourceAliCloudVpcRouteTableDelete(d *schema.ResourceData, meta interface{
 	request["RouteTableId"] = d.Id()
 	request["RegionId"] = client.RegionId
 
+	// the routes of the route blocks keep the table from being deleted
+	if v, ok := d.GetOk("route"); ok && v.(*schema.Set).Len() > 0 {
+		if err := reconcileVpcRouteTableRoutes(client, d.Id(), nil, d.Timeout(schema.TimeoutDelete)); err != nil {
+			if NotFoundError(err) {
+				return nil
+			}
+			return WrapError(err)
+		}
+	}
+
 	wait := incrementalWait(3*time.Second, 5*time.Second)
 	err = resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		response, err = client.RpcPost("Vpc", "2016-04-28", action, query, request, true)
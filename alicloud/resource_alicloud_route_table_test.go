@@ -1302,3 +1302,194 @@ resource "alicloud_vpc" "defaultVpc" {
 }
 
 // Test Vpc RouteTable. <<< Resource test cases, automatically generated.
+
+func TestAccAliCloudVPCRouteTable_route(t *testing.T) {
+	var v map[string]interface{}
+	resourceId := "alicloud_route_table.default"
+	ra := resourceAttrInit(resourceId, AlicloudRouteTableMap0)
+	rc := resourceCheckInitWithDescribeMethod(resourceId, &v, func() interface{} {
+		return &VpcServiceV2{testAccProvider.Meta().(*connectivity.AliyunClient)}
+	}, "DescribeVpcRouteTable")
+	rac := resourceAttrCheckInit(rc, ra)
+	testAccCheck := rac.resourceAttrMapUpdateSet()
+	rand := acctest.RandIntRange(10000, 99999)
+	name := fmt.Sprintf("tf-testacc%sroutetable%d", defaultRegionToTest, rand)
+	testAccConfig := resourceTestAccConfigFunc(resourceId, name, AlicloudRouteTableRouteDependence)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckWithRegions(t, false, connectivity.RouteTableNoSupportedRegions)
+		},
+		IDRefreshName: resourceId,
+		Providers:     testAccProviders,
+		CheckDestroy:  rac.checkResourceDestroy(),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConfig(map[string]interface{}{
+					"vpc_id":           "${alicloud_vpc.default.id}",
+					"route_table_name": name,
+					"route": []map[string]interface{}{
+						{
+							"destination_cidr_block": "10.0.0.0/24",
+							"nexthop_type":           "HaVip",
+							"nexthop_id":             "${alicloud_havip.default.id}",
+						},
+						{
+							"destination_cidr_block": "10.0.1.0/24",
+							"nexthop_type":           "HaVip",
+							"nexthop_id":             "${alicloud_havip.default.id}",
+							"description":            name,
+						},
+					},
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheck(map[string]string{
+						"route_table_name": name,
+						"route.#":          "2",
+					}),
+				),
+			},
+			{
+				Config: testAccConfig(map[string]interface{}{
+					"route": []map[string]interface{}{
+						{
+							"destination_cidr_block": "10.0.1.0/24",
+							"nexthop_type":           "HaVip",
+							"nexthop_id":             "${alicloud_havip.default.id}",
+							"description":            name + "_update",
+						},
+						{
+							"destination_cidr_block": "10.0.2.0/24",
+							"nexthop_type":           "HaVip",
+							"nexthop_id":             "${alicloud_havip.default.id}",
+						},
+					},
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheck(map[string]string{
+						"route.#": "2",
+					}),
+				),
+			},
+			{
+				Config: testAccConfig(map[string]interface{}{
+					"route": []map[string]interface{}{
+						{
+							"destination_prefix_list_id": "${alicloud_vpc_prefix_list.default.id}",
+							"nexthop_type":               "HaVip",
+							"nexthop_id":                 "${alicloud_havip.default.id}",
+						},
+					},
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheck(map[string]string{
+						"route.#": "1",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func AlicloudRouteTableRouteDependence(name string) string {
+	return fmt.Sprintf(`
+variable "name" {
+  default = "%s"
+}
+
+data "alicloud_zones" "default" {
+  available_resource_creation = "VSwitch"
+}
+
+resource "alicloud_vpc" "default" {
+  vpc_name   = var.name
+  cidr_block = "192.168.0.0/16"
+}
+
+resource "alicloud_vswitch" "default" {
+  vpc_id       = alicloud_vpc.default.id
+  zone_id      = data.alicloud_zones.default.zones.0.id
+  cidr_block   = "192.168.0.0/24"
+  vswitch_name = var.name
+}
+
+resource "alicloud_havip" "default" {
+  vswitch_id  = alicloud_vswitch.default.id
+  ha_vip_name = var.name
+}
+
+resource "alicloud_vpc_prefix_list" "default" {
+  prefix_list_name = var.name
+  ip_version       = "IPV4"
+  max_entries      = 5
+  entrys {
+    cidr = "10.0.3.0/24"
+  }
+}
+`, name)
+}
+
+func TestUnitAliCloudVPCRouteTableRoutes(t *testing.T) {
+	entries := []map[string]interface{}{
+		{"Type": "System", "DestinationCidrBlock": "192.168.0.0/24", "NextHops": map[string]interface{}{"NextHop": []interface{}{map[string]interface{}{"NextHopType": "local", "NextHopId": ""}}}},
+		{"Type": "Custom", "Origin": "CEN", "DestinationCidrBlock": "172.16.0.0/16", "NextHops": map[string]interface{}{"NextHop": []interface{}{map[string]interface{}{"NextHopType": "Attachment", "NextHopId": "tr-1"}}}},
+		{"Type": "Custom", "RouteEntryId": "rte-1", "DestinationCidrBlock": "10.0.0.0/24", "NextHops": map[string]interface{}{"NextHop": []interface{}{map[string]interface{}{"NextHopType": "HaVip", "NextHopId": "havip-1"}}}},
+		{"Type": "Custom", "RouteEntryId": "rte-2", "DestinationCidrBlock": "10.0.1.0/24", "Description": "old", "NextHops": map[string]interface{}{"NextHop": []interface{}{map[string]interface{}{"NextHopType": "HaVip", "NextHopId": "havip-1"}}}},
+		{"Type": "Custom", "RouteEntryId": "rte-5", "DestinationCidrBlock": "2408:4005:3ff::/48", "NextHops": map[string]interface{}{"NextHop": []interface{}{map[string]interface{}{"NextHopType": "Ipv6Gateway", "NextHopId": "ipv6gw-1"}}}},
+		{"Type": "Custom", "RouteEntryId": "rte-3", "DestinationCidrBlock": "pl-1", "NextHops": map[string]interface{}{"NextHop": []interface{}{map[string]interface{}{"NextHopType": "HaVip", "NextHopId": "havip-1"}}}},
+		{"Type": "Custom", "RouteEntryId": "rte-4", "DestinationCidrBlock": "10.0.9.0/24", "NextHops": map[string]interface{}{"NextHop": []interface{}{
+			map[string]interface{}{"NextHopType": "Instance", "NextHopId": "i-1"},
+			map[string]interface{}{"NextHopType": "Instance", "NextHopId": "i-2"},
+		}}},
+	}
+	current := flattenVpcRouteTableRoutes(entries)
+	assert.Len(t, current, 5)
+	assert.Equal(t, map[string]interface{}{
+		"destination_cidr_block":     "10.0.0.0/24",
+		"destination_prefix_list_id": "",
+		"description":                "",
+		"route_entry_id":             "rte-1",
+		"next_hops":                  []map[string]interface{}{{"nexthop_type": "HaVip", "nexthop_id": "havip-1"}},
+	}, current["10.0.0.0/24"])
+	assert.Equal(t, "", current["pl-1"]["destination_cidr_block"])
+	assert.Equal(t, "pl-1", current["pl-1"]["destination_prefix_list_id"])
+	assert.Equal(t, "pl-1", vpcRouteTableRouteDestination(current["pl-1"]))
+	assert.Equal(t, "2408:4005:3ff::/48", vpcRouteTableRouteDestination(current["2408:4005:3ff::/48"]))
+	assert.Equal(t, []string{"Instance:i-1", "Instance:i-2"}, vpcRouteTableNextHops(current["10.0.9.0/24"]))
+	assert.Len(t, expandVpcRouteTableRouteBlocks(current), 6)
+
+	desired, err := groupVpcRouteTableRoutes([]interface{}{
+		map[string]interface{}{"destination_cidr_block": "10.0.0.0/24", "destination_prefix_list_id": "", "nexthop_type": "HaVip", "nexthop_id": "havip-1", "description": ""},
+		map[string]interface{}{"destination_cidr_block": "10.0.1.0/24", "destination_prefix_list_id": "", "nexthop_type": "HaVip", "nexthop_id": "havip-2", "description": "old"},
+		map[string]interface{}{"destination_cidr_block": "10.0.2.0/24", "destination_prefix_list_id": "", "nexthop_type": "HaVip", "nexthop_id": "havip-1", "description": ""},
+		map[string]interface{}{"destination_cidr_block": "2408:4005:3ff::/48", "destination_prefix_list_id": "", "nexthop_type": "Ipv6Gateway", "nexthop_id": "ipv6gw-1", "description": ""},
+		map[string]interface{}{"destination_cidr_block": "10.0.9.0/24", "destination_prefix_list_id": "", "nexthop_type": "Instance", "nexthop_id": "i-1", "description": ""},
+		map[string]interface{}{"destination_cidr_block": "10.0.9.0/24", "destination_prefix_list_id": "", "nexthop_type": "Instance", "nexthop_id": "i-3", "description": ""},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, desired, 5)
+	creates, modifies, deletes := diffVpcRouteTableRoutes(current, desired)
+	assert.Equal(t, []map[string]interface{}{desired["10.0.2.0/24"], desired["10.0.9.0/24"]}, creates)
+	assert.Len(t, modifies, 1)
+	assert.Equal(t, "rte-2", modifies[0]["route_entry_id"])
+	assert.Equal(t, []string{"HaVip:havip-2"}, vpcRouteTableNextHops(modifies[0]))
+	assert.Len(t, deletes, 2)
+	assert.Equal(t, "rte-4", deletes[0]["route_entry_id"])
+	assert.Equal(t, "rte-3", deletes[1]["route_entry_id"])
+	assert.Len(t, vpcRouteTableNextHopList(deletes[0]), 2)
+
+	// every block needs exactly one destination, and the blocks of an ECMP route one description
+	assert.NotNil(t, checkVpcRouteTableRoutes([]interface{}{
+		map[string]interface{}{"destination_cidr_block": "10.0.0.0/24", "destination_prefix_list_id": "pl-1", "nexthop_type": "HaVip", "nexthop_id": "havip-1"},
+	}))
+	assert.NotNil(t, checkVpcRouteTableRoutes([]interface{}{
+		map[string]interface{}{"destination_cidr_block": "", "destination_prefix_list_id": "", "nexthop_type": "HaVip", "nexthop_id": "havip-1"},
+	}))
+	assert.NotNil(t, checkVpcRouteTableRoutes([]interface{}{
+		map[string]interface{}{"destination_cidr_block": "10.0.9.0/24", "nexthop_type": "Instance", "nexthop_id": "i-1", "description": "a"},
+		map[string]interface{}{"destination_cidr_block": "10.0.9.0/24", "nexthop_type": "Instance", "nexthop_id": "i-2", "description": "b"},
+	}))
+	assert.Nil(t, checkVpcRouteTableRoutes([]interface{}{
+		map[string]interface{}{"destination_cidr_block": cdnConfigUnknownValue, "destination_prefix_list_id": "", "nexthop_type": "HaVip", "nexthop_id": "havip-1"},
+	}))
+}
@@ -19,7 +19,7 @@ func resourceAliCloudEcsSecurityGroup() *schema.Resource {
 		Update: resourceAliCloudEcsSecurityGroupUpdate,
 		Delete: resourceAliCloudEcsSecurityGroupDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByLookup("alicloud_security_group", securityGroupImportLookup),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(5 * time.Minute),
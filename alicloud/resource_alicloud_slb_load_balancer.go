@@ -18,7 +18,7 @@ func resourceAlicloudSlbLoadBalancer() *schema.Resource {
 		Update: resourceAlicloudSlbLoadBalancerUpdate,
 		Delete: resourceAlicloudSlbLoadBalancerDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByLookup("alicloud_slb_load_balancer", slbLoadBalancerImportLookup),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(5 * time.Minute),
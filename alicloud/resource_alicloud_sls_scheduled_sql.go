@@ -27,6 +27,7 @@ func resourceAliCloudSlsScheduledSql() *schema.Resource {
 			Update: schema.DefaultTimeout(5 * time.Minute),
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
+		CustomizeDiff: resourceAliCloudSlsScheduledSqlCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"description": {
 				Type:     schema.TypeString,
@@ -671,3 +672,27 @@ func resourceAliCloudSlsScheduledSqlDelete(d *schema.ResourceData, meta interfac
 
 	return nil
 }
+
+// resourceAliCloudSlsScheduledSqlCustomizeDiff checks the syntax of the script at plan time, and the fields it
+// references against the index of the source logstore when it already exists. A standardSQL script has no search part.
+func resourceAliCloudSlsScheduledSqlCustomizeDiff(diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() != "" && !diff.HasChange("scheduled_sql_configuration") {
+		return nil
+	}
+	prefix := "scheduled_sql_configuration.0"
+	if !diff.NewValueKnown(prefix+".script") || !diff.NewValueKnown(prefix+".sql_type") {
+		return nil
+	}
+	script := diff.Get(prefix + ".script").(string)
+	if script == "" {
+		return nil
+	}
+	project, logstore := "", ""
+	if diff.NewValueKnown("project") && diff.NewValueKnown(prefix+".source_logstore") {
+		project = diff.Get("project").(string)
+		logstore = diff.Get(prefix + ".source_logstore").(string)
+	}
+	client, _ := meta.(*connectivity.AliyunClient)
+	standardSQL := diff.Get(prefix+".sql_type").(string) == "standardSQL"
+	return checkSlsQuery(client, prefix+".script", script, standardSQL, project, logstore)
+}
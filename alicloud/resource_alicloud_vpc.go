@@ -18,7 +18,7 @@ func resourceAliCloudVpcVpc() *schema.Resource {
 		Update: resourceAliCloudVpcVpcUpdate,
 		Delete: resourceAliCloudVpcVpcDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByLookup("alicloud_vpc", vpcImportLookup),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
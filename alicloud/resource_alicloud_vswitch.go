@@ -1,11 +1,14 @@
 package alicloud
 
 import (
+	"encoding/binary"
 	"fmt"
 	"log"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/PaesslerAG/jsonpath"
@@ -21,7 +24,7 @@ func resourceAliCloudVpcVswitch() *schema.Resource {
 		Update: resourceAliCloudVpcVswitchUpdate,
 		Delete: resourceAliCloudVpcVswitchDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByLookup("alicloud_vswitch", vswitchImportLookup),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(5 * time.Minute),
@@ -35,6 +38,24 @@ func resourceAliCloudVpcVswitch() *schema.Resource {
 				ForceNew: true,
 				Computed: true,
 			},
+			"cidr_mask": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ForceNew:      true,
+				Computed:      true,
+				ValidateFunc:  IntBetween(16, 29),
+				ConflictsWith: []string{"cidr_block"},
+			},
+			"ipv4_ipam_pool_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"cidr_mask"},
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					// the pool only narrows down the allocation, it is not reported back for an existing vSwitch
+					return old == "" && d.Id() != ""
+				},
+			},
 			"create_time": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -171,6 +192,22 @@ func resourceAliCloudVpcVswitchCreate(d *schema.ResourceData, meta interface{})
 
 		request["CidrBlock"] = d.Get("cidr_block")
 		request["VpcId"] = d.Get("vpc_id")
+		cidrMask, allocateCidrBlock := d.GetOk("cidr_mask")
+		if _, ok := d.GetOk("cidr_block"); ok {
+			allocateCidrBlock = false
+		}
+		if allocateCidrBlock {
+			// allocations and creations in the same VPC are serialized, so parallel creates never pick the same block
+			vpcId := fmt.Sprint(request["VpcId"])
+			lock, _ := vswitchCidrAllocationLocks.LoadOrStore(vpcId, &sync.Mutex{})
+			lock.(*sync.Mutex).Lock()
+			defer lock.(*sync.Mutex).Unlock()
+			cidrBlock, err := allocateVpcVswitchCidrBlock(client, vpcId, cidrMask.(int), d.Get("ipv4_ipam_pool_id").(string))
+			if err != nil {
+				return WrapError(err)
+			}
+			request["CidrBlock"] = cidrBlock
+		}
 		if v, ok := d.GetOk("name"); ok {
 			request["VSwitchName"] = v
 		}
@@ -198,6 +235,15 @@ func resourceAliCloudVpcVswitchCreate(d *schema.ResourceData, meta interface{})
 			request["ClientToken"] = buildClientToken(action)
 
 			if err != nil {
+				if allocateCidrBlock && IsExpectedErrors(err, []string{"InvalidCidrBlock.Overlapped"}) {
+					// a vSwitch created outside of this provider took the block in the meantime
+					cidrBlock, allocateErr := allocateVpcVswitchCidrBlock(client, fmt.Sprint(request["VpcId"]), cidrMask.(int), d.Get("ipv4_ipam_pool_id").(string))
+					if allocateErr != nil {
+						return resource.NonRetryableError(allocateErr)
+					}
+					request["CidrBlock"] = cidrBlock
+					return resource.RetryableError(err)
+				}
 				if IsExpectedErrors(err, []string{"TaskConflict", "IncorrectStatus.cbnStatus", "InvalidStatus.RouteEntry", "OperationFailed.IdempotentTokenProcessing", "IncorrectStatus", "CreateVSwitch.IncorrectStatus.cbnStatus", "IncorrectVSwitchStatus", "OperationConflict", "OperationFailed.DistibuteLock", "OperationFailed.NotifyCenCreate"}) || NeedRetry(err) {
 					wait()
 					return resource.RetryableError(err)
@@ -240,6 +286,10 @@ func resourceAliCloudVpcVswitchRead(d *schema.ResourceData, meta interface{}) er
 	}
 
 	d.Set("cidr_block", objectRaw["CidrBlock"])
+	if _, cidrBlock, err := net.ParseCIDR(fmt.Sprint(objectRaw["CidrBlock"])); err == nil {
+		cidrMask, _ := cidrBlock.Mask.Size()
+		d.Set("cidr_mask", cidrMask)
+	}
 	d.Set("create_time", objectRaw["CreationTime"])
 	d.Set("description", objectRaw["Description"])
 	d.Set("ipv6_cidr_block", objectRaw["Ipv6CidrBlock"])
@@ -472,3 +522,139 @@ func CancelIpv6(d *schema.ResourceData, meta interface{}) error {
 
 	return nil
 }
+
+// vswitchCidrAllocationLocks holds one mutex per VPC, taken while a cidr_mask vSwitch picks its block and is created.
+var vswitchCidrAllocationLocks sync.Map
+
+// allocateVpcVswitchCidrBlock picks the first free block of the given mask inside the primary and secondary CIDR
+// blocks of the VPC, narrowed down to the CIDR blocks of the IPAM pool when one is given.
+func allocateVpcVswitchCidrBlock(client *connectivity.AliyunClient, vpcId string, mask int, ipamPoolId string) (string, error) {
+	vpcServiceV2 := VpcServiceV2{client}
+	vpc, err := vpcServiceV2.DescribeVpcVpc(vpcId)
+	if err != nil {
+		return "", WrapError(err)
+	}
+	spaces := []string{fmt.Sprint(vpc["CidrBlock"])}
+	if v, err := jsonpath.Get("$.SecondaryCidrBlocks.SecondaryCidrBlock", vpc); err == nil {
+		if secondaryCidrBlocks, ok := v.([]interface{}); ok {
+			spaces = append(spaces, expandStringList(secondaryCidrBlocks)...)
+		}
+	}
+
+	vswitches, err := vpcServiceV2.DescribeVpcVpcVswitches(vpcId)
+	if err != nil {
+		return "", WrapError(err)
+	}
+	used := make([]string, 0, len(vswitches))
+	for _, vswitch := range vswitches {
+		used = append(used, fmt.Sprint(vswitch["CidrBlock"]))
+	}
+
+	if ipamPoolId != "" {
+		vpcIpamServiceV2 := VpcIpamServiceV2{client}
+		poolCidrs, err := vpcIpamServiceV2.DescribeVpcIpamIpamPoolCidrs(ipamPoolId)
+		if err != nil {
+			return "", WrapError(err)
+		}
+		poolSpaces := make([]string, 0, len(poolCidrs))
+		for _, poolCidr := range poolCidrs {
+			poolSpaces = append(poolSpaces, fmt.Sprint(poolCidr["Cidr"]))
+		}
+		spaces = intersectVswitchCidrSpaces(spaces, poolSpaces)
+
+		allocations, err := vpcIpamServiceV2.DescribeVpcIpamIpamPoolAllocations(ipamPoolId)
+		if err != nil {
+			return "", WrapError(err)
+		}
+		for _, allocation := range allocations {
+			// the allocation of the VPC itself covers the whole space that is being carved up
+			if fmt.Sprint(allocation["ResourceId"]) == vpcId {
+				continue
+			}
+			used = append(used, fmt.Sprint(allocation["Cidr"]))
+		}
+	}
+
+	cidrBlock, err := nextFreeVswitchCidrBlock(spaces, used, mask)
+	if err != nil {
+		return "", WrapErrorf(err, IdMsg, vpcId)
+	}
+	log.Printf("[DEBUG] alicloud_vswitch allocated %s in %s", cidrBlock, vpcId)
+	return cidrBlock, nil
+}
+
+// intersectVswitchCidrSpaces keeps the parts of the VPC CIDR blocks that are also inside a pool CIDR block. Two CIDR
+// blocks either nest or are disjoint, so every overlap is the smaller of the two.
+func intersectVswitchCidrSpaces(vpcSpaces []string, poolSpaces []string) []string {
+	result := make([]string, 0)
+	for _, vpcSpace := range vpcSpaces {
+		_, vpcCidr, err := net.ParseCIDR(vpcSpace)
+		if err != nil {
+			continue
+		}
+		for _, poolSpace := range poolSpaces {
+			_, poolCidr, err := net.ParseCIDR(poolSpace)
+			if err != nil || !vpcCidr.Contains(poolCidr.IP) && !poolCidr.Contains(vpcCidr.IP) {
+				continue
+			}
+			vpcOnes, _ := vpcCidr.Mask.Size()
+			poolOnes, _ := poolCidr.Mask.Size()
+			if vpcOnes >= poolOnes {
+				result = append(result, vpcCidr.String())
+			} else {
+				result = append(result, poolCidr.String())
+			}
+		}
+	}
+	return result
+}
+
+// nextFreeVswitchCidrBlock returns the lowest block of the given mask in the first space that has one which does not
+// overlap any used block.
+func nextFreeVswitchCidrBlock(spaces []string, used []string, mask int) (string, error) {
+	type ipv4Range struct{ first, last uint64 }
+	toRange := func(cidr *net.IPNet) ipv4Range {
+		ones, _ := cidr.Mask.Size()
+		first := uint64(binary.BigEndian.Uint32(cidr.IP.To4()))
+		return ipv4Range{first, first + 1<<uint(32-ones) - 1}
+	}
+	usedRanges := make([]ipv4Range, 0, len(used))
+	for _, block := range used {
+		if _, cidr, err := net.ParseCIDR(block); err == nil && cidr.IP.To4() != nil {
+			usedRanges = append(usedRanges, toRange(cidr))
+		}
+	}
+	sort.Slice(usedRanges, func(i, j int) bool { return usedRanges[i].first < usedRanges[j].first })
+
+	size := uint64(1) << uint(32-mask)
+	for _, space := range spaces {
+		_, cidr, err := net.ParseCIDR(space)
+		if err != nil || cidr.IP.To4() == nil {
+			continue
+		}
+		if ones, _ := cidr.Mask.Size(); ones > mask {
+			continue
+		}
+		spaceRange := toRange(cidr)
+		candidate := spaceRange.first
+		for _, usedRange := range usedRanges {
+			if candidate+size-1 > spaceRange.last {
+				break
+			}
+			if usedRange.last < candidate || usedRange.first > candidate+size-1 {
+				if usedRange.first > candidate+size-1 {
+					break
+				}
+				continue
+			}
+			// skip past the used block, then align to the mask
+			candidate = (usedRange.last + size) / size * size
+		}
+		if candidate+size-1 <= spaceRange.last {
+			ip := make(net.IP, 4)
+			binary.BigEndian.PutUint32(ip, uint32(candidate))
+			return fmt.Sprintf("%s/%d", ip, mask), nil
+		}
+	}
+	return "", fmt.Errorf("there is no free /%d block left in %s", mask, strings.Join(spaces, ", "))
+}
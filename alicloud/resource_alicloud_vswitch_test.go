@@ -1133,6 +1133,95 @@ func TestAccAliCloudVPCVSwitch_isDefault_twin(t *testing.T) {
 	})
 }
 
+func TestAccAliCloudVPCVSwitch_cidrMask(t *testing.T) {
+	rand := acctest.RandIntRange(10000, 99999)
+	name := fmt.Sprintf("tf-testacc%svswitch%d", defaultRegionToTest, rand)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVswitchCidrMaskConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alicloud_vswitch.allocated.0", "cidr_mask", "24"),
+					resource.TestCheckResourceAttr("alicloud_vswitch.allocated.1", "cidr_mask", "24"),
+					resource.TestMatchResourceAttr("alicloud_vswitch.allocated.0", "cidr_block", regexp.MustCompile(`^172\.16\.[12]\.0/24$`)),
+					resource.TestMatchResourceAttr("alicloud_vswitch.allocated.1", "cidr_block", regexp.MustCompile(`^172\.16\.[12]\.0/24$`)),
+					func(s *terraform.State) error {
+						first := s.RootModule().Resources["alicloud_vswitch.allocated.0"].Primary.Attributes["cidr_block"]
+						second := s.RootModule().Resources["alicloud_vswitch.allocated.1"].Primary.Attributes["cidr_block"]
+						if first == second {
+							return fmt.Errorf("both vSwitches were allocated %s", first)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testAccVswitchCidrMaskConfig(name string) string {
+	return fmt.Sprintf(`
+variable "name" {
+  default = "%s"
+}
+
+data "alicloud_zones" "default" {
+  available_resource_creation = "VSwitch"
+}
+
+resource "alicloud_vpc" "default" {
+  vpc_name   = var.name
+  cidr_block = "172.16.0.0/16"
+}
+
+resource "alicloud_vswitch" "fixed" {
+  vswitch_name = var.name
+  vpc_id       = alicloud_vpc.default.id
+  cidr_block   = "172.16.0.0/24"
+  zone_id      = data.alicloud_zones.default.zones.0.id
+}
+
+resource "alicloud_vswitch" "allocated" {
+  count        = 2
+  vswitch_name = var.name
+  vpc_id       = alicloud_vswitch.fixed.vpc_id
+  cidr_mask    = 24
+  zone_id      = data.alicloud_zones.default.zones.0.id
+}
+`, name)
+}
+
+func TestUnitAliCloudVPCVSwitchCidrAllocation(t *testing.T) {
+	cidrBlock, err := nextFreeVswitchCidrBlock([]string{"10.0.0.0/16"}, nil, 24)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.0/24", cidrBlock)
+
+	// used blocks of any size are skipped, and the result stays aligned to its mask
+	cidrBlock, err = nextFreeVswitchCidrBlock([]string{"10.0.0.0/16"}, []string{"10.0.1.0/24", "10.0.0.0/25", "10.0.2.16/28"}, 24)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.3.0/24", cidrBlock)
+
+	cidrBlock, err = nextFreeVswitchCidrBlock([]string{"10.0.0.0/16"}, []string{"10.0.0.0/25"}, 25)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.128/25", cidrBlock)
+
+	// a full primary block falls through to the secondary ones, and spaces smaller than the mask are skipped
+	cidrBlock, err = nextFreeVswitchCidrBlock([]string{"10.0.0.0/23", "192.168.0.0/25", "172.16.0.0/16"}, []string{"10.0.0.0/24", "10.0.1.0/24"}, 24)
+	assert.Nil(t, err)
+	assert.Equal(t, "172.16.0.0/24", cidrBlock)
+
+	_, err = nextFreeVswitchCidrBlock([]string{"10.0.0.0/24"}, []string{"10.0.0.0/24"}, 24)
+	assert.EqualError(t, err, "there is no free /24 block left in 10.0.0.0/24")
+
+	assert.Equal(t, []string{"10.0.0.0/16", "192.168.0.0/24"},
+		intersectVswitchCidrSpaces([]string{"10.0.0.0/16", "172.16.0.0/12", "192.168.0.0/24"}, []string{"10.0.0.0/8", "192.168.0.0/16"}))
+	assert.Equal(t, []string{"10.1.0.0/17"}, intersectVswitchCidrSpaces([]string{"10.0.0.0/8"}, []string{"10.1.0.0/17", "172.16.0.0/12"}))
+}
+
 var AlicloudVswitchMap0 = map[string]string{}
 
 func AlicloudVswitchBasicDependence0(name string) string {
@@ -3,6 +3,7 @@ package alicloud
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
@@ -170,6 +171,147 @@ func (s *AckServiceV2) DescribeAsyncDescribeTaskInfo(d *schema.ResourceData, res
 
 // DescribeAsyncDescribeTaskInfo >>> Encapsulated.
 
+// DescribeAsyncAckNodepoolRollingStateRefreshFunc waits for a node pool task that rolls over its nodes in batches. Every
+// change of progress is logged, and a failed task reports the nodes that failed with their errors. With
+// pauseOnFailure, the task is paused as soon as a node fails, so the remaining batches are left untouched.
+func (s *AckServiceV2) DescribeAsyncAckNodepoolRollingStateRefreshFunc(d *schema.ResourceData, res map[string]interface{}, failStates []string, pauseOnFailure bool) resource.StateRefreshFunc {
+	taskId := fmt.Sprint(res["task_id"])
+	lastProgress := ""
+	return func() (interface{}, string, error) {
+		object, err := s.DescribeAsyncDescribeTaskInfo(d, res)
+		if err != nil {
+			if NotFoundError(err) {
+				return object, "", nil
+			}
+			return nil, "", WrapError(err)
+		}
+		currentStatus := fmt.Sprint(object["state"])
+
+		if progress := ackNodepoolTaskProgress(object); progress != lastProgress {
+			log.Printf("[INFO] alicloud_cs_kubernetes_node_pool %s task %s: %s", d.Id(), taskId, progress)
+			lastProgress = progress
+		}
+
+		failedNodes := ackNodepoolTaskFailedNodes(object)
+		for _, failState := range failStates {
+			if currentStatus == failState {
+				return object, currentStatus, WrapError(fmt.Errorf("task %s %s, failed nodes: %s", taskId, currentStatus, ackNodepoolTaskFailureDetail(object, failedNodes)))
+			}
+		}
+		if pauseOnFailure && len(failedNodes) > 0 && currentStatus != "success" && currentStatus != "paused" {
+			if err := s.PauseAckTask(taskId); err != nil {
+				return object, currentStatus, WrapError(err)
+			}
+			object["state"] = "paused"
+			return object, "paused", WrapError(fmt.Errorf("task %s has been paused after nodes failed: %s. Inspect the nodes, then resume or cancel the task before applying again",
+				taskId, ackNodepoolTaskFailureDetail(object, failedNodes)))
+		}
+		return object, currentStatus, nil
+	}
+}
+
+// PauseAckTask pauses a running cluster task, such as a rolling update of a node pool.
+func (s *AckServiceV2) PauseAckTask(taskId string) (err error) {
+	client := s.client
+	var response map[string]interface{}
+	action := fmt.Sprintf("/tasks/%s/pause", taskId)
+	wait := incrementalWait(3*time.Second, 5*time.Second)
+	err = resource.Retry(1*time.Minute, func() *resource.RetryError {
+		response, err = client.RoaPost("CS", "2015-12-15", action, nil, nil, nil, true)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug(action, response, nil)
+	if err != nil {
+		return WrapErrorf(err, DefaultErrorMsg, taskId, action, AlibabaCloudSdkGoERROR)
+	}
+	return nil
+}
+
+// ackNodepoolTaskProgress summarizes the stage of a task and how many of its nodes are done.
+func ackNodepoolTaskProgress(object map[string]interface{}) string {
+	succeeded, failed := 0, 0
+	results, _ := object["task_result"].([]interface{})
+	for _, item := range results {
+		if result, ok := item.(map[string]interface{}); ok {
+			switch strings.ToLower(fmt.Sprint(result["status"])) {
+			case "success", "succeeded":
+				succeeded++
+			case "fail", "failed":
+				failed++
+			}
+		}
+	}
+	progress := fmt.Sprintf("state %v", object["state"])
+	if stages, ok := object["stages"].([]interface{}); ok && len(stages) > 0 {
+		finished := 0
+		for _, item := range stages {
+			if stage, ok := item.(map[string]interface{}); ok && fmt.Sprint(stage["state"]) == "success" {
+				finished++
+			}
+		}
+		progress += fmt.Sprintf(", batch %d/%d", finished, len(stages))
+	}
+	if stage, ok := object["current_stage"].(string); ok && stage != "" {
+		progress += fmt.Sprintf(" (%s)", stage)
+	}
+	return progress + fmt.Sprintf(", %d nodes succeeded, %d nodes failed", succeeded, failed)
+}
+
+// ackNodepoolTaskFailedNodes returns the nodes of a task that failed, paired with the error reported for them. The
+// error comes from the task result when it has one, otherwise from the latest event that mentions the node.
+func ackNodepoolTaskFailedNodes(object map[string]interface{}) [][2]string {
+	failedNodes := make([][2]string, 0)
+	results, _ := object["task_result"].([]interface{})
+	events, _ := object["events"].([]interface{})
+	for _, item := range results {
+		result, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if status := strings.ToLower(fmt.Sprint(result["status"])); status != "fail" && status != "failed" {
+			continue
+		}
+		node := fmt.Sprint(result["data"])
+		message := ""
+		if v, ok := result["message"].(string); ok {
+			message = v
+		}
+		for i := len(events) - 1; i >= 0 && message == ""; i-- {
+			if event, ok := events[i].(map[string]interface{}); ok && strings.Contains(fmt.Sprint(event["message"]), node) {
+				message = fmt.Sprint(event["message"])
+			}
+		}
+		failedNodes = append(failedNodes, [2]string{node, message})
+	}
+	return failedNodes
+}
+
+func ackNodepoolTaskFailureDetail(object map[string]interface{}, failedNodes [][2]string) string {
+	details := make([]string, 0, len(failedNodes))
+	for _, node := range failedNodes {
+		if node[1] == "" {
+			details = append(details, node[0])
+		} else {
+			details = append(details, fmt.Sprintf("%s (%s)", node[0], node[1]))
+		}
+	}
+	if len(details) == 0 {
+		details = append(details, "none reported")
+	}
+	detail := strings.Join(details, ", ")
+	if taskError, ok := object["error"].(map[string]interface{}); ok && taskError["message"] != nil {
+		detail += fmt.Sprintf("; %v: %v", taskError["code"], taskError["message"])
+	}
+	return detail
+}
+
 // DescribeAckPolicyInstance <<< Encapsulated get interface for Ack PolicyInstance.
 func (s *AckServiceV2) DescribeAckPolicyInstance(id string) (object map[string]interface{}, err error) {
 	client := s.client
@@ -1,7 +1,9 @@
 package alicloud
 
 import (
+	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/PaesslerAG/jsonpath"
@@ -331,84 +333,188 @@ func (s *BssOpenApiService) QueryAvailableInstanceList(instanceRegion, productCo
 	return v.([]interface{}), nil
 }
 
+// bssOpenApiPriceCache keeps the prices quoted during a run, so the same configuration is only priced once. Failed
+// quotes are not kept, and are tried again by the next caller.
+var bssOpenApiPriceCache sync.Map
+
+type bssOpenApiPriceEntry struct {
+	mutex sync.Mutex
+	data  map[string]interface{}
+}
+
+// bssOpenApiPriceOptions is a new order quoted by GetPrice.
+type bssOpenApiPriceOptions struct {
+	ProductCode string
+	ProductType string
+	// SubscriptionType is Subscription or PayAsYouGo.
+	SubscriptionType string
+	// Period and PeriodUnit are the duration of a Subscription order, one Month when Period is 0, and Quantity the
+	// number of instances ordered, one when it is 0.
+	Period     int
+	PeriodUnit string
+	Quantity   int
+	Modules    []map[string]interface{}
+}
+
+// GetInstanceTypePrice returns the list price of every module for one month of a Subscription order, or one hour of a
+// PayAsYouGo order, in module order.
 func (s *BssOpenApiService) GetInstanceTypePrice(productCode, productType, paymentType string, modules []map[string]interface{}) (object []float64, err error) {
+	data, err := s.GetPrice(bssOpenApiPriceOptions{
+		ProductCode:      productCode,
+		ProductType:      productType,
+		SubscriptionType: paymentType,
+		Modules:          modules,
+	})
+	if err != nil {
+		return object, err
+	}
+	return bssOpenApiOriginalCosts(data), nil
+}
+
+// GetPrice quotes a new order, at most ModulesSizeLimit modules per request. A PayAsYouGo order is quoted for one hour
+// of the modules whose PriceType is Hour. It returns the Data of every response in the order of the modules, and
+// responses are cached for the rest of the run.
+func (s *BssOpenApiService) GetPrice(options bssOpenApiPriceOptions) (object []map[string]interface{}, err error) {
+	if options.Period <= 0 {
+		options.Period, options.PeriodUnit = 1, "Month"
+	}
+	if options.Quantity <= 0 {
+		options.Quantity = 1
+	}
+	modules := options.Modules
+	for start := 0; start < len(modules); start += ModulesSizeLimit {
+		end := start + ModulesSizeLimit
+		if end > len(modules) {
+			end = len(modules)
+		}
+		options.Modules = modules[start:end]
+		data, err := s.getPrice(options)
+		if err != nil {
+			return object, err
+		}
+		object = append(object, data)
+	}
+	return object, nil
+}
+
+func (s *BssOpenApiService) getPrice(options bssOpenApiPriceOptions) (map[string]interface{}, error) {
 	client := s.client
-	var response map[string]interface{}
-	var endpoint string
-	var priceList []float64
-	action := "GetSubscriptionPrice"
+	action := "GetPayAsYouGoPrice"
 	request := map[string]interface{}{
-		"OrderType":        "NewOrder",
-		"SubscriptionType": "Subscription",
 		"Region":           client.RegionId,
-		"ProductCode":      productCode,
-		"ProductType":      productType,
+		"ProductCode":      options.ProductCode,
+		"SubscriptionType": options.SubscriptionType,
+		"ModuleList":       options.Modules,
 	}
-
-	if paymentType == "Subscription" {
-		request["ServicePeriodQuantity"] = 1
-		request["ServicePeriodUnit"] = "Month"
-		request["Quantity"] = 1
+	if options.ProductType != "" {
+		request["ProductType"] = options.ProductType
 	}
-
-	if paymentType == "PayAsYouGo" {
-		action = "GetPayAsYouGoPrice"
-		request["SubscriptionType"] = "PayAsYouGo"
+	if options.SubscriptionType == "Subscription" {
+		action = "GetSubscriptionPrice"
+		request["OrderType"] = "NewOrder"
+		request["ServicePeriodQuantity"] = options.Period
+		request["ServicePeriodUnit"] = options.PeriodUnit
+		request["Quantity"] = options.Quantity
 	}
 
-	moduleLength := len(modules)
-
-	for {
-		if len(modules) < ModulesSizeLimit {
-			moduleLength = len(modules)
-
-			request["ModuleList"] = modules
-		} else {
-			tmp := modules[:ModulesSizeLimit]
-			modules = modules[ModulesSizeLimit:]
-			moduleLength = len(tmp)
+	key, err := json.Marshal([]interface{}{client.AccessKey, action, request})
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	v, _ := bssOpenApiPriceCache.LoadOrStore(string(key), &bssOpenApiPriceEntry{})
+	entry := v.(*bssOpenApiPriceEntry)
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+	if entry.data != nil {
+		return entry.data, nil
+	}
 
-			request["ModuleList"] = &tmp
-		}
-		wait := incrementalWait(3*time.Second, 3*time.Second)
-		err = resource.Retry(5*time.Minute, func() *resource.RetryError {
-			response, err = client.RpcPostWithEndpoint("BssOpenApi", "2017-12-14", action, nil, request, true, endpoint)
-			if err != nil {
-				if NeedRetry(err) {
-					wait()
-					return resource.RetryableError(err)
-				}
-				if !client.IsInternationalAccount() && IsExpectedErrors(err, []string{"NotApplicable"}) {
-					endpoint = connectivity.BssOpenAPIEndpointInternational
-					return resource.RetryableError(err)
-				}
-				return resource.NonRetryableError(err)
+	var response map[string]interface{}
+	var endpoint string
+	wait := incrementalWait(3*time.Second, 3*time.Second)
+	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+		var callErr error
+		response, callErr = client.RpcPostWithEndpoint("BssOpenApi", "2017-12-14", action, nil, request, true, endpoint)
+		if callErr != nil {
+			if NeedRetry(callErr) {
+				wait()
+				return resource.RetryableError(callErr)
 			}
-			return nil
-		})
-		addDebug(action, response, request)
-
-		if err != nil {
-			return object, WrapError(err)
+			if !client.IsInternationalAccount() && IsExpectedErrors(callErr, []string{"NotApplicable"}) {
+				endpoint = connectivity.BssOpenAPIEndpointInternational
+				return resource.RetryableError(callErr)
+			}
+			return resource.NonRetryableError(callErr)
 		}
+		return nil
+	})
+	addDebug(action, response, request)
+	if err != nil {
+		return nil, WrapErrorf(err, DefaultErrorMsg, options.ProductCode, action, AlibabaCloudSdkGoERROR)
+	}
+	data, _ := response["Data"].(map[string]interface{})
+	if data == nil {
+		return nil, WrapErrorf(Error(GetNotFoundMessage("Price", options.ProductCode)), NotFoundWithResponse, response)
+	}
+	entry.data = data
+	return data, nil
+}
 
-		v, err := jsonpath.Get("$.Data.ModuleDetails.ModuleDetail", response)
+// DescribePricingModules returns the pricing modules of a product, with their code, price type and the configuration
+// they are priced by.
+func (s *BssOpenApiService) DescribePricingModules(productCode, productType, subscriptionType string) (object []interface{}, err error) {
+	client := s.client
+	var response map[string]interface{}
+	var endpoint string
+	action := "DescribePricingModule"
+	request := map[string]interface{}{
+		"ProductCode":      productCode,
+		"SubscriptionType": subscriptionType,
+	}
+	if productType != "" {
+		request["ProductType"] = productType
+	}
+	wait := incrementalWait(3*time.Second, 3*time.Second)
+	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+		response, err = client.RpcPostWithEndpoint("BssOpenApi", "2017-12-14", action, nil, request, true, endpoint)
 		if err != nil {
-			return object, WrapError(err)
-		}
-
-		for _, originalCosts := range v.([]interface{}) {
-			originalCostArg := originalCosts.(map[string]interface{})
-
-			if originalCost, ok := originalCostArg["OriginalCost"]; ok {
-				priceList = append(priceList, formatFloat64(originalCost))
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
 			}
+			if !client.IsInternationalAccount() && IsExpectedErrors(err, []string{"NotApplicable"}) {
+				endpoint = connectivity.BssOpenAPIEndpointInternational
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
 		}
+		return nil
+	})
+	addDebug(action, response, request)
+	if err != nil {
+		return object, WrapErrorf(err, DefaultErrorMsg, productCode, action, AlibabaCloudSdkGoERROR)
+	}
+	v, err := jsonpath.Get("$.Data.ModuleList.Module", response)
+	if err != nil {
+		return object, WrapErrorf(err, FailedGetAttributeMsg, productCode, "$.Data.ModuleList.Module", response)
+	}
+	object, _ = v.([]interface{})
+	return object, nil
+}
 
-		if moduleLength < ModulesSizeLimit {
-			break
+// bssOpenApiOriginalCosts returns the list price of every module quoted by GetPrice, in module order.
+func bssOpenApiOriginalCosts(data []map[string]interface{}) []float64 {
+	priceList := make([]float64, 0)
+	for _, item := range data {
+		details, _ := item["ModuleDetails"].(map[string]interface{})
+		modules, _ := details["ModuleDetail"].([]interface{})
+		for _, raw := range modules {
+			if module, ok := raw.(map[string]interface{}); ok {
+				if originalCost, ok := module["OriginalCost"]; ok {
+					priceList = append(priceList, formatFloat64(originalCost))
+				}
+			}
 		}
 	}
-
-	return priceList, nil
+	return priceList
 }
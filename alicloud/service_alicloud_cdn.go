@@ -110,6 +110,73 @@ func (s *CdnService) DescribeCdnDomainConfig(id string) (object interface{}, err
 	return val, nil
 }
 
+// DescribeCdnDomainConfigs returns every config function of a domain.
+func (s *CdnService) DescribeCdnDomainConfigs(id string) (object []interface{}, err error) {
+	var response map[string]interface{}
+	client := s.client
+	action := "DescribeCdnDomainConfigs"
+
+	request := map[string]interface{}{
+		"RegionId":   s.client.RegionId,
+		"DomainName": id,
+	}
+
+	wait := incrementalWait(3*time.Second, 3*time.Second)
+	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+		response, err = client.RpcPost("Cdn", "2018-05-10", action, nil, request, true)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug(action, response, request)
+
+	if err != nil {
+		if IsExpectedErrors(err, []string{"InvalidDomain.NotFound"}) {
+			return object, WrapErrorf(err, NotFoundMsg, AlibabaCloudSdkGoERROR)
+		}
+		return object, WrapErrorf(err, DefaultErrorMsg, id, action, AlibabaCloudSdkGoERROR)
+	}
+
+	v, err := jsonpath.Get("$.DomainConfigs.DomainConfig", response)
+	if err != nil {
+		return object, WrapErrorf(err, FailedGetAttributeMsg, id, "$.DomainConfigs.DomainConfig", response)
+	}
+
+	return v.([]interface{}), nil
+}
+
+// CdnDomainConfigsStateRefreshFunc reports "success" once every config function of a domain is applied.
+func (s *CdnService) CdnDomainConfigsStateRefreshFunc(id string, failStates []string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		object, err := s.DescribeCdnDomainConfigs(id)
+		if err != nil {
+			if NotFoundError(err) {
+				return nil, "", nil
+			}
+			return nil, "", WrapError(err)
+		}
+
+		status := "success"
+		for _, v := range object {
+			config := v.(map[string]interface{})
+			for _, failState := range failStates {
+				if fmt.Sprint(config["Status"]) == failState {
+					return object, failState, WrapError(Error(FailedToReachTargetStatus, fmt.Sprintf("%s of %s", failState, config["FunctionName"])))
+				}
+			}
+			if fmt.Sprint(config["Status"]) != "success" {
+				status = fmt.Sprint(config["Status"])
+			}
+		}
+		return object, status, nil
+	}
+}
+
 func (c *CdnService) WaitForCdnDomain(id string, status Status, timeout int) error {
 	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
 	time.Sleep(DefaultIntervalShort * time.Second)
@@ -1156,3 +1156,104 @@ func (s *EcsServiceV2) DescribeEcsDataDisksByNodeId(nodeId string) (objects []ma
 
 	return objects, nil
 }
+
+// DescribeEcsInstanceType returns the specification of an instance type, including its CpuCoreCount.
+func (s *EcsServiceV2) DescribeEcsInstanceType(instanceType string) (object map[string]interface{}, err error) {
+	client := s.client
+	var response map[string]interface{}
+	action := "DescribeInstanceTypes"
+	request := map[string]interface{}{
+		"InstanceTypes": []string{instanceType},
+	}
+	wait := incrementalWait(3*time.Second, 3*time.Second)
+	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+		response, err = client.RpcPost("Ecs", "2014-05-26", action, nil, request, true)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug(action, response, request)
+	if err != nil {
+		return object, WrapErrorf(err, DefaultErrorMsg, instanceType, action, AlibabaCloudSdkGoERROR)
+	}
+	v, err := jsonpath.Get("$.InstanceTypes.InstanceType", response)
+	if err != nil {
+		return object, WrapErrorf(err, FailedGetAttributeMsg, instanceType, "$.InstanceTypes.InstanceType", response)
+	}
+	for _, item := range v.([]interface{}) {
+		if itemMap, ok := item.(map[string]interface{}); ok && fmt.Sprint(itemMap["InstanceTypeId"]) == instanceType {
+			return itemMap, nil
+		}
+	}
+	return object, WrapErrorf(NotFoundErr("InstanceType", instanceType), NotFoundMsg, response)
+}
+
+// DescribeEcsInstanceTypeStock returns the stock of an instance type in a zone, as the StatusCategory the
+// DescribeAvailableResource API reports: WithStock, ClosedWithStock, WithoutStock or ClosedWithoutStock. It returns an
+// empty string when the zone does not offer the instance type at all.
+func (s *EcsServiceV2) DescribeEcsInstanceTypeStock(zoneId, instanceType, instanceChargeType string) (string, error) {
+	client := s.client
+	var response map[string]interface{}
+	var err error
+	action := "DescribeAvailableResource"
+	id := fmt.Sprintf("%s:%s", zoneId, instanceType)
+	request := map[string]interface{}{
+		"RegionId":            client.RegionId,
+		"ZoneId":              zoneId,
+		"InstanceType":        instanceType,
+		"InstanceChargeType":  instanceChargeType,
+		"DestinationResource": "InstanceType",
+	}
+	wait := incrementalWait(3*time.Second, 3*time.Second)
+	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+		response, err = client.RpcPost("Ecs", "2014-05-26", action, nil, request, true)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug(action, response, request)
+	if err != nil {
+		return "", WrapErrorf(err, DefaultErrorMsg, id, action, AlibabaCloudSdkGoERROR)
+	}
+	v, err := jsonpath.Get("$.AvailableZones.AvailableZone[*].AvailableResources.AvailableResource[*].SupportedResources.SupportedResource[*]", response)
+	if err != nil {
+		return "", nil
+	}
+	for _, supported := range flattenEcsNestedList(v) {
+		if fmt.Sprint(supported["Value"]) != instanceType {
+			continue
+		}
+		if category, ok := supported["StatusCategory"].(string); ok && category != "" {
+			return category, nil
+		}
+		if fmt.Sprint(supported["Status"]) == "Available" {
+			return "WithStock", nil
+		}
+		return "WithoutStock", nil
+	}
+	return "", nil
+}
+
+// flattenEcsNestedList flattens the nested lists a wildcard JSON path returns into their maps.
+func flattenEcsNestedList(v interface{}) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	switch value := v.(type) {
+	case []interface{}:
+		for _, item := range value {
+			result = append(result, flattenEcsNestedList(item)...)
+		}
+	case map[string]interface{}:
+		result = append(result, value)
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package alicloud
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"strconv"
 	"strings"
 	"time"
@@ -585,6 +586,98 @@ func (s *EssService) DescribeEssInstanceRefresh(id string) (object map[string]in
 	return object, nil
 }
 
+// EssInstanceRefreshStateRefreshFunc refreshes an instance refresh task and logs its progress on
+// every poll, since a refresh of a large group can take hours.
+func (s *EssService) EssInstanceRefreshStateRefreshFunc(id string, failStates []string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		object, err := s.DescribeEssInstanceRefresh(id)
+		if err != nil {
+			if NotFoundError(err) {
+				return nil, "", nil
+			}
+			return nil, "", WrapError(err)
+		}
+		status := fmt.Sprint(object["Status"])
+		log.Printf("[INFO] ESS instance refresh %s is %s: %s", id, status, essInstanceRefreshProgress(object))
+		for _, failState := range failStates {
+			if status == failState {
+				return object, status, WrapError(Error(FailedToReachTargetStatus, status))
+			}
+		}
+		return object, status, nil
+	}
+}
+
+// essInstanceRefreshProgress describes how far a refresh task has got: the refreshed capacity and
+// the next checkpoint it pauses at.
+func essInstanceRefreshProgress(object map[string]interface{}) string {
+	finished := formatInt(object["FinishedUpdateCapacity"])
+	total := formatInt(object["TotalNeedUpdateCapacity"])
+	progress := fmt.Sprintf("%d/%d instances refreshed", finished, total)
+	if total <= 0 {
+		return progress
+	}
+	percentage := finished * 100 / total
+	if v, err := jsonpath.Get("$.Checkpoints.Checkpoint", object); err == nil {
+		checkpoints, _ := v.([]interface{})
+		for _, c := range checkpoints {
+			checkpoint, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if p := formatInt(checkpoint["Percentage"]); p > percentage {
+				return fmt.Sprintf("%s, next checkpoint at %d%%", progress, p)
+			}
+		}
+	}
+	return progress
+}
+
+// DescribeEssScalingGroupHealthyPercentage returns the share of in service instances of a scaling
+// group that are healthy. A group without in service instances is reported as fully healthy.
+func (s *EssService) DescribeEssScalingGroupHealthyPercentage(scalingGroupId string) (int, error) {
+	client := s.client
+	action := "DescribeScalingInstances"
+	request := map[string]interface{}{
+		"RegionId":       s.client.RegionId,
+		"ScalingGroupId": scalingGroupId,
+		"LifecycleState": "InService",
+		"PageSize":       PageSizeLarge,
+		"PageNumber":     1,
+	}
+	healthy, total := 0, 0
+	for {
+		response, err := client.RpcPost("Ess", "2014-08-28", action, nil, request, true)
+		if err != nil {
+			return 0, WrapErrorf(err, DefaultErrorMsg, scalingGroupId, action, AlibabaCloudSdkGoERROR)
+		}
+		addDebug(action, response, request)
+		v, err := jsonpath.Get("$.ScalingInstances.ScalingInstance", response)
+		if err != nil {
+			return 0, WrapErrorf(err, FailedGetAttributeMsg, scalingGroupId, "$.ScalingInstances.ScalingInstance", response)
+		}
+		instances, _ := v.([]interface{})
+		for _, i := range instances {
+			instance, ok := i.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			total++
+			if fmt.Sprint(instance["HealthStatus"]) == "Healthy" {
+				healthy++
+			}
+		}
+		if len(instances) < PageSizeLarge {
+			break
+		}
+		request["PageNumber"] = request["PageNumber"].(int) + 1
+	}
+	if total == 0 {
+		return 100, nil
+	}
+	return healthy * 100 / total, nil
+}
+
 func (s *EssService) DescribeEssScalingRuleWithAlarm(id string) (object map[string]interface{}, err error) {
 	var response map[string]interface{}
 	client := s.client
@@ -1036,3 +1129,291 @@ func (s *EssService) ListTagResources(scalingGroupId string, client *connectivit
 	}
 	return tags, nil
 }
+
+// DescribeEssScalingInstanceIds returns the ids of the instances of a scaling group that are in the
+// given lifecycle state.
+func (s *EssService) DescribeEssScalingInstanceIds(scalingGroupId, lifecycleState string) ([]string, error) {
+	client := s.client
+	action := "DescribeScalingInstances"
+	request := map[string]interface{}{
+		"RegionId":       s.client.RegionId,
+		"ScalingGroupId": scalingGroupId,
+		"LifecycleState": lifecycleState,
+		"PageSize":       PageSizeLarge,
+		"PageNumber":     1,
+	}
+	ids := make([]string, 0)
+	for {
+		response, err := client.RpcPost("Ess", "2014-08-28", action, nil, request, true)
+		if err != nil {
+			return ids, WrapErrorf(err, DefaultErrorMsg, scalingGroupId, action, AlibabaCloudSdkGoERROR)
+		}
+		addDebug(action, response, request)
+		v, err := jsonpath.Get("$.ScalingInstances.ScalingInstance", response)
+		if err != nil {
+			return ids, WrapErrorf(err, FailedGetAttributeMsg, scalingGroupId, "$.ScalingInstances.ScalingInstance", response)
+		}
+		instances, _ := v.([]interface{})
+		for _, i := range instances {
+			if instance, ok := i.(map[string]interface{}); ok {
+				ids = append(ids, fmt.Sprint(instance["InstanceId"]))
+			}
+		}
+		if len(instances) < PageSizeLarge {
+			break
+		}
+		request["PageNumber"] = request["PageNumber"].(int) + 1
+	}
+	return ids, nil
+}
+
+// DescribeEssWarmPoolInstances returns the standby instances of a scaling group, which make up its
+// warm pool, mapped to their ECS status.
+func (s *EssService) DescribeEssWarmPoolInstances(scalingGroupId string) (map[string]string, error) {
+	client := s.client
+	pool := make(map[string]string)
+	ids, err := s.DescribeEssScalingInstanceIds(scalingGroupId, "Standby")
+	if err != nil {
+		return pool, WrapError(err)
+	}
+	action := "DescribeInstances"
+	for start := 0; start < len(ids); start += PageSizeXLarge {
+		end := start + PageSizeXLarge
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batch := make([]interface{}, 0, end-start)
+		for _, id := range ids[start:end] {
+			batch = append(batch, id)
+			pool[id] = ""
+		}
+		request := map[string]interface{}{
+			"RegionId":    s.client.RegionId,
+			"InstanceIds": convertListToJsonString(batch),
+			"PageSize":    PageSizeXLarge,
+		}
+		response, err := client.RpcPost("Ecs", "2014-05-26", action, nil, request, true)
+		if err != nil {
+			return pool, WrapErrorf(err, DefaultErrorMsg, scalingGroupId, action, AlibabaCloudSdkGoERROR)
+		}
+		addDebug(action, response, request)
+		v, err := jsonpath.Get("$.Instances.Instance", response)
+		if err != nil {
+			return pool, WrapErrorf(err, FailedGetAttributeMsg, scalingGroupId, "$.Instances.Instance", response)
+		}
+		for _, i := range v.([]interface{}) {
+			if instance, ok := i.(map[string]interface{}); ok {
+				pool[fmt.Sprint(instance["InstanceId"])] = fmt.Sprint(instance["Status"])
+			}
+		}
+	}
+	return pool, nil
+}
+
+// essWarmPoolInstanceState reports the state shared by all warm pool instances, or an empty string
+// when the pool is empty or its instances are in different states.
+func essWarmPoolInstanceState(pool map[string]string) string {
+	state := ""
+	for _, status := range pool {
+		if state != "" && status != state {
+			return ""
+		}
+		state = status
+	}
+	return state
+}
+
+// EssScaleWithAdjustment changes the capacity of a scaling group by adjustment instances, waits
+// for the scaling activity to finish and returns the instances it created. Activities that only
+// partially succeeded are not an error, callers check the capacity they need afterwards.
+func (s *EssService) EssScaleWithAdjustment(scalingGroupId string, adjustment int, timeout time.Duration) ([]string, error) {
+	client := s.client
+	action := "ScaleWithAdjustment"
+	var response map[string]interface{}
+	request := map[string]interface{}{
+		"RegionId":        s.client.RegionId,
+		"ScalingGroupId":  scalingGroupId,
+		"AdjustmentType":  "QuantityChangeInCapacity",
+		"AdjustmentValue": adjustment,
+		"ClientToken":     buildClientToken(action),
+	}
+	wait := incrementalWait(3*time.Second, 5*time.Second)
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		var err error
+		response, err = client.RpcPost("Ess", "2014-08-28", action, nil, request, true)
+		if err != nil {
+			if NeedRetry(err) || IsExpectedErrors(err, []string{"ScalingActivityInProgress", "IncorrectScalingGroupStatus"}) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug(action, response, request)
+	if err != nil {
+		return nil, WrapErrorf(err, DefaultErrorMsg, scalingGroupId, action, AlibabaCloudSdkGoERROR)
+	}
+	stateConf := BuildTracedStateConf(client, []string{}, []string{"Successful", "Warning"}, timeout, 1*time.Minute, s.ActivityStateRefreshFunc(fmt.Sprint(response["ScalingActivityId"]), []string{"Failed", "Rejected"}))
+	activity, err := stateConf.WaitForState()
+	if err != nil {
+		return nil, WrapErrorf(err, IdMsg, scalingGroupId)
+	}
+	return essCreatedInstanceIds(activity), nil
+}
+
+// essCreatedInstanceIds returns the instances a scaling activity created.
+func essCreatedInstanceIds(activity interface{}) []string {
+	instanceIds := make([]string, 0)
+	if v, ok := activity.(ess.ScalingActivity); ok {
+		instanceIds = append(instanceIds, v.CreatedInstances.CreatedInstance...)
+	}
+	return instanceIds
+}
+
+// EssEnterStandby moves in service instances of a scaling group into the standby state and waits
+// until all of them got there.
+func (s *EssService) EssEnterStandby(scalingGroupId string, instanceIds []string, timeout time.Duration) error {
+	client := s.client
+	action := "EnterStandby"
+	for start := 0; start < len(instanceIds); start += 20 {
+		end := start + 20
+		if end > len(instanceIds) {
+			end = len(instanceIds)
+		}
+		request := map[string]interface{}{
+			"RegionId":       s.client.RegionId,
+			"ScalingGroupId": scalingGroupId,
+			"InstanceId":     instanceIds[start:end],
+		}
+		var response map[string]interface{}
+		wait := incrementalWait(3*time.Second, 5*time.Second)
+		err := resource.Retry(timeout, func() *resource.RetryError {
+			var err error
+			response, err = client.RpcPost("Ess", "2014-08-28", action, nil, request, false)
+			if err != nil {
+				if NeedRetry(err) || IsExpectedErrors(err, []string{"ScalingActivityInProgress", "IncorrectScalingGroupStatus"}) {
+					wait()
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		})
+		addDebug(action, response, request)
+		if err != nil {
+			return WrapErrorf(err, DefaultErrorMsg, scalingGroupId, action, AlibabaCloudSdkGoERROR)
+		}
+	}
+	stateConf := BuildTracedStateConf(client, []string{}, []string{"Standby"}, timeout, 5*time.Second, func() (interface{}, string, error) {
+		pool, err := s.DescribeEssWarmPoolInstances(scalingGroupId)
+		if err != nil {
+			return nil, "", WrapError(err)
+		}
+		for _, id := range instanceIds {
+			if _, ok := pool[id]; !ok {
+				return pool, "EnteringStandby", nil
+			}
+		}
+		return pool, "Standby", nil
+	})
+	if _, err := stateConf.WaitForState(); err != nil {
+		return WrapErrorf(err, IdMsg, scalingGroupId)
+	}
+	return nil
+}
+
+// EssSetWarmPoolInstanceState starts or stops the given warm pool instances and waits until all of
+// them are in the requested ECS status. Instances are stopped in economical mode, so a stopped
+// warm pool is not charged for vCPU and memory.
+func (s *EssService) EssSetWarmPoolInstanceState(scalingGroupId string, instanceIds []string, status string, timeout time.Duration) error {
+	if len(instanceIds) == 0 {
+		return nil
+	}
+	client := s.client
+	action := "StartInstances"
+	if status == "Stopped" {
+		action = "StopInstances"
+	}
+	for start := 0; start < len(instanceIds); start += PageSizeXLarge {
+		end := start + PageSizeXLarge
+		if end > len(instanceIds) {
+			end = len(instanceIds)
+		}
+		request := map[string]interface{}{
+			"RegionId":   s.client.RegionId,
+			"InstanceId": instanceIds[start:end],
+		}
+		if action == "StopInstances" {
+			request["StoppedMode"] = "StopCharging"
+		}
+		var response map[string]interface{}
+		wait := incrementalWait(3*time.Second, 5*time.Second)
+		err := resource.Retry(timeout, func() *resource.RetryError {
+			var err error
+			response, err = client.RpcPost("Ecs", "2014-05-26", action, nil, request, false)
+			if err != nil {
+				if NeedRetry(err) || IsExpectedErrors(err, []string{"IncorrectInstanceStatus"}) {
+					wait()
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		})
+		addDebug(action, response, request)
+		if err != nil {
+			return WrapErrorf(err, DefaultErrorMsg, scalingGroupId, action, AlibabaCloudSdkGoERROR)
+		}
+	}
+	stateConf := BuildTracedStateConf(client, []string{}, []string{status}, timeout, 5*time.Second, func() (interface{}, string, error) {
+		pool, err := s.DescribeEssWarmPoolInstances(scalingGroupId)
+		if err != nil {
+			return nil, "", WrapError(err)
+		}
+		for _, id := range instanceIds {
+			if current, ok := pool[id]; ok && current != status {
+				return pool, current, nil
+			}
+		}
+		return pool, status, nil
+	})
+	if _, err := stateConf.WaitForState(); err != nil {
+		return WrapErrorf(err, IdMsg, scalingGroupId)
+	}
+	return nil
+}
+
+// EssScalingGroupCapacityStateRefreshFunc reports a scaling group as Settled once it has no
+// instances being added or removed, its warm pool holds the standby instances, and the instances
+// out of the pool match its desired capacity. It reports Scaling before that.
+func (s *EssService) EssScalingGroupCapacityStateRefreshFunc(id string, standby int) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		object, err := s.DescribeEssScalingGroupById(id)
+		if err != nil {
+			if NotFoundError(err) {
+				return nil, "", nil
+			}
+			return nil, "", WrapError(err)
+		}
+		return object, essScalingGroupCapacityState(object, standby), nil
+	}
+}
+
+// essScalingGroupCapacityState is the state EssScalingGroupCapacityStateRefreshFunc reports for a
+// scaling group. Standby instances are not counted against the desired capacity, as they make up
+// the warm pool.
+func essScalingGroupCapacityState(object map[string]interface{}, standby int) string {
+	for _, key := range []string{"PendingCapacity", "RemovingCapacity", "PendingWaitCapacity", "RemovingWaitCapacity"} {
+		if formatInt(object[key]) > 0 {
+			return "Scaling"
+		}
+	}
+	if formatInt(object["StandbyCapacity"]) != standby {
+		return "Scaling"
+	}
+	if object["DesiredCapacity"] != nil && formatInt(object["TotalCapacity"])-formatInt(object["StandbyCapacity"]) != formatInt(object["DesiredCapacity"]) {
+		return "Scaling"
+	}
+	return "Settled"
+}
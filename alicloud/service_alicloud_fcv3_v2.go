@@ -1,14 +1,25 @@
 package alicloud
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"hash/crc64"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/PaesslerAG/jsonpath"
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/mitchellh/go-homedir"
 )
 
 type Fcv3ServiceV2 struct {
@@ -800,3 +811,143 @@ func (s *Fcv3ServiceV2) SetResourceTags(d *schema.ResourceData, resourceType str
 }
 
 // SetResourceTags >>> tag function encapsulated.
+
+// fcv3CodeInlineSizeLimit is the largest code package sent inline as a base64 zipFile. Larger packages
+// are staged as a temporary object in code.oss_bucket_name.
+const fcv3CodeInlineSizeLimit = 50 * 1024 * 1024
+
+const fcv3CodeStagingPrefix = "terraform-fcv3-code/"
+
+// buildFcv3CodePackage zips the files of sourceDir that do not match excludes. Entries are sorted and
+// carry a fixed timestamp, so the same content always produces the same package and checksum. The
+// checksum is the CRC64 of the package, as FC reports it in codeChecksum.
+func buildFcv3CodePackage(sourceDir string, excludes []string) ([]byte, string, error) {
+	root, err := homedir.Expand(sourceDir)
+	if err != nil {
+		return nil, "", WrapError(err)
+	}
+	files := make([]string, 0)
+	err = filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+		if !globMatchAny(excludes, filepath.ToSlash(rel)) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("reading source_dir %s failed: %v", sourceDir, err)
+	}
+	if len(files) == 0 {
+		return nil, "", fmt.Errorf("source_dir %s does not contain any file to package", sourceDir)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return filepath.ToSlash(files[i]) < filepath.ToSlash(files[j])
+	})
+
+	buf := new(bytes.Buffer)
+	writer := zip.NewWriter(buf)
+	for _, rel := range files {
+		info, err := os.Stat(filepath.Join(root, rel))
+		if err != nil {
+			return nil, "", WrapError(err)
+		}
+		header := &zip.FileHeader{
+			Name:     filepath.ToSlash(rel),
+			Method:   zip.Deflate,
+			Modified: time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+		// Only the executable bit is kept, bootstrap files of custom runtimes depend on it.
+		if info.Mode()&0111 != 0 {
+			header.SetMode(0755)
+		} else {
+			header.SetMode(0644)
+		}
+		entry, err := writer.CreateHeader(header)
+		if err != nil {
+			return nil, "", WrapError(err)
+		}
+		file, err := os.Open(filepath.Join(root, rel))
+		if err != nil {
+			return nil, "", WrapError(err)
+		}
+		_, err = io.Copy(entry, file)
+		file.Close()
+		if err != nil {
+			return nil, "", WrapError(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", WrapError(err)
+	}
+	return buf.Bytes(), fcv3CodeChecksum(buf.Bytes()), nil
+}
+
+// fcv3CodeChecksum returns the CRC64 of a code package in the format of codeChecksum: the unsigned decimal of its
+// CRC-64/XZ, which is what crc64.ECMA computes.
+func fcv3CodeChecksum(pkg []byte) string {
+	return fmt.Sprintf("%d", crc64.Checksum(pkg, crc64.MakeTable(crc64.ECMA)))
+}
+
+// Fcv3CodeChecksum returns the checksum of the package built from code.source_dir, or "" if the code
+// block does not set source_dir.
+func Fcv3CodeChecksum(code interface{}) (string, error) {
+	sourceDir, _ := jsonpath.Get("$[0].source_dir", code)
+	if sourceDir == nil || sourceDir == "" {
+		return "", nil
+	}
+	excludes, _ := jsonpath.Get("$[0].excludes", code)
+	_, checksum, err := buildFcv3CodePackage(fmt.Sprint(sourceDir), expandStringList(convertToInterfaceArray(excludes)))
+	return checksum, err
+}
+
+// ExpandFcv3SourceCode packages code.source_dir into the code parameter of the FC API. The package is sent
+// inline when it is small enough, otherwise it is uploaded to code.oss_bucket_name and the returned
+// function deletes the staged object once the API has consumed it.
+func (s *Fcv3ServiceV2) ExpandFcv3SourceCode(code interface{}) (map[string]interface{}, func(), error) {
+	sourceDir, _ := jsonpath.Get("$[0].source_dir", code)
+	excludes, _ := jsonpath.Get("$[0].excludes", code)
+	pkg, checksum, err := buildFcv3CodePackage(fmt.Sprint(sourceDir), expandStringList(convertToInterfaceArray(excludes)))
+	if err != nil {
+		return nil, nil, err
+	}
+	result := map[string]interface{}{
+		"checksum": checksum,
+	}
+	if base64.StdEncoding.EncodedLen(len(pkg)) <= fcv3CodeInlineSizeLimit {
+		result["zipFile"] = base64.StdEncoding.EncodeToString(pkg)
+		return result, func() {}, nil
+	}
+
+	bucketName, _ := jsonpath.Get("$[0].oss_bucket_name", code)
+	if bucketName == nil || bucketName == "" {
+		return nil, nil, fmt.Errorf("the package built from source_dir %s is %d bytes, which is above the inline limit of FC. Set code.oss_bucket_name to stage it through OSS", sourceDir, len(pkg))
+	}
+	objectName := fcv3CodeStagingPrefix + checksum + ".zip"
+	raw, err := s.client.WithOssBucketByName(fmt.Sprint(bucketName), func(bucket *oss.Bucket) (interface{}, error) {
+		return nil, bucket.PutObject(objectName, bytes.NewReader(pkg))
+	})
+	addDebug("PutObject", raw, map[string]string{"bucketName": fmt.Sprint(bucketName), "objectName": objectName})
+	if err != nil {
+		return nil, nil, WrapErrorf(err, DefaultErrorMsg, objectName, "PutObject", AliyunOssGoSdk)
+	}
+	result["ossBucketName"] = bucketName
+	result["ossObjectName"] = objectName
+	cleanup := func() {
+		_, err := s.client.WithOssBucketByName(fmt.Sprint(bucketName), func(bucket *oss.Bucket) (interface{}, error) {
+			return nil, bucket.DeleteObject(objectName)
+		})
+		if err != nil {
+			log.Printf("[WARN] deleting the staged code package oss://%s/%s failed: %v", bucketName, objectName, err)
+		}
+	}
+	return result, cleanup, nil
+}
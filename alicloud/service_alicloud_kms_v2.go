@@ -512,6 +512,56 @@ func (s *KmsServiceV2) KmsKeyStateRefreshFunc(id string, field string, failState
 	}
 }
 
+// DescribeKmsKeyMaterial describes the key that owns the imported material. A key that is
+// waiting for its material again, because the material was deleted or has expired, is not found.
+func (s *KmsServiceV2) DescribeKmsKeyMaterial(id string) (object map[string]interface{}, err error) {
+	object, err = s.DescribeKmsKey(id)
+	if err != nil {
+		return object, err
+	}
+	if object["KeyState"] == "PendingImport" {
+		log.Printf("[WARN] Removing Kms:KeyMaterial %s because the key is waiting for its material", id)
+		return object, WrapErrorf(NotFoundErr("Kms:KeyMaterial", id), NotFoundMsg, ProviderERROR)
+	}
+	return object, nil
+}
+
+// GetKmsParametersForImport returns the wrapping public key and the import token of an EXTERNAL key.
+// The import token is only valid for 24 hours, so it is fetched right before the material is imported.
+func (s *KmsServiceV2) GetKmsParametersForImport(keyId, wrappingAlgorithm, wrappingKeySpec string, timeout time.Duration) (object map[string]interface{}, err error) {
+	client := s.client
+	var response map[string]interface{}
+	action := "GetParametersForImport"
+	request := make(map[string]interface{})
+	query := make(map[string]interface{})
+	request["KeyId"] = keyId
+	request["WrappingAlgorithm"] = wrappingAlgorithm
+	request["WrappingKeySpec"] = wrappingKeySpec
+	wait := incrementalWait(3*time.Second, 5*time.Second)
+	err = resource.Retry(client.GetRetryTimeout(timeout), func() *resource.RetryError {
+		response, err = client.RpcPost("Kms", "2016-01-20", action, query, request, true)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	// The response carries the import token, so only the request is logged.
+	addDebug(action, nil, request)
+	if err != nil {
+		return object, WrapErrorf(err, DefaultErrorMsg, keyId, action, AlibabaCloudSdkGoERROR)
+	}
+	for _, field := range []string{"PublicKey", "ImportToken"} {
+		if v, ok := response[field].(string); !ok || v == "" {
+			return object, WrapErrorf(Error(GetNotFoundMessage("Kms:ParametersForImport", keyId)), FailedGetAttributeMsg, keyId, "$."+field, "")
+		}
+	}
+	return response, nil
+}
+
 func (s *KmsServiceV2) DescribeKmsKeyPolicy(id string) (object map[string]interface{}, err error) {
 	var response map[string]interface{}
 	action := "GetKeyPolicy"
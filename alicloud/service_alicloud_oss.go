@@ -1,11 +1,16 @@
 package alicloud
 
 import (
+	"fmt"
+	"log"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 )
 
 // OssService *connectivity.AliyunClient
@@ -102,3 +107,229 @@ func (s *OssService) DescribeOssBucketReplication(id string) (response string, e
 	response, _ = raw.(string)
 	return
 }
+
+// ossForceDestroyWorkers is the number of DeleteObjectVersions batches that are in flight at once
+// while a bucket is emptied.
+const ossForceDestroyWorkers = 8
+
+// EmptyOssBucket removes everything that keeps a bucket from being deleted: its LiveChannels, the
+// in-progress multipart uploads and every object version and delete marker. Versions are deleted in
+// batches of up to 1000 by concurrent workers while later pages are still being listed. Every call
+// goes through the provider's OSS client, so it is traced and serialized like any other OSS call. A
+// bucket under a WORM retention policy fails right away, because none of its objects can be deleted.
+func (s *OssService) EmptyOssBucket(bucketName string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	var requestInfo *oss.Client
+	raw, err := s.client.WithOssClient(func(ossClient *oss.Client) (interface{}, error) {
+		requestInfo = ossClient
+		return ossClient.GetBucketWorm(bucketName)
+	})
+	if err != nil {
+		if !IsExpectedErrors(err, []string{"NoSuchWORMConfiguration"}) {
+			return WrapErrorf(err, DefaultErrorMsg, bucketName, "GetBucketWorm", AliyunOssGoSdk)
+		}
+	} else {
+		addDebug("GetBucketWorm", raw, requestInfo, map[string]string{"bucketName": bucketName})
+		if worm, ok := raw.(oss.WormConfiguration); ok && (worm.State == "Locked" || worm.State == "InProgress") {
+			return WrapError(fmt.Errorf("the objects of bucket %s can not be deleted: they are protected by a WORM retention policy %s in state %s with a retention period of %d days. "+
+				"An InProgress policy can be removed with AbortBucketWorm, a Locked one keeps every object until its retention period expires", bucketName, worm.WormId, worm.State, worm.RetentionPeriodInDays))
+		}
+	}
+
+	withBucket := func(do func(*oss.Bucket) (interface{}, error)) (interface{}, error) {
+		return s.client.WithOssBucketByName(bucketName, do)
+	}
+	if err := s.deleteOssLiveChannels(bucketName, withBucket, deadline); err != nil {
+		return WrapError(err)
+	}
+	if err := s.abortOssMultipartUploads(bucketName, withBucket, deadline); err != nil {
+		return WrapError(err)
+	}
+	return WrapError(s.deleteOssObjectVersions(bucketName, withBucket, deadline))
+}
+
+// ossBucketCall runs an API call of a bucket, through connectivity.AliyunClient.WithOssBucketByName
+// outside of the tests.
+type ossBucketCall func(do func(*oss.Bucket) (interface{}, error)) (interface{}, error)
+
+func (s *OssService) deleteOssLiveChannels(bucketName string, withBucket ossBucketCall, deadline time.Time) error {
+	deleted := 0
+	marker := ""
+	for {
+		var result oss.ListLiveChannelResult
+		err := resource.Retry(time.Until(deadline), func() *resource.RetryError {
+			raw, err := withBucket(func(bucket *oss.Bucket) (interface{}, error) {
+				return bucket.ListLiveChannel(oss.Marker(marker), oss.MaxKeys(1000))
+			})
+			if err != nil {
+				if NeedRetry(err) {
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			result = raw.(oss.ListLiveChannelResult)
+			return nil
+		})
+		if err != nil {
+			return WrapErrorf(err, DefaultErrorMsg, bucketName, "ListLiveChannel", AliyunOssGoSdk)
+		}
+		for _, channel := range result.LiveChannel {
+			_, err := withBucket(func(bucket *oss.Bucket) (interface{}, error) {
+				return nil, bucket.DeleteLiveChannel(channel.Name)
+			})
+			if err != nil && !ossNotFoundError(err) {
+				return WrapErrorf(err, DefaultErrorMsg, bucketName, "DeleteLiveChannel", AliyunOssGoSdk)
+			}
+			deleted++
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextMarker
+	}
+	if deleted > 0 {
+		log.Printf("[INFO] Deleted %d LiveChannels of OSS bucket %s.", deleted, bucketName)
+	}
+	return nil
+}
+
+func (s *OssService) abortOssMultipartUploads(bucketName string, withBucket ossBucketCall, deadline time.Time) error {
+	aborted := 0
+	keyMarker, uploadIdMarker := "", ""
+	for {
+		var result oss.ListMultipartUploadResult
+		err := resource.Retry(time.Until(deadline), func() *resource.RetryError {
+			raw, err := withBucket(func(bucket *oss.Bucket) (interface{}, error) {
+				return bucket.ListMultipartUploads(oss.KeyMarker(keyMarker), oss.UploadIDMarker(uploadIdMarker), oss.MaxUploads(1000))
+			})
+			if err != nil {
+				if NeedRetry(err) {
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			result = raw.(oss.ListMultipartUploadResult)
+			return nil
+		})
+		if err != nil {
+			return WrapErrorf(err, DefaultErrorMsg, bucketName, "ListMultipartUploads", AliyunOssGoSdk)
+		}
+		for _, upload := range result.Uploads {
+			imur := oss.InitiateMultipartUploadResult{Bucket: bucketName, Key: upload.Key, UploadID: upload.UploadID}
+			_, err := withBucket(func(bucket *oss.Bucket) (interface{}, error) {
+				return nil, bucket.AbortMultipartUpload(imur)
+			})
+			if err != nil && !IsExpectedErrors(err, []string{"NoSuchUpload"}) {
+				return WrapErrorf(err, DefaultErrorMsg, bucketName, "AbortMultipartUpload", AliyunOssGoSdk)
+			}
+			aborted++
+		}
+		if !result.IsTruncated {
+			break
+		}
+		keyMarker, uploadIdMarker = result.NextKeyMarker, result.NextUploadIDMarker
+	}
+	if aborted > 0 {
+		log.Printf("[INFO] Aborted %d multipart uploads of OSS bucket %s.", aborted, bucketName)
+	}
+	return nil
+}
+
+// deleteOssObjectVersions lists all object versions and delete markers page by page and hands every
+// page to a pool of workers that delete it with DeleteObjectVersions. The first failure stops the
+// listing and is returned once the batches already in flight are done.
+func (s *OssService) deleteOssObjectVersions(bucketName string, withBucket ossBucketCall, deadline time.Time) error {
+	batches := make(chan []oss.DeleteObject, ossForceDestroyWorkers)
+	var deleted int64
+	var failure error
+	var failureOnce sync.Once
+	stop := make(chan struct{})
+	fail := func(err error) {
+		failureOnce.Do(func() {
+			failure = err
+			close(stop)
+		})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < ossForceDestroyWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				if err := deleteOssObjectVersionBatch(bucketName, withBucket, batch, deadline); err != nil {
+					fail(err)
+					continue
+				}
+				total := atomic.AddInt64(&deleted, int64(len(batch)))
+				log.Printf("[INFO] Deleted %d object versions and delete markers of OSS bucket %s.", total, bucketName)
+			}
+		}()
+	}
+
+	keyMarker, versionIdMarker := "", ""
+listing:
+	for {
+		var result oss.ListObjectVersionsResult
+		err := resource.Retry(time.Until(deadline), func() *resource.RetryError {
+			raw, err := withBucket(func(bucket *oss.Bucket) (interface{}, error) {
+				return bucket.ListObjectVersions(oss.KeyMarker(keyMarker), oss.VersionIdMarker(versionIdMarker), oss.MaxKeys(1000))
+			})
+			if err != nil {
+				if NeedRetry(err) {
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			result = raw.(oss.ListObjectVersionsResult)
+			return nil
+		})
+		if err != nil {
+			fail(WrapErrorf(err, DefaultErrorMsg, bucketName, "ListObjectVersions", AliyunOssGoSdk))
+			break
+		}
+		batch := make([]oss.DeleteObject, 0, len(result.ObjectVersions)+len(result.ObjectDeleteMarkers))
+		for _, marker := range result.ObjectDeleteMarkers {
+			batch = append(batch, oss.DeleteObject{Key: marker.Key, VersionId: marker.VersionId})
+		}
+		for _, version := range result.ObjectVersions {
+			batch = append(batch, oss.DeleteObject{Key: version.Key, VersionId: version.VersionId})
+		}
+		if len(batch) > 0 {
+			select {
+			case batches <- batch:
+			case <-stop:
+				break listing
+			}
+		}
+		if !result.IsTruncated {
+			break
+		}
+		keyMarker, versionIdMarker = result.NextKeyMarker, result.NextVersionIdMarker
+	}
+	close(batches)
+	wg.Wait()
+	return failure
+}
+
+func deleteOssObjectVersionBatch(bucketName string, withBucket ossBucketCall, batch []oss.DeleteObject, deadline time.Time) error {
+	err := resource.Retry(time.Until(deadline), func() *resource.RetryError {
+		_, err := withBucket(func(bucket *oss.Bucket) (interface{}, error) {
+			return bucket.DeleteObjectVersions(batch, oss.DeleteObjectsQuiet(true))
+		})
+		if err != nil {
+			if NeedRetry(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		if IsExpectedErrors(err, []string{"FileImmutable"}) {
+			return WrapError(fmt.Errorf("the objects of bucket %s can not be deleted: they are protected by a WORM retention policy: %v", bucketName, err))
+		}
+		return WrapErrorf(err, DefaultErrorMsg, bucketName, "DeleteObjectVersions", AliyunOssGoSdk)
+	}
+	return nil
+}
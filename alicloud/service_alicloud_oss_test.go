@@ -0,0 +1,122 @@
+package alicloud
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeOssVersionedBucket serves ListObjectVersions, DeleteObjectVersions, ListMultipartUploads and
+// AbortMultipartUpload for a single bucket, two keys per listing page.
+type fakeOssVersionedBucket struct {
+	sync.Mutex
+	versions  []string
+	deleted   map[string]bool
+	uploads   []string
+	aborted   map[string]bool
+	immutable bool
+}
+
+func (f *fakeOssVersionedBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodGet && query.Has("versions"):
+		start := 0
+		for i, key := range f.versions {
+			if key == query.Get("key-marker") {
+				start = i + 1
+			}
+		}
+		end := start + 2
+		truncated := end < len(f.versions)
+		if end > len(f.versions) {
+			end = len(f.versions)
+		}
+		body := "<ListVersionsResult><Name>bucket</Name>"
+		for _, key := range f.versions[start:end] {
+			body += fmt.Sprintf("<Version><Key>%s</Key><VersionId>v-%s</VersionId></Version>", key, key)
+		}
+		body += fmt.Sprintf("<IsTruncated>%t</IsTruncated>", truncated)
+		if truncated {
+			body += fmt.Sprintf("<NextKeyMarker>%s</NextKeyMarker><NextVersionIdMarker>v-%s</NextVersionIdMarker>", f.versions[end-1], f.versions[end-1])
+		}
+		w.Write([]byte(body + "</ListVersionsResult>"))
+	case r.Method == http.MethodPost && query.Has("delete"):
+		if f.immutable {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("<Error><Code>FileImmutable</Code><Message>This file is immutable.</Message></Error>"))
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		for _, key := range regexp.MustCompile(`<Key>([^<]+)</Key>`).FindAllStringSubmatch(string(body), -1) {
+			f.deleted[key[1]] = true
+		}
+		w.Write([]byte("<DeleteResult></DeleteResult>"))
+	case r.Method == http.MethodGet && query.Has("uploads"):
+		body := "<ListMultipartUploadsResult><Bucket>bucket</Bucket><IsTruncated>false</IsTruncated>"
+		for _, key := range f.uploads {
+			body += fmt.Sprintf("<Upload><Key>%s</Key><UploadId>u-%s</UploadId></Upload>", key, key)
+		}
+		w.Write([]byte(body + "</ListMultipartUploadsResult>"))
+	case r.Method == http.MethodDelete && query.Get("uploadId") != "":
+		f.aborted[query.Get("uploadId")] = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func newFakeOssBucket(t *testing.T, handler http.Handler) ossBucketCall {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	ossClient, err := oss.New(server.URL, "ak", "sk")
+	assert.Nil(t, err)
+	bucket, err := ossClient.Bucket("bucket")
+	assert.Nil(t, err)
+	return func(do func(*oss.Bucket) (interface{}, error)) (interface{}, error) {
+		return do(bucket)
+	}
+}
+
+func TestUnitOssServiceEmptyOssBucketContents(t *testing.T) {
+	fake := &fakeOssVersionedBucket{
+		versions: []string{"a", "b", "c", "d", "e"},
+		deleted:  map[string]bool{},
+		uploads:  []string{"big"},
+		aborted:  map[string]bool{},
+	}
+	bucket := newFakeOssBucket(t, fake)
+	s := &OssService{}
+	deadline := time.Now().Add(time.Minute)
+
+	assert.Nil(t, s.abortOssMultipartUploads("bucket", bucket, deadline))
+	assert.Equal(t, map[string]bool{"u-big": true}, fake.aborted)
+
+	assert.Nil(t, s.deleteOssObjectVersions("bucket", bucket, deadline))
+	assert.Equal(t, map[string]bool{"a": true, "b": true, "c": true, "d": true, "e": true}, fake.deleted)
+}
+
+func TestUnitOssServiceEmptyOssBucketImmutable(t *testing.T) {
+	fake := &fakeOssVersionedBucket{
+		versions:  []string{"a", "b", "c"},
+		deleted:   map[string]bool{},
+		immutable: true,
+	}
+	bucket := newFakeOssBucket(t, fake)
+	s := &OssService{}
+
+	err := s.deleteOssObjectVersions("bucket", bucket, time.Now().Add(time.Minute))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "WORM retention policy")
+	assert.Empty(t, fake.deleted)
+}
@@ -371,3 +371,51 @@ func (s *QuotasServiceV2) QuotasTemplateServiceStateRefreshFunc(id string, field
 }
 
 // DescribeQuotasTemplateService >>> Encapsulated.
+
+// DescribeQuotasProductQuota returns a quota of productCode in regionId. Quotas that are not split by region are
+// returned as well, when no quota has the region dimension.
+func (s *QuotasServiceV2) DescribeQuotasProductQuota(productCode, quotaActionCode, regionId string) (object map[string]interface{}, err error) {
+	client := s.client
+	var response map[string]interface{}
+	action := "ListProductQuotas"
+	id := fmt.Sprintf("%s:%s", productCode, quotaActionCode)
+	request := map[string]interface{}{
+		"ProductCode":     productCode,
+		"QuotaActionCode": quotaActionCode,
+		"Dimensions": []map[string]interface{}{
+			{"Key": "regionId", "Value": regionId},
+		},
+	}
+
+	for _, withRegion := range []bool{true, false} {
+		if !withRegion {
+			delete(request, "Dimensions")
+		}
+		wait := incrementalWait(3*time.Second, 5*time.Second)
+		err = resource.Retry(1*time.Minute, func() *resource.RetryError {
+			response, err = client.Do("quotas", rpcParam("POST", "2020-05-10", action), nil, request, nil, nil, false)
+			if err != nil {
+				if NeedRetry(err) {
+					wait()
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		})
+		addDebug(action, response, request)
+		if err != nil {
+			return object, WrapErrorf(err, DefaultErrorMsg, id, action, AlibabaCloudSdkGoERROR)
+		}
+		v, err := jsonpath.Get("$.Quotas", response)
+		if err != nil {
+			return object, WrapErrorf(err, FailedGetAttributeMsg, id, "$.Quotas", response)
+		}
+		for _, quota := range v.([]interface{}) {
+			if quotaMap, ok := quota.(map[string]interface{}); ok && fmt.Sprint(quotaMap["QuotaActionCode"]) == quotaActionCode {
+				return quotaMap, nil
+			}
+		}
+	}
+	return object, WrapErrorf(NotFoundErr("Quota", id), NotFoundMsg, response)
+}
@@ -2,6 +2,7 @@ package alicloud
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/PaesslerAG/jsonpath"
@@ -195,3 +196,109 @@ func (s *TagService) DescribeTagValue(id string) (object []interface{}, err erro
 
 	return values, nil
 }
+
+// DescribeTagEffectivePolicy returns the document of the tag policy in effect for targetId, or for the
+// current account when targetId is empty.
+func (s *TagService) DescribeTagEffectivePolicy(targetId string) (string, error) {
+	var response map[string]interface{}
+	var err error
+	client := s.client
+	action := "GetEffectivePolicy"
+	request := map[string]interface{}{
+		"RegionId": s.client.RegionId,
+	}
+	if targetId != "" {
+		request["TargetId"] = targetId
+	}
+	wait := incrementalWait(3*time.Second, 3*time.Second)
+	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+		response, err = client.RpcPost("Tag", "2018-08-28", action, nil, request, true)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug(action, response, request)
+	if err != nil {
+		return "", WrapErrorf(err, DefaultErrorMsg, targetId, action, AlibabaCloudSdkGoERROR)
+	}
+	if v, ok := response["EffectivePolicy"]; ok && v != nil {
+		return fmt.Sprint(v), nil
+	}
+	return "", nil
+}
+
+// ListTaggedResourceIds returns the IDs of the resources in the region of the client that carry all the given
+// tags, read from the ARNs ListTagResources returns.
+func (s *TagService) ListTaggedResourceIds(tags map[string]interface{}) (map[string]bool, error) {
+	var response map[string]interface{}
+	var err error
+	client := s.client
+	action := "ListTagResources"
+	tagsJson, err := convertMaptoJsonString(tags)
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	request := map[string]interface{}{
+		"RegionId": s.client.RegionId,
+		"Tags":     tagsJson,
+		"PageSize": PageSizeXLarge,
+	}
+	resourceIds := make(map[string]bool)
+	for {
+		wait := incrementalWait(3*time.Second, 3*time.Second)
+		err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+			response, err = client.RpcPost("Tag", "2018-08-28", action, nil, request, true)
+			if err != nil {
+				if NeedRetry(err) {
+					wait()
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		})
+		addDebug(action, response, request)
+		if err != nil {
+			return nil, WrapErrorf(err, DefaultErrorMsg, "TagResources", action, AlibabaCloudSdkGoERROR)
+		}
+		v, err := jsonpath.Get("$.TagResources", response)
+		if err != nil {
+			return nil, WrapErrorf(err, FailedGetAttributeMsg, "TagResources", "$.TagResources", response)
+		}
+		for _, item := range v.([]interface{}) {
+			tagResource, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if resourceId := tagResourceIdFromArn(fmt.Sprint(tagResource["ResourceARN"])); resourceId != "" {
+				resourceIds[resourceId] = true
+			}
+		}
+		if nextToken, ok := response["NextToken"].(string); ok && nextToken != "" {
+			request["NextToken"] = nextToken
+		} else {
+			break
+		}
+	}
+
+	return resourceIds, nil
+}
+
+// tagResourceIdFromArn returns the resource ID of an ARN such as acs:ecs:cn-hangzhou:123456789:instance/i-abc,
+// which is the part after the resource type.
+func tagResourceIdFromArn(arn string) string {
+	parts := strings.SplitN(arn, ":", 5)
+	if len(parts) < 5 {
+		return ""
+	}
+	resourcePart := parts[4]
+	if i := strings.Index(resourcePart, "/"); i >= 0 {
+		return resourcePart[i+1:]
+	}
+	return resourcePart
+}
@@ -492,6 +492,58 @@ func (s *VpcIpamServiceV2) VpcIpamIpamPoolAllocationStateRefreshFunc(id string,
 
 // DescribeVpcIpamIpamPoolAllocation >>> Encapsulated.
 
+// DescribeVpcIpamIpamPoolCidrs lists the CIDR blocks provisioned to an IPAM pool.
+func (s *VpcIpamServiceV2) DescribeVpcIpamIpamPoolCidrs(ipamPoolId string) (objects []map[string]interface{}, err error) {
+	return s.listVpcIpamIpamPoolItems("ListIpamPoolCidrs", ipamPoolId, "$.IpamPoolCidrs")
+}
+
+// DescribeVpcIpamIpamPoolAllocations lists the allocations of an IPAM pool, including custom reservations.
+func (s *VpcIpamServiceV2) DescribeVpcIpamIpamPoolAllocations(ipamPoolId string) (objects []map[string]interface{}, err error) {
+	return s.listVpcIpamIpamPoolItems("ListIpamPoolAllocations", ipamPoolId, "$.IpamPoolAllocations")
+}
+
+func (s *VpcIpamServiceV2) listVpcIpamIpamPoolItems(action string, ipamPoolId string, path string) (objects []map[string]interface{}, err error) {
+	client := s.client
+	var response map[string]interface{}
+	request := map[string]interface{}{
+		"RegionId":   client.RegionId,
+		"IpamPoolId": ipamPoolId,
+		"MaxResults": PageSizeLarge,
+	}
+	objects = make([]map[string]interface{}, 0)
+	for {
+		wait := incrementalWait(3*time.Second, 5*time.Second)
+		err = resource.Retry(1*time.Minute, func() *resource.RetryError {
+			response, err = client.RpcPost("VpcIpam", "2023-02-28", action, nil, request, true)
+			if err != nil {
+				if NeedRetry(err) {
+					wait()
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		})
+		addDebug(action, response, request)
+		if err != nil {
+			return objects, WrapErrorf(err, DefaultErrorMsg, ipamPoolId, action, AlibabaCloudSdkGoERROR)
+		}
+		v, _ := jsonpath.Get(path, response)
+		result, _ := v.([]interface{})
+		for _, item := range result {
+			if object, ok := item.(map[string]interface{}); ok {
+				objects = append(objects, object)
+			}
+		}
+		if nextToken, ok := response["NextToken"].(string); ok && nextToken != "" {
+			request["NextToken"] = nextToken
+		} else {
+			break
+		}
+	}
+	return objects, nil
+}
+
 // DescribeVpcIpamService <<< Encapsulated get interface for VpcIpam Service.
 
 func (s *VpcIpamServiceV2) DescribeVpcIpamService(id string) (object map[string]interface{}, err error) {
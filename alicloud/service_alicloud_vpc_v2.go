@@ -1018,6 +1018,140 @@ func (s *VpcServiceV2) VpcRouteTableStateRefreshFunc(id string, field string, fa
 
 // DescribeVpcRouteTable >>> Encapsulated.
 
+// DescribeVpcRouteTableRouteEntries lists every route entry of a route table, whatever its type or origin.
+// A non-empty destination, a CIDR block or a prefix list ID, only lists the routes to it.
+func (s *VpcServiceV2) DescribeVpcRouteTableRouteEntries(routeTableId, destination string) (objects []map[string]interface{}, err error) {
+	client := s.client
+	var response map[string]interface{}
+	action := "DescribeRouteEntryList"
+	request := map[string]interface{}{
+		"RegionId":     client.RegionId,
+		"RouteTableId": routeTableId,
+		"MaxResult":    PageSizeXLarge,
+	}
+	if destination != "" {
+		request["DestinationCidrBlock"] = destination
+	}
+	objects = make([]map[string]interface{}, 0)
+	for {
+		wait := incrementalWait(3*time.Second, 5*time.Second)
+		err = resource.Retry(1*time.Minute, func() *resource.RetryError {
+			response, err = client.RpcPost("Vpc", "2016-04-28", action, nil, request, true)
+			if err != nil {
+				if NeedRetry(err) {
+					wait()
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		})
+		addDebug(action, response, request)
+		if err != nil {
+			if IsExpectedErrors(err, []string{"InvalidRouteTable.NotFound"}) {
+				return objects, WrapErrorf(NotFoundErr("RouteTable", routeTableId), NotFoundMsg, response)
+			}
+			return objects, WrapErrorf(err, DefaultErrorMsg, routeTableId, action, AlibabaCloudSdkGoERROR)
+		}
+		v, err := jsonpath.Get("$.RouteEntrys.RouteEntry", response)
+		if err != nil {
+			return objects, WrapErrorf(err, FailedGetAttributeMsg, routeTableId, "$.RouteEntrys.RouteEntry", response)
+		}
+		for _, item := range v.([]interface{}) {
+			if entry, ok := item.(map[string]interface{}); ok {
+				objects = append(objects, entry)
+			}
+		}
+		if nextToken, ok := response["NextToken"].(string); ok && nextToken != "" {
+			request["NextToken"] = nextToken
+		} else {
+			break
+		}
+	}
+	return objects, nil
+}
+
+// VpcRouteTableRouteEntryStateRefreshFunc refreshes the custom route of a route table to a destination.
+// Unlike VpcRouteEntryStateRefreshFunc it takes the route table and the destination apart, as an IPv6
+// destination can not be told apart from a "<route table id>:<destination>" id.
+func (s *VpcServiceV2) VpcRouteTableRouteEntryStateRefreshFunc(routeTableId, destination string, field string, failStates []string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		objects, err := s.DescribeVpcRouteTableRouteEntries(routeTableId, destination)
+		if err != nil {
+			if NotFoundError(err) {
+				return nil, "", nil
+			}
+			return nil, "", WrapError(err)
+		}
+		var object map[string]interface{}
+		for _, entry := range objects {
+			if fmt.Sprint(entry["Type"]) == "Custom" && fmt.Sprint(entry["DestinationCidrBlock"]) == destination {
+				object = entry
+				break
+			}
+		}
+		if object == nil {
+			return nil, "", nil
+		}
+
+		v, _ := jsonpath.Get(field, object)
+		currentStatus := fmt.Sprint(v)
+		for _, failState := range failStates {
+			if currentStatus == failState {
+				return object, currentStatus, WrapError(Error(FailedToReachTargetStatus, currentStatus))
+			}
+		}
+		return object, currentStatus, nil
+	}
+}
+
+// DescribeVpcVpcVswitches lists every vSwitch of a VPC.
+func (s *VpcServiceV2) DescribeVpcVpcVswitches(vpcId string) (objects []map[string]interface{}, err error) {
+	client := s.client
+	var response map[string]interface{}
+	action := "DescribeVSwitches"
+	request := map[string]interface{}{
+		"RegionId":   client.RegionId,
+		"VpcId":      vpcId,
+		"PageSize":   PageSizeLarge,
+		"PageNumber": 1,
+	}
+	objects = make([]map[string]interface{}, 0)
+	for {
+		wait := incrementalWait(3*time.Second, 5*time.Second)
+		err = resource.Retry(1*time.Minute, func() *resource.RetryError {
+			response, err = client.RpcPost("Vpc", "2016-04-28", action, nil, request, true)
+			if err != nil {
+				if NeedRetry(err) {
+					wait()
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		})
+		addDebug(action, response, request)
+		if err != nil {
+			return objects, WrapErrorf(err, DefaultErrorMsg, vpcId, action, AlibabaCloudSdkGoERROR)
+		}
+		v, err := jsonpath.Get("$.VSwitches.VSwitch", response)
+		if err != nil {
+			return objects, WrapErrorf(err, FailedGetAttributeMsg, vpcId, "$.VSwitches.VSwitch", response)
+		}
+		result, _ := v.([]interface{})
+		for _, item := range result {
+			if vswitch, ok := item.(map[string]interface{}); ok {
+				objects = append(objects, vswitch)
+			}
+		}
+		if len(result) < PageSizeLarge {
+			break
+		}
+		request["PageNumber"] = request["PageNumber"].(int) + 1
+	}
+	return objects, nil
+}
+
 // DescribeVpcGatewayRouteTableAttachment <<< Encapsulated get interface for Vpc GatewayRouteTableAttachment.
 
 func (s *VpcServiceV2) DescribeVpcGatewayRouteTableAttachment(id string) (object map[string]interface{}, err error) {
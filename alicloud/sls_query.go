@@ -0,0 +1,484 @@
+package alicloud
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+)
+
+// slsQueryError reports a syntax error in an SLS query, at a 1-based position in the query.
+type slsQueryError struct {
+	Position int
+	Message  string
+}
+
+func (e *slsQueryError) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Position, e.Message)
+}
+
+type slsQueryToken struct {
+	kind  string // word, phrase, op, (, ), [, ], :, cmp
+	value string
+	pos   int
+}
+
+// parseSlsQuery checks the syntax of a "search | SQL" query and returns the fields it references. With standardSQL
+// the query is an SQL statement without a search part, as scheduled SQL runs it. Only the structure is checked: the
+// search part must be a well-formed boolean expression of terms and field conditions, and the analytic part must be
+// a balanced statement that starts with SELECT, WITH or SET.
+func parseSlsQuery(query string, standardSQL bool) (fields []string, err error) {
+	search, analytic, analyticOffset := query, "", -1
+	if standardSQL {
+		search, analytic, analyticOffset = "", query, 0
+	} else if i := slsQueryPipeIndex(query); i >= 0 {
+		search, analytic, analyticOffset = query[:i], query[i+1:], i+1
+	}
+
+	fieldSet := make(map[string]bool)
+	if strings.TrimSpace(search) != "" {
+		tokens, err := tokenizeSlsSearch(search)
+		if err != nil {
+			return nil, err
+		}
+		p := &slsSearchParser{tokens: tokens, end: len(search) + 1, fields: fieldSet}
+		if err := p.parse(); err != nil {
+			return nil, err
+		}
+	}
+	if analyticOffset >= 0 {
+		if err := checkSlsAnalytic(analytic, analyticOffset, fieldSet); err != nil {
+			return nil, err
+		}
+	}
+
+	for field := range fieldSet {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// slsQueryPipeIndex returns the index of the pipe that separates the search and the analytic part, ignoring pipes
+// inside quotes.
+func slsQueryPipeIndex(query string) int {
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '|':
+			return i
+		}
+	}
+	return -1
+}
+
+func tokenizeSlsSearch(search string) ([]slsQueryToken, error) {
+	tokens := make([]slsQueryToken, 0)
+	for i := 0; i < len(search); {
+		c := search[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '#' && i+1 < len(search) && search[i+1] == '"':
+			start := i
+			if c == '#' {
+				i++
+			}
+			j := i + 1
+			for ; j < len(search) && search[j] != '"'; j++ {
+				if search[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(search) {
+				return nil, &slsQueryError{start + 1, "unterminated quoted phrase"}
+			}
+			tokens = append(tokens, slsQueryToken{"phrase", search[start : j+1], start + 1})
+			i = j + 1
+		case strings.IndexByte("()[]:", c) >= 0:
+			tokens = append(tokens, slsQueryToken{string(c), string(c), i + 1})
+			i++
+		case c == '>' || c == '<' || c == '=':
+			j := i + 1
+			if c != '=' && j < len(search) && search[j] == '=' {
+				j++
+			}
+			tokens = append(tokens, slsQueryToken{"cmp", search[i:j], i + 1})
+			i = j
+		default:
+			j := i
+			for j < len(search) && strings.IndexByte(" \t\n\r()[]:\"<>=", search[j]) < 0 {
+				if search[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j > len(search) {
+				j = len(search)
+			}
+			word := search[i:j]
+			kind := "word"
+			switch strings.ToLower(word) {
+			case "and", "or", "not":
+				kind = "op"
+			}
+			tokens = append(tokens, slsQueryToken{kind, word, i + 1})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+type slsSearchParser struct {
+	tokens []slsQueryToken
+	i      int
+	end    int
+	fields map[string]bool
+}
+
+func (p *slsSearchParser) peek() *slsQueryToken {
+	if p.i < len(p.tokens) {
+		return &p.tokens[p.i]
+	}
+	return nil
+}
+
+func (p *slsSearchParser) pos() int {
+	if t := p.peek(); t != nil {
+		return t.pos
+	}
+	return p.end
+}
+
+func (p *slsSearchParser) isOp(op string) bool {
+	t := p.peek()
+	return t != nil && t.kind == "op" && strings.EqualFold(t.value, op)
+}
+
+func (p *slsSearchParser) parse() error {
+	if err := p.parseOr(); err != nil {
+		return err
+	}
+	if t := p.peek(); t != nil {
+		return &slsQueryError{t.pos, fmt.Sprintf("unexpected %q", t.value)}
+	}
+	return nil
+}
+
+func (p *slsSearchParser) parseOr() error {
+	if err := p.parseAnd(); err != nil {
+		return err
+	}
+	for p.isOp("or") {
+		p.i++
+		if err := p.parseAnd(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseAnd handles explicit "and" as well as terms that follow each other, which SLS also joins with "and".
+func (p *slsSearchParser) parseAnd() error {
+	if err := p.parseNot(); err != nil {
+		return err
+	}
+	for {
+		if p.isOp("and") {
+			p.i++
+		} else if t := p.peek(); t == nil || t.kind == ")" || p.isOp("or") {
+			return nil
+		}
+		if err := p.parseNot(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *slsSearchParser) parseNot() error {
+	for p.isOp("not") {
+		p.i++
+	}
+	return p.parsePrimary()
+}
+
+func (p *slsSearchParser) parsePrimary() error {
+	t := p.peek()
+	if t == nil {
+		return &slsQueryError{p.end, "expected a search term"}
+	}
+	switch t.kind {
+	case "(":
+		p.i++
+		if err := p.parseOr(); err != nil {
+			return err
+		}
+		if closing := p.peek(); closing == nil || closing.kind != ")" {
+			return &slsQueryError{t.pos, "unbalanced parenthesis"}
+		}
+		p.i++
+		return nil
+	case "phrase":
+		p.i++
+		// a quoted field name, as in "user agent": chrome
+		if next := p.peek(); next != nil && (next.kind == ":" || next.kind == "cmp") && strings.HasPrefix(t.value, `"`) {
+			return p.parseCondition(strings.ReplaceAll(t.value[1:len(t.value)-1], `\"`, `"`))
+		}
+		return nil
+	case "word":
+		p.i++
+		return p.parseCondition(t.value)
+	case "op":
+		return &slsQueryError{t.pos, fmt.Sprintf("%q needs a search term before it", t.value)}
+	}
+	return &slsQueryError{t.pos, fmt.Sprintf("unexpected %q", t.value)}
+}
+
+// parseCondition parses what follows a word or a quoted field name: nothing for a plain term, or a value for
+// "field: value", "field > value" and "field in [from to]".
+func (p *slsSearchParser) parseCondition(field string) error {
+	next := p.peek()
+	if next == nil {
+		return nil
+	}
+	switch {
+	case next.kind == ":":
+		p.i++
+		// tag fields carry a colon themselves, as in __tag__:__client_ip__: 10.0.0.1
+		if field == "__tag__" {
+			if name := p.peek(); name != nil && name.kind == "word" && p.i+1 < len(p.tokens) && p.tokens[p.i+1].kind == ":" {
+				field, p.i = field+":"+name.value, p.i+2
+			}
+		}
+		p.fields[field] = true
+		value := p.peek()
+		if value == nil {
+			return &slsQueryError{p.end, fmt.Sprintf("field %s needs a value after \":\"", field)}
+		}
+		switch value.kind {
+		case "word", "phrase":
+			p.i++
+			return nil
+		case "(":
+			return p.parsePrimary()
+		}
+		return &slsQueryError{value.pos, fmt.Sprintf("field %s needs a value after \":\", got %q", field, value.value)}
+	case next.kind == "cmp":
+		p.i++
+		p.fields[field] = true
+		if value := p.peek(); value == nil || value.kind != "word" {
+			return &slsQueryError{p.pos(), fmt.Sprintf("field %s needs a value after %q", field, next.value)}
+		}
+		p.i++
+		return nil
+	case next.kind == "word" && strings.EqualFold(next.value, "in"):
+		p.i++
+		p.fields[field] = true
+		open := p.peek()
+		if open == nil || open.kind != "[" && open.kind != "(" {
+			return &slsQueryError{p.pos(), fmt.Sprintf("field %s needs a range such as [1 10] after \"in\"", field)}
+		}
+		p.i++
+		for n := 0; n < 2; n++ {
+			if bound := p.peek(); bound == nil || bound.kind != "word" {
+				return &slsQueryError{p.pos(), fmt.Sprintf("the range of field %s needs two bounds", field)}
+			}
+			p.i++
+		}
+		if closing := p.peek(); closing == nil || closing.kind != "]" && closing.kind != ")" {
+			return &slsQueryError{open.pos, fmt.Sprintf("the range of field %s is not closed", field)}
+		}
+		p.i++
+	}
+	return nil
+}
+
+// slsQueryKeywords are the SQL keywords a column may follow. A quoted identifier after any other word, a literal, a
+// quoted identifier or a closing parenthesis ends an expression, so it is an alias as in count(*) "pv".
+var slsQueryKeywords = map[string]bool{
+	"select": true, "distinct": true, "all": true, "from": true, "where": true, "by": true, "having": true,
+	"and": true, "or": true, "not": true, "on": true, "using": true, "in": true, "is": true, "like": true,
+	"between": true, "case": true, "when": true, "then": true, "else": true, "join": true, "set": true,
+	"with": true, "over": true, "partition": true, "return": true, "exists": true, "any": true, "some": true,
+	"union": true, "intersect": true, "except": true, "limit": true, "offset": true,
+}
+
+// slsQueryEndsExpression reports whether the token before a quoted identifier ends an expression, which makes the
+// quoted identifier an alias. prev is the lower-cased previous word, or ")", "'" and "\"" for a closing
+// parenthesis, a string literal and a quoted identifier, and empty after a comma, an opening parenthesis or nothing.
+func slsQueryEndsExpression(prev string) bool {
+	switch {
+	case prev == "":
+		return false
+	case prev == ")" || prev == "'" || prev == "\"":
+		return true
+	case slsQueryKeywords[prev]:
+		return false
+	}
+	// a word that ends with an operator, as in "a"= or x+, is followed by an operand, and so is the dot that
+	// qualifies a column, as in "t"."status" or t."status"
+	return strings.IndexByte("=<>!+-*/%|.", prev[len(prev)-1]) < 0
+}
+
+// checkSlsAnalytic checks that the analytic part is a balanced statement, and collects the columns it references as
+// double-quoted identifiers, leaving out aliases, whether they are introduced with AS or follow an expression.
+func checkSlsAnalytic(analytic string, offset int, fields map[string]bool) error {
+	trimmed := strings.TrimSpace(analytic)
+	if trimmed == "" {
+		return &slsQueryError{offset + 1, "the analytic statement after \"|\" is empty"}
+	}
+	start := offset + strings.Index(analytic, trimmed) + 1
+	keyword := ""
+	if words := strings.FieldsFunc(trimmed, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '('
+	}); len(words) > 0 {
+		keyword = strings.ToLower(words[0])
+	}
+	if keyword != "select" && keyword != "with" && keyword != "set" {
+		return &slsQueryError{start, fmt.Sprintf("the analytic statement must start with SELECT, WITH or SET, got %q", keyword)}
+	}
+
+	aliases := make(map[string]bool)
+	identifiers := make(map[string]bool)
+	parens := make([]int, 0)
+	lastWord := ""
+	for i := 0; i < len(analytic); i++ {
+		c := analytic[i]
+		switch {
+		case c == '\'' || c == '"':
+			j := i + 1
+			for ; j < len(analytic); j++ {
+				if analytic[j] == c {
+					// a doubled quote is an escaped quote
+					if j+1 < len(analytic) && analytic[j+1] == c {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			if j >= len(analytic) {
+				kind := "string literal"
+				if c == '"' {
+					kind = "quoted identifier"
+				}
+				return &slsQueryError{offset + i + 1, "unterminated " + kind}
+			}
+			if c == '"' {
+				name := strings.ReplaceAll(analytic[i+1:j], `""`, `"`)
+				if j+1 < len(analytic) && analytic[j+1] == '.' {
+					// the table that qualifies a column, as "t" in "t"."status"
+				} else if lastWord == "as" || slsQueryEndsExpression(lastWord) {
+					aliases[name] = true
+				} else {
+					identifiers[name] = true
+				}
+			}
+			lastWord = string(c)
+			i = j
+		case c == '(':
+			parens = append(parens, offset+i+1)
+			lastWord = ""
+		case c == ')':
+			if len(parens) == 0 {
+				return &slsQueryError{offset + i + 1, "unbalanced parenthesis"}
+			}
+			parens = parens[:len(parens)-1]
+			lastWord = ")"
+		case c == '-' && i+1 < len(analytic) && analytic[i+1] == '-':
+			for i < len(analytic) && analytic[i] != '\n' {
+				i++
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		default:
+			j := i
+			for j < len(analytic) && strings.IndexByte(" \t\n\r()'\",;", analytic[j]) < 0 {
+				j++
+			}
+			if j == i {
+				lastWord = ""
+				continue
+			}
+			lastWord = strings.ToLower(analytic[i:j])
+			i = j - 1
+		}
+	}
+	if len(parens) > 0 {
+		return &slsQueryError{parens[len(parens)-1], "unbalanced parenthesis"}
+	}
+	for name := range identifiers {
+		if !aliases[name] {
+			fields[name] = true
+		}
+	}
+	return nil
+}
+
+// slsQueryUnindexedFields returns the referenced fields that have no field index. Built-in fields such as __time__
+// and tag fields are always available, and a JSON sub-field is covered by the index of its parent key.
+func slsQueryUnindexedFields(fields []string, indexKeys map[string]bool) []string {
+	missing := make([]string, 0)
+	for _, field := range fields {
+		if strings.HasPrefix(field, "__") {
+			continue
+		}
+		if indexKeys[field] {
+			continue
+		}
+		if i := strings.Index(field, "."); i > 0 && indexKeys[field[:i]] {
+			continue
+		}
+		missing = append(missing, field)
+	}
+	return missing
+}
+
+// describeSlsQueryIndexKeys returns the field index keys of a logstore. It returns nil when the index cannot be read,
+// for example because the logstore or its index is created by the same apply, and the field check is skipped then.
+func describeSlsQueryIndexKeys(client *connectivity.AliyunClient, project, logstore string) map[string]bool {
+	logService := LogService{client}
+	index, err := logService.DescribeLogStoreIndex(project + COLON_SEPARATED + logstore)
+	if err != nil {
+		log.Printf("[WARN] skip checking the fields of queries on %s/%s: %v", project, logstore, err)
+		return nil
+	}
+	keys := make(map[string]bool, len(index.Keys))
+	for key := range index.Keys {
+		keys[key] = true
+	}
+	return keys
+}
+
+// checkSlsQuery validates a query for the attribute at path, and checks its fields against the index of the logstore
+// when the index can be read. The plan can not see an alicloud_log_store_index of the same configuration that adds
+// the missing fields in this apply, so the error tells to apply the index first.
+func checkSlsQuery(client *connectivity.AliyunClient, path, query string, standardSQL bool, project, logstore string) error {
+	fields, err := parseSlsQuery(query, standardSQL)
+	if err != nil {
+		return fmt.Errorf("%s: invalid query: %s", path, err)
+	}
+	if client == nil || project == "" || logstore == "" || len(fields) == 0 {
+		return nil
+	}
+	indexKeys := describeSlsQueryIndexKeys(client, project, logstore)
+	if indexKeys == nil {
+		return nil
+	}
+	if missing := slsQueryUnindexedFields(fields, indexKeys); len(missing) > 0 {
+		return fmt.Errorf("%s: the query references fields without a field index in logstore %s of project %s: %s. "+
+			"Add them to the field index of the logstore, and apply the alicloud_log_store_index first when it adds them in this configuration",
+			path, logstore, project, strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package alicloud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitSlsQueryParse(t *testing.T) {
+	fields, err := parseSlsQuery(`status >= 500 and not request_method: GET | select "request_uri", count(*) as "pv" group by "request_uri"`, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"request_method", "request_uri", "status"}, fields)
+
+	fields, err = parseSlsQuery(`(error or "connection reset") and latency in [100 500) and __tag__:__hostname__: web-1`, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"__tag__:__hostname__", "latency"}, fields)
+
+	fields, err = parseSlsQuery(`* | select count(1) as cnt where "message" like '%a|b%' and "level" = 'it''s'`, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"level", "message"}, fields)
+
+	fields, err = parseSlsQuery(`select "host", avg("latency") from log group by "host"`, true)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"host", "latency"}, fields)
+
+	fields, err = parseSlsQuery(`* | select "host", count(*) "pv", avg("latency") "avg latency", 'x' "tag", case when "status" >= 500 then 1 else 0 end "error" from log group by "host" order by "pv" desc`, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"host", "latency", "status"}, fields)
+
+	fields, err = parseSlsQuery(`* | select "a" "b", "c"*"d", 1 "e" from log where "f"= 'x' and "g" like '%y'`, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "c", "d", "f", "g"}, fields)
+
+	fields, err = parseSlsQuery(`"user agent": chrome and "response time" > 100 and "not a field" | select "t"."status", t."host" from log as "t"`, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"host", "response time", "status", "user agent"}, fields)
+
+	fields, err = parseSlsQuery(`*`, false)
+	assert.Nil(t, err)
+	assert.Empty(t, fields)
+
+	for query, expected := range map[string]string{
+		`status: 200 and`:                          `position 16: expected a search term`,
+		`(status: 200 or status: 404`:              `position 1: unbalanced parenthesis`,
+		`status: 200)`:                             `position 12: unexpected ")"`,
+		`status:`:                                  `position 8: field status needs a value after ":"`,
+		`latency > `:                               `position 11: field latency needs a value after ">"`,
+		`latency in [100`:                          `position 16: the range of field latency needs two bounds`,
+		`"connection reset`:                        `position 1: unterminated quoted phrase`,
+		`"user agent":`:                            `position 14: field user agent needs a value after ":"`,
+		`or error`:                                 `position 1: "or" needs a search term before it`,
+		`* |`:                                      `position 4: the analytic statement after "|" is empty`,
+		`* | count(*)`:                             `position 5: the analytic statement must start with SELECT, WITH or SET, got "count"`,
+		`* | select count(* from log`:              `position 17: unbalanced parenthesis`,
+		`* | select count(*)) from log`:            `position 20: unbalanced parenthesis`,
+		`* | select 'abc from log`:                 `position 12: unterminated string literal`,
+		`* | select "status from log`:              `position 12: unterminated quoted identifier`,
+		`error | select * from log where "a`:       `position 33: unterminated quoted identifier`,
+		`* | select * from log -- comment (`:       ``,
+		`* | with t as (select 1) select * from t`: ``,
+	} {
+		_, err := parseSlsQuery(query, false)
+		if expected == "" {
+			assert.Nil(t, err, query)
+			continue
+		}
+		if assert.NotNil(t, err, query) {
+			assert.Equal(t, expected, err.Error(), query)
+		}
+	}
+
+	assert.Equal(t, []string{"latency", "request"}, slsQueryUnindexedFields(
+		[]string{"__time__", "__tag__:__hostname__", "body.level", "latency", "request", "status"},
+		map[string]bool{"body": true, "status": true}))
+}
@@ -0,0 +1,193 @@
+package alicloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// tagPolicyResourceTypes maps resources to the resource types tag policies name in enforced_for and
+// report_required_tag_for. Required keys are only checked for the resources listed here, while the case
+// of keys and their allowed values are checked for every taggable resource.
+var tagPolicyResourceTypes = map[string]string{
+	"alicloud_instance":              "ecs:instance",
+	"alicloud_ecs_instance_set":      "ecs:instance",
+	"alicloud_disk":                  "ecs:disk",
+	"alicloud_ecs_disk":              "ecs:disk",
+	"alicloud_image":                 "ecs:image",
+	"alicloud_ecs_snapshot":          "ecs:snapshot",
+	"alicloud_security_group":        "ecs:securitygroup",
+	"alicloud_ecs_network_interface": "ecs:eni",
+	"alicloud_ecs_key_pair":          "ecs:keypair",
+	"alicloud_vpc":                   "vpc:vpc",
+	"alicloud_vswitch":               "vpc:vswitch",
+	"alicloud_eip_address":           "vpc:eip",
+	"alicloud_nat_gateway":           "vpc:natgateway",
+	"alicloud_oss_bucket":            "oss:bucket",
+	"alicloud_db_instance":           "rds:dbinstance",
+	"alicloud_kvstore_instance":      "kvstore:instance",
+	"alicloud_slb_load_balancer":     "slb:instance",
+	"alicloud_alb_load_balancer":     "alb:loadbalancer",
+	"alicloud_nlb_load_balancer":     "nlb:loadbalancer",
+	"alicloud_kms_key":               "kms:key",
+	"alicloud_cs_managed_kubernetes": "cs:cluster",
+}
+
+// tagPolicyRule is the part of a tag policy that governs one tag key.
+type tagPolicyRule struct {
+	// Key is the key as the policy spells it. A tag whose key only matches it case-insensitively violates the policy.
+	Key string
+	// Values are the allowed values, a trailing * matching any suffix. Empty means any value.
+	Values []string
+	// RequiredFor are the resource types the key is required on.
+	RequiredFor []string
+}
+
+type effectiveTagPolicy struct {
+	Rules []tagPolicyRule
+}
+
+type effectiveTagPolicyEntry struct {
+	once   sync.Once
+	policy *effectiveTagPolicy
+	err    error
+}
+
+// effectiveTagPolicies caches the effective tag policy per configured provider, so a run fetches it once.
+var effectiveTagPolicies sync.Map
+
+func getEffectiveTagPolicy(client *connectivity.AliyunClient) (*effectiveTagPolicy, error) {
+	raw, _ := effectiveTagPolicies.LoadOrStore(client, &effectiveTagPolicyEntry{})
+	entry := raw.(*effectiveTagPolicyEntry)
+	entry.once.Do(func() {
+		tagService := TagService{client}
+		content, err := tagService.DescribeTagEffectivePolicy(client.Features.TagPolicy.TargetId)
+		if err != nil {
+			entry.err = err
+			return
+		}
+		entry.policy, entry.err = parseEffectiveTagPolicy(content)
+	})
+	return entry.policy, entry.err
+}
+
+// parseEffectiveTagPolicy reads the tags section of a tag policy document. Effective policies carry plain
+// values, while attached policies wrap them in inheritance operators such as @@assign, so both are accepted.
+func parseEffectiveTagPolicy(content string) (*effectiveTagPolicy, error) {
+	policy := &effectiveTagPolicy{}
+	if strings.TrimSpace(content) == "" {
+		return policy, nil
+	}
+	var document struct {
+		Tags map[string]map[string]interface{} `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(content), &document); err != nil {
+		return nil, fmt.Errorf("parsing the effective tag policy %s failed: %v", content, err)
+	}
+	names := make([]string, 0, len(document.Tags))
+	for name := range document.Tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		item := document.Tags[name]
+		rule := tagPolicyRule{Key: name}
+		if keys := tagPolicyValues(item["tag_key"]); len(keys) > 0 {
+			rule.Key = keys[0]
+		}
+		rule.Values = tagPolicyValues(item["tag_value"])
+		rule.RequiredFor = tagPolicyValues(item["report_required_tag_for"])
+		policy.Rules = append(policy.Rules, rule)
+	}
+	return policy, nil
+}
+
+func tagPolicyValues(v interface{}) []string {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		return tagPolicyValues(value["@@assign"])
+	case []interface{}:
+		result := make([]string, 0, len(value))
+		for _, item := range value {
+			result = append(result, fmt.Sprint(item))
+		}
+		return result
+	case string:
+		return []string{value}
+	}
+	return nil
+}
+
+// Violations lists the ways tags break the policy for a resource of resourceType, sorted by key.
+func (p *effectiveTagPolicy) Violations(resourceType string, tags map[string]interface{}) []string {
+	violations := make([]string, 0)
+	for _, rule := range p.Rules {
+		matched := false
+		for key, value := range tags {
+			if !strings.EqualFold(key, rule.Key) {
+				continue
+			}
+			matched = true
+			if key != rule.Key {
+				violations = append(violations, fmt.Sprintf("tag key %q must be written %q", key, rule.Key))
+				continue
+			}
+			if len(rule.Values) > 0 && !tagPolicyValueAllowed(rule.Values, fmt.Sprint(value)) {
+				violations = append(violations, fmt.Sprintf("tag %q has the value %q, which is not one of %s", key, value, strings.Join(rule.Values, ", ")))
+			}
+		}
+		if !matched && resourceType != "" {
+			for _, requiredFor := range rule.RequiredFor {
+				if requiredFor == resourceType || requiredFor == strings.Split(resourceType, ":")[0]+":*" {
+					violations = append(violations, fmt.Sprintf("tag %q is required", rule.Key))
+					break
+				}
+			}
+		}
+	}
+	sort.Strings(violations)
+	return violations
+}
+
+func tagPolicyValueAllowed(allowed []string, value string) bool {
+	for _, v := range allowed {
+		if v == value || (strings.HasSuffix(v, "*") && strings.HasPrefix(value, strings.TrimSuffix(v, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// tagPolicyCustomizeDiff wraps the CustomizeDiff of a taggable resource to validate its planned tags when
+// features.tag_policy.enforce is set. Existing resources are only checked when their tags change, so a
+// policy attached later does not block unrelated changes.
+func tagPolicyCustomizeDiff(resourceName string, customizeDiff schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return func(diff *schema.ResourceDiff, meta interface{}) error {
+		if customizeDiff != nil {
+			if err := customizeDiff(diff, meta); err != nil {
+				return err
+			}
+		}
+		client, ok := meta.(*connectivity.AliyunClient)
+		if !ok || client == nil || !client.Features.TagPolicy.Enforce {
+			return nil
+		}
+		if (diff.Id() != "" && !diff.HasChange("tags")) || !diff.NewValueKnown("tags") {
+			return nil
+		}
+		policy, err := getEffectiveTagPolicy(client)
+		if err != nil {
+			return WrapError(err)
+		}
+		tags, _ := diff.Get("tags").(map[string]interface{})
+		if violations := policy.Violations(tagPolicyResourceTypes[resourceName], tags); len(violations) > 0 {
+			return fmt.Errorf("the tags of %s do not comply with the tag policy in effect: %s", resourceName, strings.Join(violations, "; "))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,42 @@
+package alicloud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitTagPolicyParse(t *testing.T) {
+	policy, err := parseEffectiveTagPolicy(`{"tags":{"costcenter":{"tag_key":"CostCenter","tag_value":["Beijing","Shanghai*"],"report_required_tag_for":["ecs:instance"]},"env":{"tag_key":{"@@assign":"Env"},"tag_value":{"@@assign":["prod","test"]}}}}`)
+	assert.Nil(t, err)
+	assert.Equal(t, []tagPolicyRule{
+		{Key: "CostCenter", Values: []string{"Beijing", "Shanghai*"}, RequiredFor: []string{"ecs:instance"}},
+		{Key: "Env", Values: []string{"prod", "test"}},
+	}, policy.Rules)
+
+	policy, err = parseEffectiveTagPolicy("")
+	assert.Nil(t, err)
+	assert.Empty(t, policy.Rules)
+
+	_, err = parseEffectiveTagPolicy("{")
+	assert.NotNil(t, err)
+}
+
+func TestUnitTagPolicyViolations(t *testing.T) {
+	policy := &effectiveTagPolicy{Rules: []tagPolicyRule{
+		{Key: "CostCenter", Values: []string{"Beijing", "Shanghai*"}, RequiredFor: []string{"ecs:instance", "oss:*"}},
+		{Key: "Env"},
+	}}
+
+	assert.Empty(t, policy.Violations("ecs:instance", map[string]interface{}{"CostCenter": "Beijing", "Owner": "ops"}))
+	assert.Empty(t, policy.Violations("ecs:instance", map[string]interface{}{"CostCenter": "Shanghai-Pudong"}))
+	assert.Empty(t, policy.Violations("vpc:vpc", map[string]interface{}{}))
+	assert.Empty(t, policy.Violations("", map[string]interface{}{"Env": "anything"}))
+
+	assert.Equal(t, []string{`tag "CostCenter" is required`}, policy.Violations("ecs:instance", map[string]interface{}{"Env": "prod"}))
+	assert.Equal(t, []string{`tag "CostCenter" is required`}, policy.Violations("oss:bucket", nil))
+	assert.Equal(t, []string{`tag "CostCenter" has the value "Hangzhou", which is not one of Beijing, Shanghai*`},
+		policy.Violations("vpc:vpc", map[string]interface{}{"CostCenter": "Hangzhou"}))
+	assert.Equal(t, []string{`tag key "costcenter" must be written "CostCenter"`, `tag key "env" must be written "Env"`},
+		policy.Violations("ecs:instance", map[string]interface{}{"costcenter": "Beijing", "env": "prod"}))
+}
@@ -0,0 +1,78 @@
+package alicloud
+
+import (
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// traceWait traces the wait loop polled by refresh when client is the handle of a traced operation. client may be
+// nil for the wait loops that do not poll through the provider's client.
+func traceWait(client *connectivity.AliyunClient, target []string, refresh resource.StateRefreshFunc) resource.StateRefreshFunc {
+	if client == nil {
+		return refresh
+	}
+	return client.TraceWait(target, refresh)
+}
+
+// traceOperation runs an operation of a resource or data source with a handle of the client, whose API calls and wait
+// loops are traced as children of the operation's span.
+func traceOperation(name, operation string, d *schema.ResourceData, meta interface{}, f func(interface{}) error) error {
+	client, ok := meta.(*connectivity.AliyunClient)
+	if !ok || client == nil || !connectivity.TracingEnabled() {
+		return f(meta)
+	}
+	attributes := map[string]string{
+		"terraform.resource.type": name,
+		"terraform.operation":     operation,
+	}
+	if d.Id() != "" {
+		attributes["terraform.resource.id"] = d.Id()
+	}
+	handle, end := client.StartTrace(name+"."+operation, attributes)
+	err := f(handle)
+	end(err)
+	return err
+}
+
+// tracedResource traces every operation of a resource or data source when traces are exported. It runs inside the
+// region wrapper, so the operations are traced with the client of their region.
+func tracedResource(name string, r *schema.Resource, isDataSource bool) {
+	wrap := func(operation string, f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+		if f == nil {
+			return nil
+		}
+		return func(d *schema.ResourceData, meta interface{}) error {
+			return traceOperation(name, operation, d, meta, func(client interface{}) error {
+				return f(d, client)
+			})
+		}
+	}
+	r.Read = wrap("read", r.Read)
+	if isDataSource {
+		return
+	}
+	r.Create = wrap("create", r.Create)
+	r.Update = wrap("update", r.Update)
+	r.Delete = wrap("delete", r.Delete)
+	if r.Exists != nil {
+		exists := r.Exists
+		r.Exists = func(d *schema.ResourceData, meta interface{}) (found bool, err error) {
+			err = traceOperation(name, "exists", d, meta, func(client interface{}) error {
+				found, err = exists(d, client)
+				return err
+			})
+			return found, err
+		}
+	}
+	if r.Importer != nil && r.Importer.State != nil {
+		state := r.Importer.State
+		r.Importer.State = func(d *schema.ResourceData, meta interface{}) (results []*schema.ResourceData, err error) {
+			err = traceOperation(name, "import", d, meta, func(client interface{}) error {
+				results, err = state(d, client)
+				return err
+			})
+			return results, err
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package alicloud
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitTracedResource(t *testing.T) {
+	// without an exporter, operations run with the client they are given
+	client := &connectivity.AliyunClient{RegionId: "cn-hangzhou"}
+	var got interface{}
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			got = meta
+			return nil
+		},
+	}
+	tracedResource("alicloud_test", r, true)
+	assert.Nil(t, r.Create)
+	assert.Nil(t, r.Read(r.TestResourceData(), client))
+	assert.Same(t, client, got)
+}
+
+func TestUnitBuildTracedStateConf(t *testing.T) {
+	polls := 0
+	refresh := func() (interface{}, string, error) {
+		polls++
+		return "object", "Running", nil
+	}
+	// an untraced client polls as BuildStateConf does
+	conf := BuildTracedStateConf(&connectivity.AliyunClient{RegionId: "cn-hangzhou"}, []string{"Creating"}, []string{"Running"}, time.Minute, time.Second, refresh)
+	plain := BuildStateConf([]string{"Creating"}, []string{"Running"}, time.Minute, time.Second, refresh)
+	assert.Equal(t, plain.Pending, conf.Pending)
+	assert.Equal(t, plain.Target, conf.Target)
+	assert.Equal(t, plain.Timeout, conf.Timeout)
+	assert.Equal(t, plain.Delay, conf.Delay)
+	assert.Equal(t, plain.MinTimeout, conf.MinTimeout)
+	object, state, err := conf.Refresh()
+	assert.Nil(t, err)
+	assert.Equal(t, "object", object)
+	assert.Equal(t, "Running", state)
+	assert.Equal(t, 1, polls)
+}
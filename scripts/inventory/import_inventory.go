@@ -0,0 +1,260 @@
+// import_inventory lists the resources of a live account and prints a Terraform 1.5 import block
+// for each of them. It drives the provider's own list data sources, so it pages, retries and
+// authenticates exactly like `alicloud_vpcs`, `alicloud_instances` and friends do, and reads the
+// same ALICLOUD_* / ALIBABA_CLOUD_* environment variables as the provider block.
+//
+// Usage:
+//
+//	go run scripts/inventory/import_inventory.go -region cn-hangzhou -tags Env=prod,Team=web > import.tf
+//	terraform plan -generate-config-out=generated.tf
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aliyun/terraform-provider-alicloud/alicloud"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	log "github.com/sirupsen/logrus"
+)
+
+func init() {
+	customFormatter := new(log.TextFormatter)
+	customFormatter.FullTimestamp = true
+	customFormatter.TimestampFormat = "2006-01-02 15:04:05"
+	log.SetFormatter(customFormatter)
+	// stdout carries the generated blocks
+	log.SetOutput(os.Stderr)
+	log.SetLevel(log.InfoLevel)
+}
+
+var (
+	region          = flag.String("region", "", "the region to enumerate, defaults to the provider's region environment variables")
+	tags            = flag.String("tags", "", "only include resources with all of these tags, written as key1=value1,key2=value2")
+	resourceGroupId = flag.String("resource-group-id", "", "only include resources in this resource group")
+	resourceTypes   = flag.String("types", "", "comma separated resource types to enumerate, defaults to all supported types")
+	outputFile      = flag.String("out", "", "the file to write the import blocks to, defaults to stdout")
+)
+
+// inventorySource maps a resource type to the list data source that enumerates it. Every data
+// source used here exports parallel `ids` and `names` lists.
+type inventorySource struct {
+	ResourceType string
+	DataSource   string
+}
+
+var inventorySources = []inventorySource{
+	{"alicloud_vpc", "alicloud_vpcs"},
+	{"alicloud_vswitch", "alicloud_vswitches"},
+	{"alicloud_security_group", "alicloud_security_groups"},
+	{"alicloud_instance", "alicloud_instances"},
+	{"alicloud_slb_load_balancer", "alicloud_slb_load_balancers"},
+	{"alicloud_alb_load_balancer", "alicloud_alb_load_balancers"},
+	{"alicloud_nlb_load_balancer", "alicloud_nlb_load_balancers"},
+	{"alicloud_db_instance", "alicloud_db_instances"},
+}
+
+// inventoryItem is one enumerated resource.
+type inventoryItem struct {
+	Id   string
+	Name string
+}
+
+func main() {
+	flag.Parse()
+
+	tagFilter, err := parseTagFilter(*tags)
+	if err != nil {
+		log.Fatalf("parsing -tags failed: %v", err)
+	}
+	sources, err := selectSources(*resourceTypes)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	provider := alicloud.Provider().(*schema.Provider)
+	providerConfig := map[string]interface{}{}
+	if *region != "" {
+		providerConfig["region"] = *region
+	}
+	if err := provider.Configure(terraform.NewResourceConfigRaw(providerConfig)); err != nil {
+		log.Fatalf("configuring the provider failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	exitCode := 0
+	for _, source := range sources {
+		items, err := listInventory(provider, source, tagFilter, *resourceGroupId)
+		if err != nil {
+			log.Errorf("listing %s failed: %v", source.ResourceType, err)
+			exitCode = 1
+			continue
+		}
+		log.Infof("found %d %s", len(items), source.ResourceType)
+		buf.WriteString(renderImportBlocks(source.ResourceType, items))
+	}
+
+	if *outputFile == "" {
+		os.Stdout.Write(buf.Bytes())
+	} else if err := os.WriteFile(*outputFile, buf.Bytes(), 0644); err != nil {
+		log.Fatalf("writing %s failed: %v", *outputFile, err)
+	}
+	os.Exit(exitCode)
+}
+
+func selectSources(types string) ([]inventorySource, error) {
+	if strings.TrimSpace(types) == "" {
+		return inventorySources, nil
+	}
+	known := make(map[string]inventorySource, len(inventorySources))
+	for _, source := range inventorySources {
+		known[source.ResourceType] = source
+	}
+	var result []inventorySource
+	for _, t := range strings.Split(types, ",") {
+		t = strings.TrimSpace(t)
+		source, ok := known[t]
+		if !ok {
+			return nil, fmt.Errorf("%s is not supported, supported types are: %s", t, supportedTypes())
+		}
+		result = append(result, source)
+	}
+	return result, nil
+}
+
+func supportedTypes() string {
+	var types []string
+	for _, source := range inventorySources {
+		types = append(types, source.ResourceType)
+	}
+	return strings.Join(types, ", ")
+}
+
+func parseTagFilter(raw string) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	if strings.TrimSpace(raw) == "" {
+		return result, nil
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			return nil, fmt.Errorf("invalid tag %q, expected key=value", pair)
+		}
+		result[key] = strings.TrimSpace(parts[1])
+	}
+	return result, nil
+}
+
+// listInventory reads the data source of a resource type with the requested filters. A filter the
+// data source does not support fails the type rather than silently returning everything.
+func listInventory(provider *schema.Provider, source inventorySource, tagFilter map[string]interface{}, resourceGroupId string) ([]inventoryItem, error) {
+	dataSource, ok := provider.DataSourcesMap[source.DataSource]
+	if !ok {
+		return nil, fmt.Errorf("data source %s is not registered", source.DataSource)
+	}
+	d := dataSource.Data(nil)
+	if len(tagFilter) > 0 {
+		if _, ok := dataSource.Schema["tags"]; !ok {
+			return nil, fmt.Errorf("%s can not filter by tags", source.DataSource)
+		}
+		if err := d.Set("tags", tagFilter); err != nil {
+			return nil, err
+		}
+	}
+	if resourceGroupId != "" {
+		if _, ok := dataSource.Schema["resource_group_id"]; !ok {
+			return nil, fmt.Errorf("%s can not filter by resource group", source.DataSource)
+		}
+		if err := d.Set("resource_group_id", resourceGroupId); err != nil {
+			return nil, err
+		}
+	}
+	if err := dataSource.Read(d, provider.Meta()); err != nil {
+		return nil, err
+	}
+
+	ids := d.Get("ids").([]interface{})
+	var names []interface{}
+	if v, ok := d.GetOk("names"); ok {
+		names = v.([]interface{})
+	}
+	items := make([]inventoryItem, 0, len(ids))
+	for i, id := range ids {
+		item := inventoryItem{Id: fmt.Sprint(id)}
+		if len(names) == len(ids) && names[i] != nil {
+			item.Name = fmt.Sprint(names[i])
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// renderImportBlocks writes one import block per item. Blocks are sorted by id and labelled by
+// resourceLabels, so running the command twice against the same account gives the same file.
+func renderImportBlocks(resourceType string, items []inventoryItem) string {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Id < items[j].Id
+	})
+	labels := resourceLabels(items)
+	var buf bytes.Buffer
+	for i, item := range items {
+		buf.WriteString(fmt.Sprintf(`import {
+  id = %q
+  to = %s.%s
+}
+
+`, item.Id, resourceType, labels[i]))
+	}
+	return buf.String()
+}
+
+// resourceLabels derives a Terraform resource name from each item's name, falling back to its id.
+// Names shared by several items get the item's id appended so every label stays unique.
+func resourceLabels(items []inventoryItem) []string {
+	counts := map[string]int{}
+	base := make([]string, len(items))
+	for i, item := range items {
+		base[i] = sanitizeLabel(item.Name)
+		if base[i] == "" {
+			base[i] = sanitizeLabel(item.Id)
+		}
+		counts[base[i]]++
+	}
+	labels := make([]string, len(items))
+	for i, item := range items {
+		labels[i] = base[i]
+		if counts[base[i]] > 1 && base[i] != sanitizeLabel(item.Id) {
+			labels[i] = base[i] + "_" + sanitizeLabel(item.Id)
+		}
+	}
+	return labels
+}
+
+// sanitizeLabel turns s into a valid Terraform identifier: lower case letters, digits and
+// underscores, not starting with a digit.
+func sanitizeLabel(s string) string {
+	var b strings.Builder
+	lastUnderscore := false
+	for _, ch := range strings.ToLower(s) {
+		if (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') {
+			b.WriteRune(ch)
+			lastUnderscore = false
+			continue
+		}
+		if !lastUnderscore && b.Len() > 0 {
+			b.WriteByte('_')
+			lastUnderscore = true
+		}
+	}
+	label := strings.TrimSuffix(b.String(), "_")
+	if label != "" && label[0] >= '0' && label[0] <= '9' {
+		label = "r_" + label
+	}
+	return label
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeLabel(t *testing.T) {
+	assert.Equal(t, "web_prod", sanitizeLabel("Web-Prod"))
+	assert.Equal(t, "my_vpc", sanitizeLabel("  my..vpc  "))
+	assert.Equal(t, "r_01_vpc", sanitizeLabel("01 vpc"))
+	assert.Equal(t, "vpc_abc123", sanitizeLabel("vpc-abc123"))
+	assert.Equal(t, "", sanitizeLabel("测试"))
+}
+
+func TestResourceLabels(t *testing.T) {
+	items := []inventoryItem{
+		{Id: "vpc-1", Name: "web"},
+		{Id: "vpc-2", Name: "web"},
+		{Id: "vpc-3", Name: "db"},
+		{Id: "vpc-4"},
+	}
+	assert.Equal(t, []string{"web_vpc_1", "web_vpc_2", "db", "vpc_4"}, resourceLabels(items))
+}
+
+func TestRenderImportBlocks(t *testing.T) {
+	got := renderImportBlocks("alicloud_vpc", []inventoryItem{
+		{Id: "vpc-2", Name: "b"},
+		{Id: "vpc-1", Name: "a"},
+	})
+	assert.Equal(t, `import {
+  id = "vpc-1"
+  to = alicloud_vpc.a
+}
+
+import {
+  id = "vpc-2"
+  to = alicloud_vpc.b
+}
+
+`, got)
+}
+
+func TestParseTagFilter(t *testing.T) {
+	tags, err := parseTagFilter("Env=prod, Team=web")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"Env": "prod", "Team": "web"}, tags)
+
+	_, err = parseTagFilter("Env")
+	assert.Error(t, err)
+}